@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'react';
-import { Settings, Save, TestTube, AlertCircle, CheckCircle } from 'lucide-react';
+import { Settings, Save, TestTube, AlertCircle, CheckCircle, Radio } from 'lucide-react';
 import Layout from '../components/Layout';
 
 interface AWSSettings {
@@ -19,6 +19,22 @@ interface TimezoneSettings {
   timezone: string;
 }
 
+interface SNSSubscription {
+  topic_arn: string;
+  subscription_arn: string;
+  status: 'pending' | 'confirmed' | 'unsubscribed' | 'failed';
+  last_error?: string;
+  confirmed_at?: string;
+  last_message_at?: string;
+}
+
+const subscriptionStatusStyles: Record<SNSSubscription['status'], string> = {
+  confirmed: 'bg-green-100 text-green-800',
+  pending: 'bg-yellow-100 text-yellow-800',
+  unsubscribed: 'bg-gray-100 text-gray-800',
+  failed: 'bg-red-100 text-red-800'
+};
+
 const SettingsPage = () => {
   const [settings, setSettings] = useState<AWSSettings>({
     enabled: false,
@@ -34,6 +50,7 @@ const SettingsPage = () => {
   const [timezoneSettings, setTimezoneSettings] = useState<TimezoneSettings>({
     timezone: 'Asia/Jakarta'
   });
+  const [subscriptions, setSubscriptions] = useState<SNSSubscription[]>([]);
   const [initialLoading, setInitialLoading] = useState(true);
   const [loading, setLoading] = useState(false);
   const [testing, setTesting] = useState(false);
@@ -78,6 +95,16 @@ const SettingsPage = () => {
         const timezoneData = await timezoneResponse.json();
         setTimezoneSettings(timezoneData);
       }
+
+      // Load SNS subscription status
+      const subscriptionsResponse = await fetch('/api/settings/sns/subscriptions', {
+        headers: { 'Authorization': `Bearer ${localStorage.getItem('token')}` }
+      });
+
+      if (subscriptionsResponse.ok) {
+        const subscriptionsData = await subscriptionsResponse.json();
+        setSubscriptions(subscriptionsData.subscriptions || []);
+      }
     } catch (error) {
       console.error('Failed to load settings:', error);
       if (showLoading) {
@@ -239,6 +266,56 @@ const SettingsPage = () => {
           </div>
         )}
 
+        {/* SNS Subscriptions */}
+        <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
+          <div className="flex items-center mb-6">
+            <Radio className="w-6 h-6 text-orange-600 mr-3" />
+            <h2 className="text-xl font-semibold">SNS Subscriptions</h2>
+          </div>
+
+          {subscriptions.length === 0 ? (
+            <p className="text-sm text-gray-500">
+              No SNS topics have contacted this endpoint yet. Subscribe <code>/sns/ses</code> to your SES topic and the subscription will be confirmed automatically.
+            </p>
+          ) : (
+            <div className="overflow-x-auto">
+              <table className="min-w-full divide-y divide-gray-200">
+                <thead className="bg-gray-50">
+                  <tr>
+                    <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase">Topic</th>
+                    <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase">Status</th>
+                    <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase">Confirmed</th>
+                    <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase">Last Message</th>
+                  </tr>
+                </thead>
+                <tbody className="divide-y divide-gray-200">
+                  {subscriptions.map((subscription) => (
+                    <tr key={subscription.topic_arn}>
+                      <td className="px-4 py-2 text-sm text-gray-900 break-all">
+                        {subscription.topic_arn}
+                        {subscription.last_error && (
+                          <p className="text-xs text-red-600 mt-1">{subscription.last_error}</p>
+                        )}
+                      </td>
+                      <td className="px-4 py-2 text-sm">
+                        <span className={`px-2 py-1 rounded-full text-xs font-medium ${subscriptionStatusStyles[subscription.status]}`}>
+                          {subscription.status}
+                        </span>
+                      </td>
+                      <td className="px-4 py-2 text-sm text-gray-600">
+                        {subscription.confirmed_at ? new Date(subscription.confirmed_at).toLocaleString() : '-'}
+                      </td>
+                      <td className="px-4 py-2 text-sm text-gray-600">
+                        {subscription.last_message_at ? new Date(subscription.last_message_at).toLocaleString() : '-'}
+                      </td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </div>
+          )}
+        </div>
+
         {/* Timezone Settings */}
         <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
           <div className="flex items-center mb-6">
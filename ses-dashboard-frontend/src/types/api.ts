@@ -32,6 +32,27 @@ export interface Event {
   RemoteMtaIp?: string;
   ReportingMTA?: string;
   Tags?: string;
+  ComplaintFeedbackType?: string;
+  ComplaintSubType?: string;
+  ComplaintUserAgent?: string;
+  ComplaintArrivalDate?: string | null;
+  FeedbackID?: string;
+  Link?: string;
+  LinkTags?: string;
+  IPAddress?: string;
+  UserAgent?: string;
+  OccurredAt?: string | null;
+  DelayType?: string;
+  DelayExpirationTime?: string | null;
+  TemplateName?: string;
+  ContactList?: string;
+  TopicPreferences?: string;
+  ConfigurationSet?: string;
+  FromDomain?: string;
+  PublishingMechanism?: 'event_publishing' | 'notification';
+  Provider?: 'ses' | 'sendgrid' | 'mailgun' | 'postmark';
+  SNSMessageID?: string;
+  EventKey?: string;
 }
 
 export interface PaginationInfo {
@@ -56,6 +77,10 @@ export interface MetricsResponse {
   complaint_count: number;
   open_count: number;
   click_count: number;
+  delivery_delay_count: number;
+  reject_count: number;
+  rendering_failure_count: number;
+  subscription_count: number;
   bounce_rate: number;
   delivery_rate: number;
 }
@@ -70,6 +95,10 @@ export interface DailyMetrics {
   complaint_count: number;
   open_count: number;
   click_count: number;
+  delivery_delay_count: number;
+  reject_count: number;
+  rendering_failure_count: number;
+  subscription_count: number;
   bounce_rate: number;
   delivery_rate: number;
 }
@@ -83,6 +112,10 @@ export interface MonthlyMetrics {
   complaint_count: number;
   open_count: number;
   click_count: number;
+  delivery_delay_count: number;
+  reject_count: number;
+  rendering_failure_count: number;
+  subscription_count: number;
   bounce_rate: number;
   delivery_rate: number;
 }
@@ -96,6 +129,10 @@ export interface HourlyMetrics {
   complaint_count: number;
   open_count: number;
   click_count: number;
+  delivery_delay_count: number;
+  reject_count: number;
+  rendering_failure_count: number;
+  subscription_count: number;
   bounce_rate: number;
   delivery_rate: number;
 }
@@ -23,12 +23,23 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	_ "ses-monitoring/docs"
 	"ses-monitoring/internal/config"
 	"ses-monitoring/internal/delivery/http"
+	"ses-monitoring/internal/infrastructure/aws"
+	"ses-monitoring/internal/infrastructure/blobstore"
 	"ses-monitoring/internal/infrastructure/database"
+	"ses-monitoring/internal/infrastructure/eventsink"
 	"ses-monitoring/internal/infrastructure/repository"
 	"ses-monitoring/internal/services"
 	"ses-monitoring/internal/usecase"
@@ -57,11 +68,30 @@ func main() {
 
 	db := database.NewPostgres(dsn)
 
+	// Message broker sinks: stored events are queued in the event outbox in
+	// the same statement that stores them, then relayed to each broker
+	topics, err := eventsink.ParseTopics(cfg.Sinks.TopicTemplate, cfg.Sinks.Topics)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid sink topics: %v", err))
+	}
+	sinks, err := eventsink.NewFromConfig(cfg, topics)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize event sinks: %v", err))
+	}
+
 	sesRepo := repository.NewSESEventRepository(db)
+	if len(sinks) > 0 {
+		sesRepo = repository.NewSESEventRepositoryWithOutbox(db, eventsink.Names(sinks))
+	}
 	userRepo := repository.NewUserRepository(db)
 	settingsRepo := repository.NewSettingsRepository(db)
 	suppressionRepo := repository.NewSuppressionRepository(db)
 	suppressionDBRepo := database.NewSuppressionRepository(db)
+	snsSubscriptionRepo := repository.NewSNSSubscriptionRepository(db)
+	deadLetterRepo := repository.NewDeadLetterRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	exportJobRepo := repository.NewExportJobRepository(db)
 
 	// Initialize AWS client and sync service
 	// Initialize services
@@ -77,14 +107,163 @@ func main() {
 
 	sesUC := usecase.NewSESUsecase(sesRepo)
 	authUC := usecase.NewAuthUsecase(userRepo, cfg.App.JWTSecret)
+	deadLetterUC := usecase.NewDeadLetterUsecase(deadLetterRepo, sesUC)
+	webhookUC := usecase.NewWebhookUsecase(webhookRepo)
+	recipientUC := usecase.NewRecipientUsecase(sesRepo, suppressionRepo, suppressionDBRepo)
+
+	// Outbound webhooks: stored events are queued per matching subscription
+	// and delivered in the background
+	var webhookDispatcher *services.WebhookDispatcher
+	if cfg.Webhooks.Enabled {
+		sesUC.OnEventsStored(webhookUC.EnqueueEvents)
+		webhookDispatcher = services.NewWebhookDispatcher(webhookRepo, services.WebhookDispatcherConfig{
+			Workers:              cfg.Webhooks.Workers,
+			MaxAttempts:          cfg.Webhooks.MaxAttempts,
+			Timeout:              time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second,
+			DisableAfterFailures: cfg.Webhooks.DisableAfterFailures,
+			RetentionDays:        cfg.Webhooks.RetentionDays,
+		})
+		webhookDispatcher.Start()
+	}
+
+	// Live event streams are woken up when events were stored
+	eventBroadcaster := services.NewEventBroadcaster()
+	sesUC.OnEventsStored(eventBroadcaster.Notify)
+
+	var outboxRelay *services.OutboxRelay
+	if len(sinks) > 0 {
+		log.Printf("Event sinks enabled: %s", strings.Join(eventsink.Names(sinks), ", "))
+		outboxRelay = services.NewOutboxRelay(outboxRepo, sesRepo, sinks, topics, services.OutboxRelayConfig{
+			BatchSize: cfg.Sinks.BatchSize,
+		})
+		outboxRelay.Start()
+	}
+
+	// Buffered ingestion: webhooks enqueue, workers write batches
+	var ingestPipeline *services.IngestPipeline
+	if cfg.Ingest.Async {
+		ingestPipeline = services.NewIngestPipeline(sesUC, services.IngestPipelineConfig{
+			QueueSize:     cfg.Ingest.QueueSize,
+			Workers:       cfg.Ingest.Workers,
+			BatchSize:     cfg.Ingest.BatchSize,
+			FlushInterval: time.Duration(cfg.Ingest.FlushIntervalMs) * time.Millisecond,
+		})
+		ingestPipeline.SetFailureHandler(deadLetterUC.RecordFailedEvents)
+		ingestPipeline.Start()
+	}
+
+	// Raw SES messages are archived so history can be re-parsed later
+	var eventArchiver *services.EventArchiver
+	if cfg.Archive.Enabled {
+		store, err := blobstore.NewFromConfig(context.Background(), cfg)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to initialize archive storage: %v", err))
+		}
+		eventArchiver = services.NewEventArchiver(store, services.EventArchiverConfig{
+			Prefix:        cfg.Archive.Prefix,
+			FlushInterval: time.Duration(cfg.Archive.FlushIntervalSeconds) * time.Second,
+			MaxBatch:      cfg.Archive.MaxBatch,
+		})
+		eventArchiver.Start()
+	}
+
+	// Large exports run as background jobs and are downloaded from the object store
+	var exportWorker *services.ExportWorker
+	var exportUC *usecase.ExportUsecase
+	// Stops the export file cleanup at shutdown
+	exportCleanupCtx, cancelExportCleanup := context.WithCancel(context.Background())
+	defer cancelExportCleanup()
+	if cfg.Exports.Enabled {
+		store, err := blobstore.NewFromConfig(context.Background(), cfg)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to initialize export storage: %v", err))
+		}
+		exportWorker = services.NewExportWorker(exportJobRepo, sesRepo, store, services.ExportWorkerConfig{
+			Workers:   cfg.Exports.Workers,
+			Prefix:    cfg.Exports.Prefix,
+			Retention: time.Duration(cfg.Exports.RetentionHours) * time.Hour,
+		})
+		exportWorker.Start()
+		go services.NewExportCleanupService(exportJobRepo, store).StartCleanupScheduler(exportCleanupCtx)
+
+		signingKey := cfg.Exports.SigningKey
+		if signingKey == "" {
+			signingKey = cfg.App.JWTSecret
+		}
+		exportUC = usecase.NewExportUsecase(exportJobRepo, store, signingKey, time.Duration(cfg.Exports.LinkTTLSeconds)*time.Second)
+	}
+
+	var snsVerifier *aws.SNSVerifier
+	if !cfg.AWS.SNSSkipVerify {
+		snsVerifier = aws.NewSNSVerifier(aws.NewHTTPCertFetcher())
+	}
 
-	snsHandler := http.NewSNSHandler(sesUC, cfg)
-	monitoringHandler := http.NewMonitoringHandler(sesUC, settingsRepo)
+	// Pull-based ingestion from an SQS queue subscribed to the SES topic
+	ingestMode := cfg.AWS.IngestMode
+	if ingestMode == "" {
+		ingestMode = "webhook"
+	}
+	var sqsConsumer *services.SQSConsumer
+	if ingestMode == "sqs" || ingestMode == "both" {
+		if cfg.AWS.SQSQueueURL == "" {
+			panic("SQS ingestion requires SQS_QUEUE_URL")
+		}
+		sqsClient, err := aws.NewSQSClient(
+			context.Background(),
+			cfg.AWS.Region,
+			cfg.AWS.AccessKey,
+			cfg.AWS.SecretKey,
+			cfg.AWS.SQSEndpoint,
+		)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to initialize SQS client: %v", err))
+		}
+		// Queued messages may be far older than the webhook's window allows
+		var sqsVerifier *aws.SNSVerifier
+		if !cfg.AWS.SNSSkipVerify {
+			sqsVerifier = aws.NewSNSVerifier(aws.NewHTTPCertFetcher())
+			sqsVerifier.SetMaxMessageAge(services.SQSMaxMessageAge)
+		}
+		sqsConsumer = services.NewSQSConsumer(sqsClient, sesUC, deadLetterUC, eventArchiver, sqsVerifier, services.SQSConsumerConfig{
+			QueueURL:                 cfg.AWS.SQSQueueURL,
+			AllowedTopicARN:          cfg.AWS.SNSTopicARN,
+			Workers:                  cfg.AWS.SQSWorkers,
+			MaxMessages:              cfg.AWS.SQSMaxMessages,
+			WaitTimeSeconds:          cfg.AWS.SQSWaitTimeSeconds,
+			VisibilityTimeoutSeconds: cfg.AWS.SQSVisibilityTimeoutSecs,
+		})
+		sqsConsumer.Start()
+	}
+
+	snsHandler := http.NewSNSHandler(
+		sesUC,
+		ingestPipeline,
+		deadLetterUC,
+		eventArchiver,
+		snsVerifier,
+		aws.NewSNSSubscriptionConfirmer(nil),
+		snsSubscriptionRepo,
+		cfg,
+	)
+	firehoseHandler := http.NewFirehoseHandler(sesUC, ingestPipeline, deadLetterUC, eventArchiver, cfg)
+	eventBridgeHandler := http.NewEventBridgeHandler(sesUC, ingestPipeline, deadLetterUC, eventArchiver, cfg)
+	providerHandler, err := http.NewProviderHandler(sesUC, ingestPipeline, deadLetterUC, eventArchiver, cfg)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize provider webhooks: %v", err))
+	}
+	monitoringHandler := http.NewMonitoringHandler(sesUC, settingsRepo, eventBroadcaster)
+	streamTokenHandler := http.NewStreamTokenHandler([]byte(cfg.App.JWTSecret))
+	recipientHandler := http.NewRecipientHandler(recipientUC, monitoringHandler)
 	authHandler := http.NewAuthHandler(authUC)
 	userHandler := http.NewUserHandler(authUC)
 	settingsHandler := http.NewSettingsHandler(settingsRepo)
 	suppressionHandler := http.NewSuppressionHandler(settingsRepo, suppressionRepo, suppressionDBRepo, syncService)
 	healthHandler := http.NewHealthHandler()
+	ingestHandler := http.NewIngestHandler(ingestPipeline)
+	deadLetterHandler := http.NewDeadLetterHandler(deadLetterUC)
+	webhookHandler := http.NewWebhookHandler(webhookUC)
+	sinkHandler := http.NewSinkHandler(outboxRepo, eventsink.Names(sinks))
+	exportJobHandler := http.NewExportJobHandler(exportUC, monitoringHandler)
 
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery())
@@ -116,8 +295,24 @@ func main() {
 	// ========================
 	r.GET("/health", healthHandler.Health)
 	r.GET("/ready", healthHandler.Ready)
-	r.POST("/sns/ses", snsHandler.Handle)
+	if ingestMode != "sqs" {
+		r.POST("/sns/ses", snsHandler.Handle)
+	}
+	if cfg.AWS.FirehoseAccessKey != "" {
+		r.POST("/firehose/ses", firehoseHandler.Handle)
+	}
+	if cfg.AWS.EventBridgeAPIKey != "" {
+		r.POST("/eventbridge/ses", eventBridgeHandler.Handle)
+	}
+	if len(providerHandler.Providers()) > 0 {
+		log.Printf("Provider webhooks enabled: %s", strings.Join(providerHandler.Providers(), ", "))
+		r.POST("/webhooks/:provider", providerHandler.Handle)
+	}
 	r.POST("/api/login", authHandler.Login)
+	if exportUC != nil {
+		// Signed links work without a bearer token
+		r.GET("/api/exports/:id/download", exportJobHandler.DownloadExport)
+	}
 
 	// ========================
 	// SWAGGER
@@ -129,6 +324,10 @@ func main() {
 	// ========================
 	// PROTECTED ROUTES
 	// ========================
+	// EventSource cannot send the Authorization header, so the stream also
+	// accepts a stream token as access_token
+	r.GET("/api/events/stream", http.StreamAuthMiddleware([]byte(cfg.App.JWTSecret)), monitoringHandler.StreamEvents)
+
 	api := r.Group("/api")
 	api.Use(http.JWTAuthMiddleware([]byte(cfg.App.JWTSecret)))
 	api.Use(func(c *gin.Context) {
@@ -137,10 +336,21 @@ func main() {
 	})
 	{
 		api.GET("/events", monitoringHandler.GetEvents)
+		api.POST("/events/stream/token", streamTokenHandler.CreateStreamToken)
+		api.GET("/events/export", monitoringHandler.ExportEvents)
+		api.GET("/messages/:message_id", monitoringHandler.GetMessageTimeline)
+		api.GET("/recipients/:email", recipientHandler.GetRecipient)
 		api.GET("/metrics", monitoringHandler.GetMetrics)
 		api.GET("/metrics/daily", monitoringHandler.GetDailyMetrics)
 		api.GET("/metrics/monthly", monitoringHandler.GetMonthlyMetrics)
 		api.GET("/metrics/hourly", monitoringHandler.GetHourlyMetrics)
+		api.GET("/engagement/links", monitoringHandler.GetTopLinks)
+		if exportUC != nil {
+			api.POST("/exports", exportJobHandler.CreateExportJob)
+			api.GET("/exports", exportJobHandler.GetExportJobs)
+			api.GET("/exports/:id", exportJobHandler.GetExportJob)
+			api.DELETE("/exports/:id", exportJobHandler.DeleteExportJob)
+		}
 
 		// User management routes (admin only)
 		admin := api.Group("")
@@ -161,6 +371,26 @@ func main() {
 			admin.PUT("/settings/retention", settingsHandler.UpdateRetentionSettings)
 			admin.GET("/settings/timezone", settingsHandler.GetTimezoneSettings)
 			admin.PUT("/settings/timezone", settingsHandler.UpdateTimezoneSettings)
+			admin.GET("/settings/sns/subscriptions", snsHandler.GetSubscriptions)
+			admin.GET("/settings/ingest/stats", ingestHandler.GetStats)
+			admin.GET("/settings/sinks/stats", sinkHandler.GetStats)
+
+			// Dead-lettered SES payloads (admin only)
+			admin.GET("/deadletters", deadLetterHandler.GetDeadLetters)
+			admin.POST("/deadletters/replay", deadLetterHandler.ReplayDeadLetters)
+			admin.GET("/deadletters/:id", deadLetterHandler.GetDeadLetter)
+			admin.PUT("/deadletters/:id", deadLetterHandler.UpdateDeadLetter)
+			admin.POST("/deadletters/:id/retry", deadLetterHandler.RetryDeadLetter)
+			admin.DELETE("/deadletters/:id", deadLetterHandler.DiscardDeadLetter)
+
+			// Outbound webhook subscriptions (admin only)
+			admin.GET("/webhooks", webhookHandler.GetWebhooks)
+			admin.POST("/webhooks", webhookHandler.CreateWebhook)
+			admin.GET("/webhooks/:id", webhookHandler.GetWebhook)
+			admin.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+			admin.POST("/webhooks/:id/deliveries/:delivery_id/retry", webhookHandler.RetryWebhookDelivery)
 
 			// AWS SES Suppression management routes (admin only)
 			admin.GET("/suppression", suppressionHandler.GetSuppressions)
@@ -177,5 +407,61 @@ func main() {
 		api.PUT("/change-password", userHandler.ChangePassword)
 	}
 
-	r.Run(fmt.Sprintf(":%d", cfg.App.Port))
+	srv := &nethttp.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.App.Port),
+		Handler: r,
+	}
+	// Event streams never finish on their own, so end them when shutting down
+	srv.RegisterOnShutdown(eventBroadcaster.Close)
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Wait for a shutdown signal, then stop taking requests before draining
+	// the ingest queue so no accepted event is lost
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	if sqsConsumer != nil {
+		if err := sqsConsumer.Shutdown(ctx); err != nil {
+			log.Printf("SQS consumer did not stop: %v", err)
+		}
+	}
+	if ingestPipeline != nil {
+		if err := ingestPipeline.Shutdown(ctx); err != nil {
+			log.Printf("Ingest pipeline did not drain: %v", err)
+		}
+	}
+	if eventArchiver != nil {
+		if err := eventArchiver.Shutdown(ctx); err != nil {
+			log.Printf("Event archive flush failed: %v", err)
+		}
+	}
+	cancelExportCleanup()
+	if exportWorker != nil {
+		if err := exportWorker.Shutdown(ctx); err != nil {
+			log.Printf("Export worker did not stop: %v", err)
+		}
+	}
+	if webhookDispatcher != nil {
+		if err := webhookDispatcher.Shutdown(ctx); err != nil {
+			log.Printf("Webhook dispatcher did not stop: %v", err)
+		}
+	}
+	if outboxRelay != nil {
+		if err := outboxRelay.Shutdown(ctx); err != nil {
+			log.Printf("Outbox relay did not stop: %v", err)
+		}
+	}
 }
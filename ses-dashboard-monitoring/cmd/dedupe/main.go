@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+
+	"ses-monitoring/internal/infrastructure/repository"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	var (
+		dbHost     = flag.String("host", "localhost", "Database host")
+		dbPort     = flag.String("port", "5432", "Database port")
+		dbUser     = flag.String("user", "ses_user", "Database user")
+		dbPassword = flag.String("password", "ses_password", "Database password")
+		dbName     = flag.String("dbname", "ses_monitoring", "Database name")
+		dryRun     = flag.Bool("dry-run", false, "Only report how many duplicate events would be removed")
+	)
+	flag.Parse()
+
+	// Build connection string
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		*dbHost, *dbPort, *dbUser, *dbPassword, *dbName)
+
+	// Connect to database
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSESEventRepository(db)
+	ctx := context.Background()
+
+	if *dryRun {
+		count, err := repo.CountDuplicateEvents(ctx)
+		if err != nil {
+			log.Fatal("Failed to count duplicate events:", err)
+		}
+		fmt.Printf("Found %d duplicate events\n", count)
+		return
+	}
+
+	deleted, err := repo.DeleteDuplicateEvents(ctx)
+	if err != nil {
+		log.Fatal("Failed to delete duplicate events:", err)
+	}
+	fmt.Printf("Deleted %d duplicate events\n", deleted)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/blobstore"
+	"ses-monitoring/internal/infrastructure/database"
+	"ses-monitoring/internal/infrastructure/repository"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+)
+
+// rebuild re-parses the raw event archive into ses_events. By default it only
+// inserts events that are missing; -upsert also rewrites stored events, which
+// backfills columns added after the events were first ingested.
+func main() {
+	var (
+		configPath = flag.String("config", "config/config.yaml", "Path to config file")
+		from       = flag.String("from", "", "First day to rebuild (YYYY-MM-DD, default: oldest archived day)")
+		to         = flag.String("to", "", "Last day to rebuild (YYYY-MM-DD, default: newest archived day)")
+		upsert     = flag.Bool("upsert", false, "Overwrite stored events instead of only inserting missing ones")
+		batchSize  = flag.Int("batch", 500, "Events written per batch")
+		dryRun     = flag.Bool("dry-run", false, "Parse the archive and report counts without writing")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	fromDay, toDay, err := parseDayRange(*from, *to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	store, err := blobstore.NewFromConfig(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to open archive storage:", err)
+	}
+
+	prefix := cfg.Archive.Prefix
+	if prefix == "" {
+		prefix = "ses-events"
+	}
+	prefix = strings.Trim(prefix, "/") + "/"
+
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		log.Fatal("Failed to list archive:", err)
+	}
+
+	var sesUC *usecase.SESUsecase
+	if !*dryRun {
+		dsn := fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Database.Host,
+			cfg.Database.Port,
+			cfg.Database.User,
+			cfg.Database.Password,
+			cfg.Database.Name,
+			cfg.Database.SSLMode,
+		)
+		db := database.NewPostgres(dsn)
+		defer db.Close()
+		sesUC = usecase.NewSESUsecase(repository.NewSESEventRepository(db))
+	}
+
+	var (
+		objects, records, unparsable, parsed, written int
+		batch                                         []*sesevent.Event
+	)
+	flush := func() {
+		if len(batch) == 0 || *dryRun {
+			batch = batch[:0]
+			return
+		}
+		var n int
+		var err error
+		if *upsert {
+			n, err = sesUC.UpsertEvents(ctx, batch)
+		} else {
+			n, err = sesUC.StoreEvents(ctx, batch)
+		}
+		if err != nil {
+			log.Fatal("Failed to write events:", err)
+		}
+		written += n
+		batch = batch[:0]
+	}
+
+	for _, key := range keys {
+		day, ok := partitionDay(key)
+		if !ok || (fromDay != "" && day < fromDay) || (toDay != "" && day > toDay) {
+			continue
+		}
+		objects++
+
+		err := services.ReadArchiveObject(ctx, store, key, func(record services.ArchiveRecord) error {
+			records++
+			events, err := usecase.ParseMessage(record.Source, []byte(record.Message))
+			if err != nil {
+				unparsable++
+				log.Printf("Skipping record from %s (SNS %s): %v", key, record.SNSMessageID, err)
+				return nil
+			}
+			if record.SNSMessageID != "" {
+				for _, event := range events {
+					event.SNSMessageID = record.SNSMessageID
+				}
+			}
+			parsed += len(events)
+			batch = append(batch, events...)
+			if len(batch) >= *batchSize {
+				flush()
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal("Failed to read archive object:", err)
+		}
+	}
+	flush()
+
+	fmt.Printf("Read %d records from %d archive objects: %d events parsed, %d records unparsable\n",
+		records, objects, parsed, unparsable)
+	if *dryRun {
+		fmt.Println("Dry run, nothing written")
+	} else if *upsert {
+		fmt.Printf("Inserted or updated %d events\n", written)
+	} else {
+		fmt.Printf("Inserted %d missing events\n", written)
+	}
+}
+
+// partitionDay extracts the YYYY-MM-DD of a ".../dt=YYYY-MM-DD/..." key
+func partitionDay(key string) (string, bool) {
+	for _, segment := range strings.Split(key, "/") {
+		if day, ok := strings.CutPrefix(segment, "dt="); ok {
+			return day, true
+		}
+	}
+	return "", false
+}
+
+func parseDayRange(from, to string) (string, string, error) {
+	for _, day := range []string{from, to} {
+		if day == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			return "", "", fmt.Errorf("invalid day %q, expected YYYY-MM-DD", day)
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return "", "", fmt.Errorf("-from %s is after -to %s", from, to)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/database"
+	"ses-monitoring/internal/infrastructure/eventsink"
+	"ses-monitoring/internal/infrastructure/repository"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+)
+
+// sinkcheck is the integration check of the event sinks. It stores a probe
+// event through the outbox, relays it to every configured broker and reads it
+// back from each of them. Run it against the brokers of the docker compose
+// "brokers" profile, see the README. It exits non-zero if any broker did not
+// receive the probe.
+func main() {
+	var (
+		configPath = flag.String("config", "config/config.yaml", "Path to config file")
+		timeout    = flag.Duration("timeout", 30*time.Second, "How long to wait for the probe on each broker")
+	)
+	flag.Parse()
+
+	if !run(*configPath, *timeout) {
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, timeout time.Duration) bool {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	topics, err := eventsink.ParseTopics(cfg.Sinks.TopicTemplate, cfg.Sinks.Topics)
+	if err != nil {
+		log.Fatal("Invalid sink topics:", err)
+	}
+	sinks, err := eventsink.NewFromConfig(cfg, topics)
+	if err != nil {
+		log.Fatal("Failed to initialize event sinks:", err)
+	}
+	if len(sinks) == 0 {
+		log.Fatal("No event sink configured, set NATS_URL, KAFKA_BROKERS or REDIS_URL")
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Database.Host,
+		cfg.Database.Port,
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.Name,
+		cfg.Database.SSLMode,
+	)
+	db := database.NewPostgres(dsn)
+	defer db.Close()
+
+	sesRepo := repository.NewSESEventRepositoryWithOutbox(db, eventsink.Names(sinks))
+	sesUC := usecase.NewSESUsecase(sesRepo)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	probe := &sesevent.Event{
+		MessageID:      fmt.Sprintf("sinkcheck-%d", now.UnixNano()),
+		Email:          "sinkcheck@example.invalid",
+		Source:         "sinkcheck@example.invalid",
+		Subject:        "sinkcheck probe",
+		EventType:      "SinkCheck",
+		Status:         "sinkcheck",
+		EventTimestamp: now,
+		Provider:       sesevent.ProviderSES,
+	}
+	if _, err := sesUC.HandleEvents(ctx, []*sesevent.Event{probe}); err != nil {
+		log.Fatal("Failed to store probe event:", err)
+	}
+	// Removing the probe also removes its outbox entries
+	defer func() {
+		if _, err := db.ExecContext(ctx, `DELETE FROM ses_events WHERE id = $1`, probe.ID); err != nil {
+			log.Printf("Failed to remove probe event %d: %v", probe.ID, err)
+		}
+	}()
+
+	relay := services.NewOutboxRelay(repository.NewOutboxRepository(db), sesRepo, sinks, topics, services.OutboxRelayConfig{})
+	relay.Start()
+	defer relay.Shutdown(context.Background())
+
+	topic := topics.For(probe)
+	log.Printf("Probe %s stored, waiting for it on %s", probe.EventKey, topic)
+
+	ok := true
+	for _, sink := range sinks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := readBack(checkCtx, cfg, sink.Name(), topic, probe.EventKey)
+		cancel()
+		if err != nil {
+			ok = false
+			log.Printf("FAIL %s: %v", sink.Name(), err)
+			continue
+		}
+		log.Printf("ok   %s", sink.Name())
+	}
+	return ok
+}
+
+// readBack waits until the broker of a sink holds a message with the event key
+func readBack(ctx context.Context, cfg *config.Config, sink, topic, eventKey string) error {
+	switch sink {
+	case "nats":
+		return readNATS(ctx, cfg, topic, eventKey)
+	case "kafka":
+		return readKafka(ctx, cfg, topic, eventKey)
+	case "redis":
+		return readRedis(ctx, cfg, topic, eventKey)
+	default:
+		return fmt.Errorf("no read-back for sink %q", sink)
+	}
+}
+
+func readNATS(ctx context.Context, cfg *config.Config, subject, eventKey string) error {
+	conn, err := nats.Connect(cfg.Sinks.NATSURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return err
+	}
+
+	stream := cfg.Sinks.NATSStream
+	if stream == "" {
+		stream = eventsink.DefaultNATSStream
+	}
+	for {
+		consumer, err := js.OrderedConsumer(ctx, stream, jetstream.OrderedConsumerConfig{
+			FilterSubjects: []string{subject},
+			DeliverPolicy:  jetstream.DeliverLastPerSubjectPolicy,
+		})
+		if err == nil {
+			msg, err := consumer.Next(jetstream.FetchMaxWait(time.Second))
+			if err == nil && msg.Headers().Get("event-key") == eventKey {
+				return nil
+			}
+		}
+		if err := sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func readKafka(ctx context.Context, cfg *config.Config, topic, eventKey string) error {
+	var brokers []string
+	for _, broker := range strings.Split(cfg.Sinks.KafkaBrokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     "sinkcheck-" + eventKey,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, header := range msg.Headers {
+			if header.Key == "event-key" && string(header.Value) == eventKey {
+				return nil
+			}
+		}
+	}
+}
+
+func readRedis(ctx context.Context, cfg *config.Config, stream, eventKey string) error {
+	opts, err := redis.ParseURL(cfg.Sinks.RedisURL)
+	if err != nil {
+		return err
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	for {
+		entries, err := client.XRevRangeN(ctx, stream, "+", "-", 100).Result()
+		if err == nil {
+			for _, entry := range entries {
+				if entry.Values["event-key"] == eventKey {
+					return nil
+				}
+			}
+		}
+		if err := sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(500 * time.Millisecond):
+		return nil
+	}
+}
@@ -24,22 +24,28 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/events": {
+        "/api/deadletters": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of SES events with pagination support",
+                "description": "List SES payloads that failed to process, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "monitoring"
+                    "deadletters"
                 ],
-                "summary": "Get SES events with pagination",
+                "summary": "List dead-lettered payloads",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, replayed)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "minimum": 1,
                         "type": "integer",
@@ -48,10 +54,10 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "maximum": 1000,
+                        "maximum": 500,
                         "minimum": 1,
                         "type": "integer",
-                        "description": "Number of events per page (default: 50, max: 1000)",
+                        "description": "Entries per page (default: 50, max: 500)",
                         "name": "limit",
                         "in": "query"
                     }
@@ -64,15 +70,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -85,9 +82,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/login": {
+        "/api/deadletters/replay": {
             "post": {
-                "description": "Authenticate user and return JWT token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replay the given entries, or the oldest pending entries when no IDs are given",
                 "consumes": [
                     "application/json"
                 ],
@@ -95,17 +97,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "deadletters"
                 ],
-                "summary": "Login user",
+                "summary": "Bulk replay dead-lettered payloads",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "description": "Entry IDs, or a limit for pending entries (default and max: 500)",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/usecase.LoginRequest"
+                            "$ref": "#/definitions/http.ReplayDeadLettersRequest"
                         }
                     }
                 ],
@@ -113,7 +114,8 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/usecase.LoginResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -125,8 +127,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -137,30 +139,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/metrics": {
+        "/api/deadletters/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve overall SES metrics with counts and rates",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "monitoring"
+                    "deadletters"
+                ],
+                "summary": "Get a dead-lettered payload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get overall metrics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.MetricsResponse"
+                            "$ref": "#/definitions/deadletter.Entry"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -169,78 +179,60 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/metrics/daily": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve daily SES metrics",
+                "description": "Replace the stored SES payload and replay it immediately",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "monitoring"
+                    "deadletters"
                 ],
-                "summary": "Get daily metrics",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/sesevent.DailyMetrics"
-                                }
-                            }
-                        }
+                "summary": "Edit and retry a dead-lettered payload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "description": "Corrected SES event JSON",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.UpdateDeadLetterRequest"
                         }
                     }
-                }
-            }
-        },
-        "/api/metrics/hourly": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve hourly SES metrics",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "monitoring"
                 ],
-                "summary": "Get hourly metrics",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.DeadLetterReplayResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/sesevent.HourlyMetrics"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -249,38 +241,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/metrics/monthly": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve monthly SES metrics",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "monitoring"
+                    "deadletters"
+                ],
+                "summary": "Discard a dead-lettered payload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get monthly metrics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/sesevent.MonthlyMetrics"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -291,88 +286,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/settings/aws": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get current AWS configuration settings",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Get AWS settings",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/settings.AWSConfig"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            },
-            "put": {
+        "/api/deadletters/{id}/retry": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update AWS configuration settings",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "deadletters"
                 ],
-                "summary": "Update AWS settings",
+                "summary": "Retry a dead-lettered payload",
                 "parameters": [
                     {
-                        "description": "AWS settings",
-                        "name": "settings",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/settings.AWSConfig"
-                        }
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/usecase.DeadLetterReplayResult"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -383,33 +328,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/settings/aws/test": {
-            "post": {
+        "/api/engagement/links": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Test AWS SES connection with provided credentials",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Rank clicked links by unique clicks (one per message) over a date range",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "engagement"
                 ],
-                "summary": "Test AWS connection",
+                "summary": "Get most clicked links",
                 "parameters": [
                     {
-                        "description": "AWS settings to test",
-                        "name": "settings",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/settings.AWSConfig"
-                        }
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD, default: 30 days ago)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD, default: today)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of links to return (default: 20, max: 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -418,7 +377,10 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/sesevent.LinkClickStats"
+                                }
                             }
                         }
                     },
@@ -443,56 +405,145 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/settings/retention": {
+        "/api/events": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get event log retention settings",
+                "description": "Retrieve list of SES events, newest first. Sending cursor (empty for the first page) switches from page numbers to cursor pagination, which stays fast on deep pages: follow pagination.nextCursor and pagination.prevCursor to move through the list.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "monitoring"
                 ],
-                "summary": "Get retention settings",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/http.RetentionSettings"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update event log retention settings",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Update retention settings",
+                "summary": "Get SES events with pagination",
                 "parameters": [
                     {
-                        "description": "Retention settings",
-                        "name": "settings",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.RetentionSettings"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from pagination.nextCursor or pagination.prevCursor; empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of events per page (default: 50, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approximate",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Total to return: exact, approximate (planner estimate) or none (default with page: exact for filters matching up to about 100000 events, otherwise approximate; approximate with cursor)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search email, subject or source",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event types, repeatable or comma separated (e.g. Bounce,Complaint)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce type (Permanent, Transient, Undetermined)",
+                        "name": "bounce_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce subtype (e.g. General, NoEmail, MailboxFull)",
+                        "name": "bounce_sub_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender address, local@ for a local part at any domain or @domain for any address at a domain",
+                        "name": "sender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient domain (e.g. gmail.com)",
+                        "name": "recipient_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the reporting MTA",
+                        "name": "reporting_mta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Complaint feedback type (e.g. abuse, not-spam)",
+                        "name": "feedback_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)",
+                        "name": "tag",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -500,35 +551,16 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/api/suppression": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get list of suppressed emails from suppressions table",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "suppression"
-                ],
-                "summary": "Get suppressions from new table",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -541,38 +573,149 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/events/export": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add email to local suppression list and sync to AWS SES",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download every event matching the filters, newest first, as CSV, NDJSON or XLSX. Rows are streamed from a database cursor, so exports of any size start immediately. Times are converted to the configured timezone. If reading fails midway the connection is closed without finishing the response, so a truncated download is never mistaken for a complete one.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "suppression"
+                    "monitoring"
                 ],
-                "summary": "Add email to suppression list with AWS sync",
+                "summary": "Export SES events",
                 "parameters": [
                     {
-                        "description": "Email to suppress",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.AddSuppressionRequest"
-                        }
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default: csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Columns in order, repeatable or comma separated (default: id, event_timestamp, occurred_at, event_type, message_id, email, source, subject, status, bounce_type, bounce_sub_type, diagnostic_code, reporting_mta, complaint_feedback_type, provider)",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search email, subject or source",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event types, repeatable or comma separated (e.g. Bounce,Complaint)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce type (Permanent, Transient, Undetermined)",
+                        "name": "bounce_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce subtype (e.g. General, NoEmail, MailboxFull)",
+                        "name": "bounce_sub_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender address, local@ for a local part at any domain or @domain for any address at a domain",
+                        "name": "sender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient domain (e.g. gmail.com)",
+                        "name": "recipient_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the reporting MTA",
+                        "name": "reporting_mta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Complaint feedback type (e.g. abuse, not-spam)",
+                        "name": "feedback_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)",
+                        "name": "tag",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -580,8 +723,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -592,82 +735,185 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/suppression/bulk": {
-            "post": {
+        "/api/events/stream": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add multiple emails to suppression list at once",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Server-Sent Events stream of newly stored events, oldest first. Each event is sent as a message whose id is the event ID and whose data is the event JSON; a \"heartbeat\" event is sent every 15 seconds. A new stream starts with the next stored event; after a reconnect the Last-Event-ID header (or last_event_id) resumes after the last received event and repeats events of the last 30 seconds that may have been missed, so clients should ignore IDs they already have.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "suppression"
+                    "monitoring"
                 ],
-                "summary": "Bulk add emails to suppression list",
+                "summary": "Stream new SES events",
                 "parameters": [
                     {
-                        "description": "Emails to suppress",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.BulkSuppressionRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "string",
+                        "description": "Stream token from /api/events/stream/token, for clients that cannot set headers",
+                        "name": "access_token",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search email, subject or source",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event types, repeatable or comma separated (e.g. Bounce,Complaint)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce type (Permanent, Transient, Undetermined)",
+                        "name": "bounce_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce subtype (e.g. General, NoEmail, MailboxFull)",
+                        "name": "bounce_sub_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender address, local@ for a local part at any domain or @domain for any address at a domain",
+                        "name": "sender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient domain (e.g. gmail.com)",
+                        "name": "recipient_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the reporting MTA",
+                        "name": "reporting_mta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Complaint feedback type (e.g. abuse, not-spam)",
+                        "name": "feedback_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
                                 "type": "string"
                             }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/events/stream/token": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove multiple emails from AWS SES suppression list at once",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Short-lived token for /api/events/stream?access_token=..., for clients such as EventSource that cannot send an Authorization header. It only opens event streams and expires after a minute; request a new one before reconnecting.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "suppression"
-                ],
-                "summary": "Bulk remove emails from suppression list",
-                "parameters": [
-                    {
-                        "description": "Emails to remove from suppression",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.BulkRemoveRequest"
-                        }
-                    }
+                    "monitoring"
                 ],
+                "summary": "Create an event stream token",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -676,8 +922,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -688,21 +934,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/suppression/sync": {
-            "post": {
+        "/api/exports": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Trigger manual sync of suppression list from AWS SES to local database",
+                "description": "The 100 newest export jobs of the current user; admins see the jobs of every user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "suppression"
+                    "exports"
                 ],
-                "summary": "Trigger manual sync from AWS SES",
+                "summary": "List export jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -721,54 +967,160 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/suppression/sync/status": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get last sync time and current sync status",
+                "description": "Queue a background export of every event matching the filters. It takes the same query parameters as /api/events/export. A worker writes the file (gzip compressed CSV or NDJSON, or XLSX) to the configured object store; poll the job until it succeeded and download it from download_url.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "suppression"
+                    "exports"
+                ],
+                "summary": "Start an export job",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default: csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Columns in order, repeatable or comma separated",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query (see README)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search email, subject or source",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event types, repeatable or comma separated (e.g. Bounce,Complaint)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounce type (Permanent, Transient, Undetermined)",
+                        "name": "bounce_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender address, local@ for a local part at any domain or @domain for any address at a domain",
+                        "name": "sender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient domain (e.g. gmail.com)",
+                        "name": "recipient_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag filter as key:value, repeatable; a repeated key matches any of its values",
+                        "name": "tag",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get sync status",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/http.ExportJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/suppression/{email}": {
-            "delete": {
+        "/api/exports/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove email from AWS SES suppression list",
+                "description": "Status and progress of an export job. Once it succeeded the response carries a signed download_url that works without a bearer token until download_expires_at; fetch the job again for a fresh link.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "suppression"
+                    "exports"
                 ],
-                "summary": "Remove email from AWS SES suppression list",
+                "summary": "Get an export job",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Email address",
-                        "name": "email",
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -776,6 +1128,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ExportJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -783,8 +1141,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -793,28 +1160,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/suppression/{email}/status": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Check if email is suppressed in AWS SES",
+                "description": "Cancels a queued or running job, or deletes a finished job together with its file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "suppression"
+                    "exports"
                 ],
-                "summary": "Check email suppression status",
+                "summary": "Cancel or delete an export job",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Email address",
-                        "name": "email",
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -823,7 +1188,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aws.SuppressionStatus"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -835,6 +1203,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -847,128 +1224,2347 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/api/exports/{id}/download": {
             "get": {
-                "description": "Check if the service is running",
+                "description": "Download the file of a succeeded export job through the signed link returned as download_url. No bearer token is needed.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "health"
+                    "exports"
                 ],
-                "summary": "Health check endpoint",
-                "responses": {
+                "summary": "Download an export",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Link expiry (unix time)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Link signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.HealthResponse"
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/ready": {
+        "/api/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/usecase.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/messages/{message_id}": {
             "get": {
-                "description": "Check if the service is ready to serve requests",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every event of one sent message grouped by recipient in the order they happened, with the time since the previous event and since the send, milestone latencies (send to delivery, delivery to first open, ...) and the final state of each recipient",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "monitoring"
+                ],
+                "summary": "Get the lifecycle of a message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this recipient",
+                        "name": "email",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Readiness check endpoint",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.HealthResponse"
+                            "$ref": "#/definitions/sesevent.MessageTimeline"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "aws.SuppressionStatus": {
-            "type": "object",
-            "properties": {
-                "email": {
+        },
+        "/api/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve overall SES metrics with counts and rates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Get overall metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.MetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/metrics/daily": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve daily SES metrics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Get daily metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/sesevent.DailyMetrics"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/metrics/hourly": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve hourly SES metrics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Get hourly metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/sesevent.HourlyMetrics"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/metrics/monthly": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve monthly SES metrics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Get monthly metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events of this provider (ses, sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/sesevent.MonthlyMetrics"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recipients/{email}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Everything known about one email address: its events (newest first, paginated), bounce and complaint counts, last delivery and engagement, suppression state in the AWS and local suppression lists, and the senders that mailed it. The address is matched case-insensitively.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Get the history of a recipient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email address",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number of the events (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of events per page (default: 50, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/aws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get current AWS configuration settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get AWS settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/settings.AWSConfig"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update AWS configuration settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update AWS settings",
+                "parameters": [
+                    {
+                        "description": "AWS settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/settings.AWSConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/aws/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Test AWS SES connection with provided credentials",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Test AWS connection",
+                "parameters": [
+                    {
+                        "description": "AWS settings to test",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/settings.AWSConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/ingest/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue depth, throughput and batch latency of the buffered ingestion pipeline",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get ingestion pipeline stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/retention": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get event log retention settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get retention settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.RetentionSettings"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update event log retention settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update retention settings",
+                "parameters": [
+                    {
+                        "description": "Retention settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.RetentionSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/sinks/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Configured message broker sinks and the events each still has to publish",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get event sink stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/sns/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the SNS topics delivering to this endpoint and their subscription status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get SNS subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/snssubscription.Subscription"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings/timezone": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get application timezone settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get timezone settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/settings.TimezoneConfig"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update application timezone settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update timezone settings",
+                "parameters": [
+                    {
+                        "description": "Timezone settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/settings.TimezoneConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of suppressed emails from suppressions table with pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Get suppressions with pagination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 50, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term for email, reason, or source",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add email to local suppression list and sync to AWS SES",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Add email to suppression list with AWS sync",
+                "parameters": [
+                    {
+                        "description": "Email to suppress",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.AddSuppressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add multiple emails to suppression list at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Bulk add emails to suppression list",
+                "parameters": [
+                    {
+                        "description": "Emails to suppress",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.BulkSuppressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove multiple emails from AWS SES suppression list at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Bulk remove emails from suppression list",
+                "parameters": [
+                    {
+                        "description": "Emails to remove from suppression",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.BulkRemoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trigger manual sync of suppression list from AWS SES to local database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Trigger manual sync from AWS SES",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression/sync/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get last sync time and current sync status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Get sync status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression/{email}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove email from AWS SES suppression list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Remove email from AWS SES suppression list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email address",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/suppression/{email}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check if email is suppressed in AWS SES",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Check email suppression status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email address",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aws.SuppressionStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List outbound webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/webhook.Subscription"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Forward stored events matching the filters to a URL. The response contains the signing secret, which is not shown again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create an outbound webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get an outbound webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the URL and filters. Setting enabled re-enables a subscription that was disabled after repeated failures.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update an outbound webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the subscription together with its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete an outbound webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delivery log of a subscription, newest first, with the outcome of the latest attempt",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List deliveries of a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, succeeded, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Deliveries per page (default: 50, max: 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}/deliveries/{delivery_id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule a pending or failed delivery for an immediate attempt",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/eventbridge/ses": {
+            "post": {
+                "description": "Target for an EventBridge API destination. Accepts one event or an array of events whose detail is an SES event. Authenticated with the configured API key header (X-Api-Key by default).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ingest"
+                ],
+                "summary": "Receive SES events from EventBridge",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/firehose/ses": {
+            "post": {
+                "description": "HTTP endpoint destination for a Firehose stream carrying SES event publishing records. Authenticated with the X-Amz-Firehose-Access-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ingest"
+                ],
+                "summary": "Receive SES events from Kinesis Data Firehose",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configured Firehose access key",
+                        "name": "X-Amz-Firehose-Access-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the service is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Check if the service is ready to serve requests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{provider}": {
+            "post": {
+                "description": "Event webhook for SendGrid (signed event webhook), Mailgun (webhook signing key) and Postmark (basic auth). Events are stored with their provider next to SES events.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ingest"
+                ],
+                "summary": "Receive events from another email service provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider (sendgrid, mailgun, postmark)",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "aws.SuppressionStatus": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "last_update": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "suppressed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "deadletter.Entry": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "stage": {
+                    "$ref": "#/definitions/deadletter.Stage"
+                },
+                "status": {
+                    "$ref": "#/definitions/deadletter.Status"
+                },
+                "topic_arn": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "deadletter.Stage": {
+            "type": "string",
+            "enum": [
+                "parse",
+                "store",
+                "verify"
+            ],
+            "x-enum-varnames": [
+                "StageParse",
+                "StageStore",
+                "StageVerify"
+            ]
+        },
+        "deadletter.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "replayed"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusReplayed"
+            ]
+        },
+        "exportjob.Status": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed",
+                "canceled",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "StatusQueued",
+                "StatusRunning",
+                "StatusSucceeded",
+                "StatusFailed",
+                "StatusCanceled",
+                "StatusExpired"
+            ]
+        },
+        "http.AddSuppressionRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.BulkRemoveRequest": {
+            "type": "object",
+            "required": [
+                "emails"
+            ],
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.BulkSuppressionRequest": {
+            "type": "object",
+            "required": [
+                "emails"
+            ],
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.ExportJobResponse": {
+            "type": "object",
+            "properties": {
+                "artifact_size": {
+                    "type": "integer"
+                },
+                "attempt": {
+                    "description": "Attempt counts the claims of the job. A worker only updates the job\nwhile its claim is the current attempt, so a worker that stalled past\nits lease cannot overwrite the one that took over.",
+                    "type": "integer"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_expires_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "estimated_rows": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the file of a succeeded job is deleted",
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "params": {
+                    "description": "Params is the query string the job was submitted with, for display",
+                    "type": "string"
+                },
+                "rows_written": {
+                    "description": "RowsWritten counts exported events, EstimatedRows is the planner's\nestimate of the total when the job started",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/exportjob.Status"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the user who submitted the job; only they and admins see it",
+                    "type": "integer"
+                }
+            }
+        },
+        "http.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.MetricsResponse": {
+            "type": "object",
+            "properties": {
+                "bounce_count": {
+                    "type": "integer"
+                },
+                "bounce_rate": {
+                    "type": "number"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "complaint_count": {
+                    "type": "integer"
+                },
+                "delivery_count": {
+                    "type": "integer"
+                },
+                "delivery_delay_count": {
+                    "type": "integer"
+                },
+                "delivery_rate": {
+                    "type": "number"
+                },
+                "open_count": {
+                    "type": "integer"
+                },
+                "reject_count": {
+                    "type": "integer"
+                },
+                "rendering_failure_count": {
+                    "type": "integer"
+                },
+                "send_count": {
+                    "type": "integer"
+                },
+                "subscription_count": {
+                    "type": "integer"
+                },
+                "total_events": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.ReplayDeadLettersRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.RetentionSettings": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "retention_days": {
+                    "description": "0 = never delete",
+                    "type": "integer"
+                }
+            }
+        },
+        "http.UpdateDeadLetterRequest": {
+            "type": "object",
+            "required": [
+                "payload"
+            ],
+            "properties": {
+                "payload": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is only read on create; a random secret is generated when empty",
+                    "type": "string"
+                },
+                "senders": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "sesevent.DailyMetrics": {
+            "type": "object",
+            "properties": {
+                "bounce_count": {
+                    "type": "integer"
+                },
+                "bounce_rate": {
+                    "type": "number"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "complaint_count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "delivery_count": {
+                    "type": "integer"
+                },
+                "delivery_delay_count": {
+                    "type": "integer"
+                },
+                "delivery_rate": {
+                    "type": "number"
+                },
+                "open_count": {
+                    "type": "integer"
+                },
+                "reject_count": {
+                    "type": "integer"
+                },
+                "rendering_failure_count": {
+                    "type": "integer"
+                },
+                "send_count": {
+                    "type": "integer"
+                },
+                "subscription_count": {
+                    "type": "integer"
+                },
+                "total_events": {
+                    "type": "integer"
+                }
+            }
+        },
+        "sesevent.Event": {
+            "type": "object",
+            "properties": {
+                "bounceSubType": {
+                    "type": "string"
+                },
+                "bounceType": {
+                    "type": "string"
+                },
+                "complaintArrivalDate": {
+                    "type": "string"
+                },
+                "complaintFeedbackType": {
+                    "description": "Complaint feedback report details",
+                    "type": "string"
+                },
+                "complaintSubType": {
+                    "type": "string"
+                },
+                "complaintUserAgent": {
+                    "type": "string"
+                },
+                "configurationSet": {
+                    "description": "Promoted from the SES ses:configuration-set and ses:from-domain tags",
+                    "type": "string"
+                },
+                "contactList": {
+                    "description": "Subscription details",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "delayExpirationTime": {
+                    "type": "string"
+                },
+                "delayType": {
+                    "description": "DeliveryDelay details",
+                    "type": "string"
+                },
+                "diagnosticCode": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "eventKey": {
+                    "description": "EventKey identifies the event for deduplication, see Fingerprint",
+                    "type": "string"
+                },
+                "eventTimestamp": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "feedbackID": {
+                    "type": "string"
+                },
+                "fromDomain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "ipaddress": {
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Engagement (Open/Click) details",
+                    "type": "string"
+                },
+                "linkTags": {
+                    "description": "JSON map",
+                    "type": "string"
+                },
+                "messageID": {
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "description": "OccurredAt is when the event itself happened (open, click, bounce, ...),\nas opposed to EventTimestamp which is when the mail was sent",
                     "type": "string"
                 },
-                "last_update": {
+                "processingTimeMillis": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "description": "Provider is the email service provider that reported the event, see Providers",
+                    "type": "string"
+                },
+                "publishingMechanism": {
+                    "description": "PublishingMechanism is PublishingMechanismEventPublishing or PublishingMechanismNotification",
                     "type": "string"
                 },
                 "reason": {
                     "type": "string"
                 },
-                "suppressed": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "http.AddSuppressionRequest": {
-            "type": "object",
-            "required": [
-                "email"
-            ],
-            "properties": {
-                "email": {
+                "recipients": {
+                    "description": "JSON array",
                     "type": "string"
                 },
-                "reason": {
+                "remoteMtaIp": {
                     "type": "string"
-                }
-            }
-        },
-        "http.BulkRemoveRequest": {
-            "type": "object",
-            "required": [
-                "emails"
-            ],
-            "properties": {
-                "emails": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "http.BulkSuppressionRequest": {
-            "type": "object",
-            "required": [
-                "emails"
-            ],
-            "properties": {
-                "emails": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 },
-                "reason": {
+                "reportingMTA": {
                     "type": "string"
-                }
-            }
-        },
-        "http.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "service": {
+                },
+                "smtpResponse": {
+                    "type": "string"
+                },
+                "snsmessageID": {
+                    "description": "SNSMessageID is the MessageId of the SNS notification that carried the\nevent, or the event ID assigned by another provider",
+                    "type": "string"
+                },
+                "source": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
-                "timestamp": {
+                "subject": {
                     "type": "string"
                 },
-                "version": {
+                "tags": {
+                    "description": "JSON map",
+                    "type": "string"
+                },
+                "templateName": {
+                    "description": "RenderingFailure details (the error message is stored in Reason)",
+                    "type": "string"
+                },
+                "topicPreferences": {
+                    "description": "JSON {\"new\": ..., \"old\": ...}",
+                    "type": "string"
+                },
+                "userAgent": {
                     "type": "string"
                 }
             }
         },
-        "http.MetricsResponse": {
+        "sesevent.HourlyMetrics": {
             "type": "object",
             "properties": {
                 "bounce_count": {
@@ -986,68 +3582,80 @@ const docTemplate = `{
                 "delivery_count": {
                     "type": "integer"
                 },
+                "delivery_delay_count": {
+                    "type": "integer"
+                },
                 "delivery_rate": {
                     "type": "number"
                 },
+                "hour": {
+                    "type": "string"
+                },
                 "open_count": {
                     "type": "integer"
                 },
+                "reject_count": {
+                    "type": "integer"
+                },
+                "rendering_failure_count": {
+                    "type": "integer"
+                },
                 "send_count": {
                     "type": "integer"
                 },
+                "subscription_count": {
+                    "type": "integer"
+                },
                 "total_events": {
                     "type": "integer"
                 }
             }
         },
-        "http.RetentionSettings": {
+        "sesevent.LinkClickStats": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
+                "last_clicked_at": {
+                    "type": "string"
                 },
-                "retention_days": {
-                    "description": "0 = never delete",
+                "link": {
+                    "type": "string"
+                },
+                "total_clicks": {
+                    "type": "integer"
+                },
+                "unique_clicks": {
                     "type": "integer"
                 }
             }
         },
-        "sesevent.DailyMetrics": {
+        "sesevent.MessageTimeline": {
             "type": "object",
             "properties": {
-                "bounce_count": {
-                    "type": "integer"
-                },
-                "bounce_rate": {
-                    "type": "number"
-                },
-                "click_count": {
-                    "type": "integer"
-                },
-                "complaint_count": {
-                    "type": "integer"
-                },
-                "date": {
+                "message_id": {
                     "type": "string"
                 },
-                "delivery_count": {
-                    "type": "integer"
+                "provider": {
+                    "type": "string"
                 },
-                "delivery_rate": {
-                    "type": "number"
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sesevent.RecipientTimeline"
+                    }
                 },
-                "open_count": {
-                    "type": "integer"
+                "sent_at": {
+                    "description": "SentAt is when the provider accepted the message",
+                    "type": "string"
                 },
-                "send_count": {
-                    "type": "integer"
+                "source": {
+                    "type": "string"
                 },
-                "total_events": {
-                    "type": "integer"
+                "subject": {
+                    "type": "string"
                 }
             }
         },
-        "sesevent.HourlyMetrics": {
+        "sesevent.MonthlyMetrics": {
             "type": "object",
             "properties": {
                 "bounce_count": {
@@ -1065,54 +3673,109 @@ const docTemplate = `{
                 "delivery_count": {
                     "type": "integer"
                 },
+                "delivery_delay_count": {
+                    "type": "integer"
+                },
                 "delivery_rate": {
                     "type": "number"
                 },
-                "hour": {
+                "month": {
                     "type": "string"
                 },
                 "open_count": {
                     "type": "integer"
                 },
+                "reject_count": {
+                    "type": "integer"
+                },
+                "rendering_failure_count": {
+                    "type": "integer"
+                },
                 "send_count": {
                     "type": "integer"
                 },
+                "subscription_count": {
+                    "type": "integer"
+                },
                 "total_events": {
                     "type": "integer"
                 }
             }
         },
-        "sesevent.MonthlyMetrics": {
+        "sesevent.RecipientTimeline": {
             "type": "object",
             "properties": {
-                "bounce_count": {
+                "bounced_at": {
+                    "type": "string"
+                },
+                "clicks": {
                     "type": "integer"
                 },
-                "bounce_rate": {
-                    "type": "number"
+                "complained_at": {
+                    "type": "string"
                 },
-                "click_count": {
-                    "type": "integer"
+                "delivered": {
+                    "description": "Delivered reports whether the receiving server accepted the message",
+                    "type": "boolean"
                 },
-                "complaint_count": {
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_to_complaint_ms": {
                     "type": "integer"
                 },
-                "delivery_count": {
+                "delivery_to_first_open_ms": {
                     "type": "integer"
                 },
-                "delivery_rate": {
-                    "type": "number"
+                "email": {
+                    "description": "Email is empty for the opens and clicks of a message with several\nrecipients, which SES does not attribute to one of them",
+                    "type": "string"
                 },
-                "month": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sesevent.TimelineEvent"
+                    }
+                },
+                "final_state": {
                     "type": "string"
                 },
-                "open_count": {
+                "first_clicked_at": {
+                    "type": "string"
+                },
+                "first_open_to_first_click_ms": {
                     "type": "integer"
                 },
-                "send_count": {
+                "first_opened_at": {
+                    "type": "string"
+                },
+                "opens": {
                     "type": "integer"
                 },
-                "total_events": {
+                "send_to_bounce_ms": {
+                    "type": "integer"
+                },
+                "send_to_delivery_ms": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "sesevent.TimelineEvent": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/sesevent.Event"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "since_previous_ms": {
+                    "type": "integer"
+                },
+                "since_send_ms": {
                     "type": "integer"
                 }
             }
@@ -1131,6 +3794,79 @@ const docTemplate = `{
                 },
                 "secret_key": {
                     "type": "string"
+                },
+                "sync_interval": {
+                    "description": "in minutes",
+                    "type": "integer"
+                }
+            }
+        },
+        "settings.TimezoneConfig": {
+            "type": "object",
+            "properties": {
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "snssubscription.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "confirmed",
+                "unsubscribed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusConfirmed",
+                "StatusUnsubscribed",
+                "StatusFailed"
+            ]
+        },
+        "snssubscription.Subscription": {
+            "type": "object",
+            "properties": {
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_message_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/snssubscription.Status"
+                },
+                "subscription_arn": {
+                    "type": "string"
+                },
+                "topic_arn": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.DeadLetterReplayResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/deadletter.Status"
+                },
+                "stored": {
+                    "type": "integer"
                 }
             }
         },
@@ -1175,6 +3911,56 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "webhook.Subscription": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "description": "EventTypes are SES event types, e.g. Bounce and Complaint",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "senders": {
+                    "description": "Senders are sender addresses or domains",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "description": "Tags must all be present on the event",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -31,7 +31,105 @@ type Config struct {
 		AccessKey   string `yaml:"access_key"`
 		SecretKey   string `yaml:"secret_key"`
 		SNSTopicARN string `yaml:"sns_topic_arn"`
+		// SNSSkipVerify disables SNS signature checks (local testing only)
+		SNSSkipVerify bool `yaml:"sns_skip_verify"`
+
+		// IngestMode is how SES events arrive: webhook (POST /sns/ses), sqs
+		// (poll a queue subscribed to the topic, the webhook is not exposed) or both
+		IngestMode string `yaml:"ingest_mode"`
+		// SQSQueueURL is the queue polled in sqs mode
+		SQSQueueURL string `yaml:"sqs_queue_url"`
+		// SQSEndpoint overrides the SQS endpoint, e.g. for ElasticMQ
+		SQSEndpoint              string `yaml:"sqs_endpoint"`
+		SQSWorkers               int    `yaml:"sqs_workers"`
+		SQSMaxMessages           int    `yaml:"sqs_max_messages"`
+		SQSWaitTimeSeconds       int    `yaml:"sqs_wait_time_seconds"`
+		SQSVisibilityTimeoutSecs int    `yaml:"sqs_visibility_timeout_seconds"`
+
+		// FirehoseAccessKey enables POST /firehose/ses for Firehose HTTP endpoint deliveries
+		FirehoseAccessKey string `yaml:"firehose_access_key"`
+		// EventBridgeAPIKey enables POST /eventbridge/ses for EventBridge API destinations
+		EventBridgeAPIKey       string `yaml:"eventbridge_api_key"`
+		EventBridgeAPIKeyHeader string `yaml:"eventbridge_api_key_header"`
 	} `yaml:"aws"`
+
+	// Ingest controls the buffered pipeline between the webhooks and the database
+	Ingest struct {
+		Async           bool `yaml:"async"`
+		QueueSize       int  `yaml:"queue_size"`
+		Workers         int  `yaml:"workers"`
+		BatchSize       int  `yaml:"batch_size"`
+		FlushIntervalMs int  `yaml:"flush_interval_ms"`
+	} `yaml:"ingest"`
+
+	// Storage is the object store used for the raw event archive and export files
+	Storage struct {
+		Backend        string `yaml:"backend"` // local or s3
+		LocalPath      string `yaml:"local_path"`
+		S3Bucket       string `yaml:"s3_bucket"`
+		S3Region       string `yaml:"s3_region"`
+		S3Endpoint     string `yaml:"s3_endpoint"`
+		S3AccessKey    string `yaml:"s3_access_key"`
+		S3SecretKey    string `yaml:"s3_secret_key"`
+		S3UsePathStyle bool   `yaml:"s3_use_path_style"`
+	} `yaml:"storage"`
+
+	Archive struct {
+		Enabled              bool   `yaml:"enabled"`
+		Prefix               string `yaml:"prefix"`
+		FlushIntervalSeconds int    `yaml:"flush_interval_seconds"`
+		MaxBatch             int    `yaml:"max_batch"`
+	} `yaml:"archive"`
+
+	// Exports controls the background export jobs at /api/exports
+	Exports struct {
+		Enabled bool   `yaml:"enabled"`
+		Workers int    `yaml:"workers"`
+		Prefix  string `yaml:"prefix"`
+		// RetentionHours is how long a finished export can be downloaded
+		RetentionHours int `yaml:"retention_hours"`
+		// LinkTTLSeconds is how long a signed download link stays valid
+		LinkTTLSeconds int `yaml:"link_ttl_seconds"`
+		// SigningKey signs download links; the JWT secret is used when empty
+		SigningKey string `yaml:"signing_key"`
+	} `yaml:"exports"`
+
+	// Providers holds the webhook credentials of other email service
+	// providers. POST /webhooks/:provider accepts a provider once it is set.
+	Providers struct {
+		// SendGridPublicKey is the verification key of the Signed Event Webhook
+		SendGridPublicKey string `yaml:"sendgrid_public_key"`
+		// MailgunSigningKey is the HTTP webhook signing key
+		MailgunSigningKey string `yaml:"mailgun_signing_key"`
+		// Postmark webhooks authenticate with basic auth credentials in the webhook URL
+		PostmarkUsername string `yaml:"postmark_username"`
+		PostmarkPassword string `yaml:"postmark_password"`
+	} `yaml:"providers"`
+
+	// Webhooks controls delivery of stored events to outbound webhook subscriptions
+	Webhooks struct {
+		Enabled              bool `yaml:"enabled"`
+		Workers              int  `yaml:"workers"`
+		MaxAttempts          int  `yaml:"max_attempts"`
+		TimeoutSeconds       int  `yaml:"timeout_seconds"`
+		DisableAfterFailures int  `yaml:"disable_after_failures"`
+		RetentionDays        int  `yaml:"retention_days"`
+	} `yaml:"webhooks"`
+
+	// Sinks publishes stored events to message brokers through the event
+	// outbox. Each broker is enabled by its address.
+	Sinks struct {
+		// TopicTemplate names the topic of an event; {event_type} and {provider} are replaced
+		TopicTemplate string `yaml:"topic_template"`
+		// Topics overrides the topic of single event types, e.g. "Bounce=ses.bounces,Complaint=ses.complaints"
+		Topics       string `yaml:"topics"`
+		BatchSize    int    `yaml:"batch_size"`
+		NATSURL      string `yaml:"nats_url"`
+		NATSStream   string `yaml:"nats_stream"`
+		KafkaBrokers string `yaml:"kafka_brokers"` // comma-separated host:port list
+		RedisURL     string `yaml:"redis_url"`
+		RedisMaxLen  int    `yaml:"redis_max_len"`
+	} `yaml:"sinks"`
 }
 
 func Load(path string) (*Config, error) {
@@ -55,6 +153,65 @@ func Load(path string) (*Config, error) {
 	cfg.AWS.AccessKey = getEnv("AWS_ACCESS_KEY", "")
 	cfg.AWS.SecretKey = getEnv("AWS_SECRET_KEY", "")
 	cfg.AWS.SNSTopicARN = getEnv("SNS_TOPIC_ARN", "")
+	cfg.AWS.SNSSkipVerify = getEnvBool("SNS_SKIP_VERIFY", false)
+	cfg.AWS.IngestMode = getEnv("INGEST_MODE", "")
+	cfg.AWS.SQSQueueURL = getEnv("SQS_QUEUE_URL", "")
+	cfg.AWS.SQSEndpoint = getEnv("SQS_ENDPOINT", "")
+	cfg.AWS.SQSWorkers = getEnvInt("SQS_WORKERS", 0)
+	cfg.AWS.SQSMaxMessages = getEnvInt("SQS_MAX_MESSAGES", 0)
+	cfg.AWS.SQSWaitTimeSeconds = getEnvInt("SQS_WAIT_TIME_SECONDS", 0)
+	cfg.AWS.SQSVisibilityTimeoutSecs = getEnvInt("SQS_VISIBILITY_TIMEOUT_SECONDS", 0)
+	cfg.AWS.FirehoseAccessKey = getEnv("FIREHOSE_ACCESS_KEY", "")
+	cfg.AWS.EventBridgeAPIKey = getEnv("EVENTBRIDGE_API_KEY", "")
+	cfg.AWS.EventBridgeAPIKeyHeader = getEnv("EVENTBRIDGE_API_KEY_HEADER", "")
+
+	cfg.Ingest.Async = getEnvBool("INGEST_ASYNC", true)
+	cfg.Ingest.QueueSize = getEnvInt("INGEST_QUEUE_SIZE", 0)
+	cfg.Ingest.Workers = getEnvInt("INGEST_WORKERS", 0)
+	cfg.Ingest.BatchSize = getEnvInt("INGEST_BATCH_SIZE", 0)
+	cfg.Ingest.FlushIntervalMs = getEnvInt("INGEST_FLUSH_INTERVAL_MS", 0)
+
+	cfg.Storage.Backend = getEnv("STORAGE_BACKEND", "")
+	cfg.Storage.LocalPath = getEnv("STORAGE_LOCAL_PATH", "")
+	cfg.Storage.S3Bucket = getEnv("STORAGE_S3_BUCKET", "")
+	cfg.Storage.S3Region = getEnv("STORAGE_S3_REGION", "")
+	cfg.Storage.S3Endpoint = getEnv("STORAGE_S3_ENDPOINT", "")
+	cfg.Storage.S3AccessKey = getEnv("STORAGE_S3_ACCESS_KEY", "")
+	cfg.Storage.S3SecretKey = getEnv("STORAGE_S3_SECRET_KEY", "")
+	cfg.Storage.S3UsePathStyle = getEnvBool("STORAGE_S3_USE_PATH_STYLE", false)
+
+	cfg.Archive.Enabled = getEnvBool("ARCHIVE_ENABLED", false)
+	cfg.Archive.Prefix = getEnv("ARCHIVE_PREFIX", "")
+	cfg.Archive.FlushIntervalSeconds = getEnvInt("ARCHIVE_FLUSH_INTERVAL_SECONDS", 0)
+	cfg.Archive.MaxBatch = getEnvInt("ARCHIVE_MAX_BATCH", 0)
+
+	cfg.Exports.Enabled = getEnvBool("EXPORTS_ENABLED", true)
+	cfg.Exports.Workers = getEnvInt("EXPORT_WORKERS", 0)
+	cfg.Exports.Prefix = getEnv("EXPORT_PREFIX", "")
+	cfg.Exports.RetentionHours = getEnvInt("EXPORT_RETENTION_HOURS", 0)
+	cfg.Exports.LinkTTLSeconds = getEnvInt("EXPORT_LINK_TTL_SECONDS", 0)
+	cfg.Exports.SigningKey = getEnv("EXPORT_SIGNING_KEY", "")
+
+	cfg.Providers.SendGridPublicKey = getEnv("SENDGRID_WEBHOOK_PUBLIC_KEY", "")
+	cfg.Providers.MailgunSigningKey = getEnv("MAILGUN_WEBHOOK_SIGNING_KEY", "")
+	cfg.Providers.PostmarkUsername = getEnv("POSTMARK_WEBHOOK_USERNAME", "")
+	cfg.Providers.PostmarkPassword = getEnv("POSTMARK_WEBHOOK_PASSWORD", "")
+
+	cfg.Webhooks.Enabled = getEnvBool("WEBHOOKS_ENABLED", true)
+	cfg.Webhooks.Workers = getEnvInt("WEBHOOK_WORKERS", 0)
+	cfg.Webhooks.MaxAttempts = getEnvInt("WEBHOOK_MAX_ATTEMPTS", 0)
+	cfg.Webhooks.TimeoutSeconds = getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 0)
+	cfg.Webhooks.DisableAfterFailures = getEnvInt("WEBHOOK_DISABLE_AFTER_FAILURES", 0)
+	cfg.Webhooks.RetentionDays = getEnvInt("WEBHOOK_RETENTION_DAYS", 0)
+
+	cfg.Sinks.TopicTemplate = getEnv("SINK_TOPIC_TEMPLATE", "")
+	cfg.Sinks.Topics = getEnv("SINK_TOPICS", "")
+	cfg.Sinks.BatchSize = getEnvInt("SINK_BATCH_SIZE", 0)
+	cfg.Sinks.NATSURL = getEnv("NATS_URL", "")
+	cfg.Sinks.NATSStream = getEnv("NATS_STREAM", "")
+	cfg.Sinks.KafkaBrokers = getEnv("KAFKA_BROKERS", "")
+	cfg.Sinks.RedisURL = getEnv("REDIS_URL", "")
+	cfg.Sinks.RedisMaxLen = getEnvInt("REDIS_STREAM_MAX_LEN", 0)
 
 	// If environment variables are not set, fallback to YAML file
 	if cfg.App.Name == "" || cfg.Database.Host == "" {
@@ -110,6 +267,169 @@ func Load(path string) (*Config, error) {
 				if cfg.AWS.SNSTopicARN == "" {
 					cfg.AWS.SNSTopicARN = yamlCfg.AWS.SNSTopicARN
 				}
+				if os.Getenv("SNS_SKIP_VERIFY") == "" {
+					cfg.AWS.SNSSkipVerify = yamlCfg.AWS.SNSSkipVerify
+				}
+				if cfg.AWS.IngestMode == "" {
+					cfg.AWS.IngestMode = yamlCfg.AWS.IngestMode
+				}
+				if cfg.AWS.SQSQueueURL == "" {
+					cfg.AWS.SQSQueueURL = yamlCfg.AWS.SQSQueueURL
+				}
+				if cfg.AWS.SQSEndpoint == "" {
+					cfg.AWS.SQSEndpoint = yamlCfg.AWS.SQSEndpoint
+				}
+				if cfg.AWS.SQSWorkers == 0 {
+					cfg.AWS.SQSWorkers = yamlCfg.AWS.SQSWorkers
+				}
+				if cfg.AWS.SQSMaxMessages == 0 {
+					cfg.AWS.SQSMaxMessages = yamlCfg.AWS.SQSMaxMessages
+				}
+				if cfg.AWS.SQSWaitTimeSeconds == 0 {
+					cfg.AWS.SQSWaitTimeSeconds = yamlCfg.AWS.SQSWaitTimeSeconds
+				}
+				if cfg.AWS.SQSVisibilityTimeoutSecs == 0 {
+					cfg.AWS.SQSVisibilityTimeoutSecs = yamlCfg.AWS.SQSVisibilityTimeoutSecs
+				}
+				if cfg.AWS.FirehoseAccessKey == "" {
+					cfg.AWS.FirehoseAccessKey = yamlCfg.AWS.FirehoseAccessKey
+				}
+				if cfg.AWS.EventBridgeAPIKey == "" {
+					cfg.AWS.EventBridgeAPIKey = yamlCfg.AWS.EventBridgeAPIKey
+				}
+				if cfg.AWS.EventBridgeAPIKeyHeader == "" {
+					cfg.AWS.EventBridgeAPIKeyHeader = yamlCfg.AWS.EventBridgeAPIKeyHeader
+				}
+
+				if os.Getenv("INGEST_ASYNC") == "" {
+					cfg.Ingest.Async = yamlCfg.Ingest.Async
+				}
+				if cfg.Ingest.QueueSize == 0 {
+					cfg.Ingest.QueueSize = yamlCfg.Ingest.QueueSize
+				}
+				if cfg.Ingest.Workers == 0 {
+					cfg.Ingest.Workers = yamlCfg.Ingest.Workers
+				}
+				if cfg.Ingest.BatchSize == 0 {
+					cfg.Ingest.BatchSize = yamlCfg.Ingest.BatchSize
+				}
+				if cfg.Ingest.FlushIntervalMs == 0 {
+					cfg.Ingest.FlushIntervalMs = yamlCfg.Ingest.FlushIntervalMs
+				}
+
+				if cfg.Storage.Backend == "" {
+					cfg.Storage.Backend = yamlCfg.Storage.Backend
+				}
+				if cfg.Storage.LocalPath == "" {
+					cfg.Storage.LocalPath = yamlCfg.Storage.LocalPath
+				}
+				if cfg.Storage.S3Bucket == "" {
+					cfg.Storage.S3Bucket = yamlCfg.Storage.S3Bucket
+				}
+				if cfg.Storage.S3Region == "" {
+					cfg.Storage.S3Region = yamlCfg.Storage.S3Region
+				}
+				if cfg.Storage.S3Endpoint == "" {
+					cfg.Storage.S3Endpoint = yamlCfg.Storage.S3Endpoint
+				}
+				if cfg.Storage.S3AccessKey == "" {
+					cfg.Storage.S3AccessKey = yamlCfg.Storage.S3AccessKey
+				}
+				if cfg.Storage.S3SecretKey == "" {
+					cfg.Storage.S3SecretKey = yamlCfg.Storage.S3SecretKey
+				}
+				if os.Getenv("STORAGE_S3_USE_PATH_STYLE") == "" {
+					cfg.Storage.S3UsePathStyle = yamlCfg.Storage.S3UsePathStyle
+				}
+
+				if os.Getenv("ARCHIVE_ENABLED") == "" {
+					cfg.Archive.Enabled = yamlCfg.Archive.Enabled
+				}
+				if cfg.Archive.Prefix == "" {
+					cfg.Archive.Prefix = yamlCfg.Archive.Prefix
+				}
+				if cfg.Archive.FlushIntervalSeconds == 0 {
+					cfg.Archive.FlushIntervalSeconds = yamlCfg.Archive.FlushIntervalSeconds
+				}
+				if cfg.Archive.MaxBatch == 0 {
+					cfg.Archive.MaxBatch = yamlCfg.Archive.MaxBatch
+				}
+
+				if os.Getenv("EXPORTS_ENABLED") == "" {
+					cfg.Exports.Enabled = yamlCfg.Exports.Enabled
+				}
+				if cfg.Exports.Workers == 0 {
+					cfg.Exports.Workers = yamlCfg.Exports.Workers
+				}
+				if cfg.Exports.Prefix == "" {
+					cfg.Exports.Prefix = yamlCfg.Exports.Prefix
+				}
+				if cfg.Exports.RetentionHours == 0 {
+					cfg.Exports.RetentionHours = yamlCfg.Exports.RetentionHours
+				}
+				if cfg.Exports.LinkTTLSeconds == 0 {
+					cfg.Exports.LinkTTLSeconds = yamlCfg.Exports.LinkTTLSeconds
+				}
+				if cfg.Exports.SigningKey == "" {
+					cfg.Exports.SigningKey = yamlCfg.Exports.SigningKey
+				}
+
+				if cfg.Providers.SendGridPublicKey == "" {
+					cfg.Providers.SendGridPublicKey = yamlCfg.Providers.SendGridPublicKey
+				}
+				if cfg.Providers.MailgunSigningKey == "" {
+					cfg.Providers.MailgunSigningKey = yamlCfg.Providers.MailgunSigningKey
+				}
+				if cfg.Providers.PostmarkUsername == "" {
+					cfg.Providers.PostmarkUsername = yamlCfg.Providers.PostmarkUsername
+				}
+				if cfg.Providers.PostmarkPassword == "" {
+					cfg.Providers.PostmarkPassword = yamlCfg.Providers.PostmarkPassword
+				}
+
+				if os.Getenv("WEBHOOKS_ENABLED") == "" {
+					cfg.Webhooks.Enabled = yamlCfg.Webhooks.Enabled
+				}
+				if cfg.Webhooks.Workers == 0 {
+					cfg.Webhooks.Workers = yamlCfg.Webhooks.Workers
+				}
+				if cfg.Webhooks.MaxAttempts == 0 {
+					cfg.Webhooks.MaxAttempts = yamlCfg.Webhooks.MaxAttempts
+				}
+				if cfg.Webhooks.TimeoutSeconds == 0 {
+					cfg.Webhooks.TimeoutSeconds = yamlCfg.Webhooks.TimeoutSeconds
+				}
+				if cfg.Webhooks.DisableAfterFailures == 0 {
+					cfg.Webhooks.DisableAfterFailures = yamlCfg.Webhooks.DisableAfterFailures
+				}
+				if cfg.Webhooks.RetentionDays == 0 {
+					cfg.Webhooks.RetentionDays = yamlCfg.Webhooks.RetentionDays
+				}
+
+				if cfg.Sinks.TopicTemplate == "" {
+					cfg.Sinks.TopicTemplate = yamlCfg.Sinks.TopicTemplate
+				}
+				if cfg.Sinks.Topics == "" {
+					cfg.Sinks.Topics = yamlCfg.Sinks.Topics
+				}
+				if cfg.Sinks.BatchSize == 0 {
+					cfg.Sinks.BatchSize = yamlCfg.Sinks.BatchSize
+				}
+				if cfg.Sinks.NATSURL == "" {
+					cfg.Sinks.NATSURL = yamlCfg.Sinks.NATSURL
+				}
+				if cfg.Sinks.NATSStream == "" {
+					cfg.Sinks.NATSStream = yamlCfg.Sinks.NATSStream
+				}
+				if cfg.Sinks.KafkaBrokers == "" {
+					cfg.Sinks.KafkaBrokers = yamlCfg.Sinks.KafkaBrokers
+				}
+				if cfg.Sinks.RedisURL == "" {
+					cfg.Sinks.RedisURL = yamlCfg.Sinks.RedisURL
+				}
+				if cfg.Sinks.RedisMaxLen == 0 {
+					cfg.Sinks.RedisMaxLen = yamlCfg.Sinks.RedisMaxLen
+				}
 			}
 		}
 	}
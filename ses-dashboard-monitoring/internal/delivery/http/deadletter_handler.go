@@ -0,0 +1,246 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxDeadLetterReplayBatch = 500
+
+type DeadLetterHandler struct {
+	uc *usecase.DeadLetterUsecase
+}
+
+func NewDeadLetterHandler(uc *usecase.DeadLetterUsecase) *DeadLetterHandler {
+	return &DeadLetterHandler{uc: uc}
+}
+
+type UpdateDeadLetterRequest struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+type ReplayDeadLettersRequest struct {
+	IDs   []int64 `json:"ids"`
+	Limit int     `json:"limit"`
+}
+
+// GetDeadLetters godoc
+// @Summary List dead-lettered payloads
+// @Description List SES payloads that failed to process, newest first
+// @Tags deadletters
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (pending, replayed)"
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param limit query int false "Entries per page (default: 50, max: 500)" minimum(1) maximum(500)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/deadletters [get]
+func (h *DeadLetterHandler) GetDeadLetters(c *gin.Context) {
+	page := 1
+	limit := 50
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	entries, total, err := h.uc.List(c.Request.Context(), deadletter.Status(c.Query("status")), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []*deadletter.Entry{}
+	}
+
+	totalPages := (total + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"deadletters": entries,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": totalPages,
+			"hasNext":    page < totalPages,
+			"hasPrev":    page > 1,
+		},
+	})
+}
+
+// GetDeadLetter godoc
+// @Summary Get a dead-lettered payload
+// @Tags deadletters
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Entry ID"
+// @Success 200 {object} deadletter.Entry
+// @Failure 404 {object} map[string]string
+// @Router /api/deadletters/{id} [get]
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// UpdateDeadLetter godoc
+// @Summary Edit and retry a dead-lettered payload
+// @Description Replace the stored SES payload and replay it immediately
+// @Tags deadletters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Entry ID"
+// @Param request body UpdateDeadLetterRequest true "Corrected SES event JSON"
+// @Success 200 {object} usecase.DeadLetterReplayResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/deadletters/{id} [put]
+func (h *DeadLetterHandler) UpdateDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDeadLetterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.uc.UpdateAndReplay(c.Request.Context(), id, req.Payload)
+	if err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// RetryDeadLetter godoc
+// @Summary Retry a dead-lettered payload
+// @Tags deadletters
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Entry ID"
+// @Success 200 {object} usecase.DeadLetterReplayResult
+// @Failure 404 {object} map[string]string
+// @Router /api/deadletters/{id}/retry [post]
+func (h *DeadLetterHandler) RetryDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.uc.Replay(c.Request.Context(), id)
+	if err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ReplayDeadLetters godoc
+// @Summary Bulk replay dead-lettered payloads
+// @Description Replay the given entries, or the oldest pending entries when no IDs are given
+// @Tags deadletters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ReplayDeadLettersRequest false "Entry IDs, or a limit for pending entries (default and max: 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/deadletters/replay [post]
+func (h *DeadLetterHandler) ReplayDeadLetters(c *gin.Context) {
+	var req ReplayDeadLettersRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if len(req.IDs) > maxDeadLetterReplayBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 500 entries allowed per replay"})
+		return
+	}
+	if req.Limit <= 0 || req.Limit > maxDeadLetterReplayBatch {
+		req.Limit = maxDeadLetterReplayBatch
+	}
+
+	results, err := h.uc.ReplayMany(c.Request.Context(), req.IDs, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "results": results})
+		return
+	}
+
+	replayed := 0
+	for _, result := range results {
+		if result.Status == deadletter.StatusReplayed {
+			replayed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"total":    len(results),
+		"replayed": replayed,
+		"failed":   len(results) - replayed,
+		"results":  results,
+	})
+}
+
+// DiscardDeadLetter godoc
+// @Summary Discard a dead-lettered payload
+// @Tags deadletters
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/deadletters/{id} [delete]
+func (h *DeadLetterHandler) DiscardDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Discard(c.Request.Context(), id); err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dead-letter entry discarded"})
+}
+
+func deadLetterID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dead-letter ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func deadLetterError(c *gin.Context, err error) {
+	if errors.Is(err, deadletter.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/infrastructure/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportEvents godoc
+// @Summary Export SES events
+// @Description Download every event matching the filters, newest first, as CSV, NDJSON or XLSX. Rows are streamed from a database cursor, so exports of any size start immediately. Times are converted to the configured timezone. If reading fails midway the connection is closed without finishing the response, so a truncated download is never mistaken for a complete one.
+// @Tags monitoring
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "Export format (default: csv)" Enums(csv, ndjson, xlsx)
+// @Param columns query []string false "Columns in order, repeatable or comma separated (default: id, event_timestamp, occurred_at, event_type, message_id, email, source, subject, status, bounce_type, bounce_sub_type, diagnostic_code, reporting_mta, complaint_feedback_type, provider)" collectionFormat(multi)
+// @Param q query string false "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)"
+// @Param search query string false "Search email, subject or source"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param event_type query []string false "Event types, repeatable or comma separated (e.g. Bounce,Complaint)" collectionFormat(multi)
+// @Param bounce_type query string false "Bounce type (Permanent, Transient, Undetermined)"
+// @Param bounce_sub_type query string false "Bounce subtype (e.g. General, NoEmail, MailboxFull)"
+// @Param status query string false "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)"
+// @Param sender query string false "Sender address, local@ for a local part at any domain or @domain for any address at a domain"
+// @Param recipient_domain query string false "Recipient domain (e.g. gmail.com)"
+// @Param reporting_mta query string false "Part of the reporting MTA"
+// @Param feedback_type query string false "Complaint feedback type (e.g. abuse, not-spam)"
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
+// @Param tag query []string false "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)" collectionFormat(multi)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/events/export [get]
+func (h *MonitoringHandler) ExportEvents(c *gin.Context) {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		filterError(c, err)
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatCSV))
+	if !export.IsValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected csv, ndjson or xlsx"})
+		return
+	}
+
+	columns, err := export.ParseColumns(parseListQuery(c, "columns"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, err := time.LoadLocation(h.getTimezoneFromCache())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("events-%s.%s", time.Now().In(loc).Format("20060102-150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, c.Writer, columns, loc)
+	if err == nil {
+		err = h.uc.ForEachEvent(c.Request.Context(), filter, writer.Write)
+		if err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		// The writers buffer, so an early error such as a failing query
+		// still gets a proper error response
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if c.Request.Context().Err() == nil {
+			log.Printf("Event export failed: %v", err)
+		}
+		abortResponse(c)
+	}
+}
+
+// parseListQuery reads a query parameter that is repeatable or comma separated
+func parseListQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// abortResponse closes the connection of a response whose status has
+// already been sent, so the client sees an incomplete transfer instead of a
+// successful but truncated body
+func abortResponse(c *gin.Context) {
+	// gin refuses to hijack a written response; the net/http writer below it does not
+	var w http.ResponseWriter = c.Writer
+	if unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter }); ok {
+		w = unwrapper.Unwrap()
+	}
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		// HTTP/2 connections cannot be hijacked
+		log.Printf("Cannot abort response: %v", err)
+		return
+	}
+	conn.Close()
+}
@@ -0,0 +1,258 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second
+	// streamPollInterval picks up events stored by other API instances,
+	// whose hooks do not wake up the streams of this one
+	streamPollInterval = 5 * time.Second
+	streamBatchSize    = 500
+	// streamRetryMs is how long browsers wait before reconnecting
+	streamRetryMs = 3000
+	// streamSettleWindow is how long the stream keeps looking behind the
+	// events it sent for events with lower IDs. The ingest workers commit in
+	// parallel, so a lower ID can become visible after a higher one.
+	streamSettleWindow = 30 * time.Second
+)
+
+// streamWindow tracks the position of an event stream. Events up to floor
+// are done; the events sent above it within the settle window are excluded
+// from the next read, which starts at floor.
+type streamWindow struct {
+	floor int64
+	sent  []sentEvent
+}
+
+type sentEvent struct {
+	id     int64
+	sentAt time.Time
+}
+
+func (w *streamWindow) add(id int64, at time.Time) {
+	w.sent = append(w.sent, sentEvent{id: id, sentAt: at})
+}
+
+// exclude returns the IDs sent above floor
+func (w *streamWindow) exclude() []int64 {
+	ids := make([]int64, len(w.sent))
+	for i, e := range w.sent {
+		ids[i] = e.id
+	}
+	return ids
+}
+
+// settle moves floor past the events sent longer than streamSettleWindow
+// ago. Any lower ID still missing by then belongs to a rolled back insert.
+func (w *streamWindow) settle(now time.Time) {
+	kept := w.sent[:0]
+	for _, e := range w.sent {
+		if now.Sub(e.sentAt) >= streamSettleWindow && e.id > w.floor {
+			w.floor = e.id
+		}
+	}
+	for _, e := range w.sent {
+		if e.id > w.floor {
+			kept = append(kept, e)
+		}
+	}
+	w.sent = kept
+}
+
+// StreamEvents godoc
+// @Summary Stream new SES events
+// @Description Server-Sent Events stream of newly stored events, oldest first. Each event is sent as a message whose id is the event ID and whose data is the event JSON; a "heartbeat" event is sent every 15 seconds. A new stream starts with the next stored event; after a reconnect the Last-Event-ID header (or last_event_id) resumes after the last received event and repeats events of the last 30 seconds that may have been missed, so clients should ignore IDs they already have.
+// @Tags monitoring
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param access_token query string false "Stream token from /api/events/stream/token, for clients that cannot set headers"
+// @Param Last-Event-ID header int false "ID of the last received event"
+// @Param last_event_id query int false "ID of the last received event, for clients that cannot set headers"
+// @Param q query string false "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)"
+// @Param search query string false "Search email, subject or source"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param event_type query []string false "Event types, repeatable or comma separated (e.g. Bounce,Complaint)" collectionFormat(multi)
+// @Param bounce_type query string false "Bounce type (Permanent, Transient, Undetermined)"
+// @Param bounce_sub_type query string false "Bounce subtype (e.g. General, NoEmail, MailboxFull)"
+// @Param status query string false "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)"
+// @Param sender query string false "Sender address, local@ for a local part at any domain or @domain for any address at a domain"
+// @Param recipient_domain query string false "Recipient domain (e.g. gmail.com)"
+// @Param reporting_mta query string false "Part of the reporting MTA"
+// @Param feedback_type query string false "Complaint feedback type (e.g. abuse, not-spam)"
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
+// @Param tag query []string false "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)" collectionFormat(multi)
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/events/stream [get]
+func (h *MonitoringHandler) StreamEvents(c *gin.Context) {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		filterError(c, err)
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var window streamWindow
+	if lastEventID != "" {
+		lastID, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || lastID < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		// Events with lower IDs may have been committed after the client
+		// received lastID, so resume from the start of the settle window
+		window.floor = lastID
+		recentID, err := h.uc.GetFirstRecentEventID(c.Request.Context(), streamSettleWindow)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if recentID > 0 && recentID <= lastID {
+			window.floor = recentID - 1
+		}
+	} else {
+		window.floor, err = h.uc.GetLatestEventID(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	wake, unsubscribe := h.broadcaster.Subscribe()
+	defer unsubscribe()
+
+	// Keep reverse proxies such as nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	write := func(event sse.Event) bool {
+		return event.Render(c.Writer) == nil
+	}
+	heartbeatEvent := func() sse.Event {
+		return sse.Event{Event: "heartbeat", Data: gin.H{"time": time.Now().UTC()}}
+	}
+
+	first := heartbeatEvent()
+	first.Retry = streamRetryMs
+	if !write(first) {
+		return
+	}
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+
+	// sendNew writes the events that became visible since the last call. It
+	// returns false when the stream should end; the client then resumes from
+	// the last sent ID.
+	sendNew := func() bool {
+		window.settle(time.Now())
+		for {
+			events, err := h.uc.GetEventsAfterID(ctx, filter, window.floor, window.exclude(), streamBatchSize)
+			if err == nil {
+				err = h.convertEventsTimezone(events)
+			}
+			if err != nil {
+				write(sse.Event{Event: "error", Data: gin.H{"error": err.Error()}})
+				c.Writer.Flush()
+				return false
+			}
+			for _, e := range events {
+				if !write(sse.Event{Id: strconv.FormatInt(e.ID, 10), Data: e}) {
+					return false
+				}
+				window.add(e.ID, time.Now())
+			}
+			c.Writer.Flush()
+			if len(events) < streamBatchSize {
+				return true
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+
+	// Catch up on events missed while reconnecting
+	if !sendNew() {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.broadcaster.Done():
+			return
+		case <-wake:
+			if !sendNew() {
+				return
+			}
+		case <-poll.C:
+			if !sendNew() {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(heartbeatEvent()) {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamTokenTTL is how long a stream token can be used to open a stream. An
+// open stream is not cut off when its token expires.
+const streamTokenTTL = time.Minute
+
+// StreamTokenHandler issues the short-lived tokens browsers pass as
+// access_token when opening an event stream with EventSource
+type StreamTokenHandler struct {
+	secret []byte
+}
+
+func NewStreamTokenHandler(secret []byte) *StreamTokenHandler {
+	return &StreamTokenHandler{secret: secret}
+}
+
+// CreateStreamToken godoc
+// @Summary Create an event stream token
+// @Description Short-lived token for /api/events/stream?access_token=..., for clients such as EventSource that cannot send an Authorization header. It only opens event streams and expires after a minute; request a new one before reconnecting.
+// @Tags monitoring
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/events/stream/token [post]
+func (h *StreamTokenHandler) CreateStreamToken(c *gin.Context) {
+	claims, _ := c.Value("claims").(jwt.MapClaims)
+	expiresAt := time.Now().Add(streamTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":  claims["user_id"],
+		"username": claims["username"],
+		"role":     claims["role"],
+		"scope":    streamTokenScope,
+		"exp":      expiresAt.Unix(),
+	})
+	tokenString, err := token.SignedString(h.secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": tokenString, "expires_at": expiresAt.UTC()})
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamWindow(t *testing.T) {
+	start := time.Unix(1772366400, 0)
+	w := streamWindow{floor: 10}
+
+	// Event 12 becomes visible before event 11, whose insert commits later
+	w.add(12, start)
+	w.settle(start.Add(time.Second))
+	if w.floor != 10 || !reflect.DeepEqual(w.exclude(), []int64{12}) {
+		t.Fatalf("after 12: floor %d, exclude %v; want the read to start at 10 without 12", w.floor, w.exclude())
+	}
+
+	w.add(11, start.Add(2*time.Second))
+	w.add(13, start.Add(2*time.Second))
+	w.settle(start.Add(streamSettleWindow))
+	if w.floor != 12 || !reflect.DeepEqual(w.exclude(), []int64{13}) {
+		t.Errorf("after the window of 12: floor %d, exclude %v; want floor 12 excluding 13", w.floor, w.exclude())
+	}
+
+	w.settle(start.Add(2*time.Second + streamSettleWindow))
+	if w.floor != 13 || len(w.exclude()) != 0 {
+		t.Errorf("after every window: floor %d, exclude %v; want floor 13", w.floor, w.exclude())
+	}
+}
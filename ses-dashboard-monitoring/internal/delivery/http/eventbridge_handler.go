@@ -0,0 +1,144 @@
+package http
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultEventBridgeAPIKeyHeader = "X-Api-Key"
+
+// eventBridgeEvent is the EventBridge envelope around an SES event. detail
+// holds the same JSON that SES publishes to SNS.
+type eventBridgeEvent struct {
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+type EventBridgeHandler struct {
+	ingester     *eventIngester
+	apiKey       string
+	apiKeyHeader string
+	logBody      bool
+}
+
+// NewEventBridgeHandler creates the handler for SES events forwarded by an
+// EventBridge API destination. A nil pipeline stores events synchronously and
+// a nil archiver skips the raw event archive.
+func NewEventBridgeHandler(
+	uc *usecase.SESUsecase,
+	pipeline *services.IngestPipeline,
+	deadLetters *usecase.DeadLetterUsecase,
+	archiver *services.EventArchiver,
+	cfg *config.Config,
+) *EventBridgeHandler {
+	header := cfg.AWS.EventBridgeAPIKeyHeader
+	if header == "" {
+		header = defaultEventBridgeAPIKeyHeader
+	}
+	return &EventBridgeHandler{
+		ingester: &eventIngester{
+			uc:          uc,
+			pipeline:    pipeline,
+			deadLetters: deadLetters,
+			archiver:    archiver,
+			source:      "eventbridge",
+			parse:       usecase.ParseSESMessage,
+		},
+		apiKey:       cfg.AWS.EventBridgeAPIKey,
+		apiKeyHeader: header,
+		logBody:      cfg.App.LogBody,
+	}
+}
+
+// Handle godoc
+// @Summary Receive SES events from EventBridge
+// @Description Target for an EventBridge API destination. Accepts one event or an array of events whose detail is an SES event. Authenticated with the configured API key header (X-Api-Key by default).
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /eventbridge/ses [post]
+func (h *EventBridgeHandler) Handle(c *gin.Context) {
+	key := c.GetHeader(h.apiKeyHeader)
+	if h.apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.apiKey)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	if h.logBody {
+		log.Printf("Received EventBridge payload: %s", string(body))
+	}
+
+	var raws []json.RawMessage
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &raws)
+	} else {
+		var raw json.RawMessage
+		err = json.Unmarshal(body, &raw)
+		raws = append(raws, raw)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+
+	messages := make([]ingestMessage, 0, len(raws))
+	for _, raw := range raws {
+		messages = append(messages, unwrapEventBridge(raw))
+	}
+
+	result, err := h.ingester.ingest(c.Request.Context(), messages)
+	if errors.Is(err, services.ErrQueueFull) || errors.Is(err, services.ErrPipelineClosed) {
+		// API destinations retry 429 and 5xx responses
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := "ok"
+	if h.ingester.pipeline != nil {
+		status = "queued"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":        status,
+		"messages":      result.Messages,
+		"events":        result.Events,
+		"dead_lettered": result.DeadLettered,
+	})
+}
+
+// unwrapEventBridge returns the SES event inside an EventBridge envelope, or
+// raw unchanged when it is not one
+func unwrapEventBridge(raw json.RawMessage) ingestMessage {
+	var event eventBridgeEvent
+	if err := json.Unmarshal(raw, &event); err != nil || event.DetailType == "" || len(event.Detail) == 0 {
+		return ingestMessage{Message: string(raw)}
+	}
+	return ingestMessage{ID: event.ID, Message: string(event.Detail)}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/exportjob"
+	"ses-monitoring/internal/infrastructure/export"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ExportJobHandler struct {
+	uc *usecase.ExportUsecase
+	// monitoring provides the configured timezone the files are written in
+	monitoring *MonitoringHandler
+}
+
+func NewExportJobHandler(uc *usecase.ExportUsecase, monitoring *MonitoringHandler) *ExportJobHandler {
+	return &ExportJobHandler{uc: uc, monitoring: monitoring}
+}
+
+// ExportJobResponse is an export job with, once it succeeded, a signed download link
+type ExportJobResponse struct {
+	*exportjob.Job
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+// CreateExportJob godoc
+// @Summary Start an export job
+// @Description Queue a background export of every event matching the filters. It takes the same query parameters as /api/events/export. A worker writes the file (gzip compressed CSV or NDJSON, or XLSX) to the configured object store; poll the job until it succeeded and download it from download_url.
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format (default: csv)" Enums(csv, ndjson, xlsx)
+// @Param columns query []string false "Columns in order, repeatable or comma separated" collectionFormat(multi)
+// @Param q query string false "Search query (see README)"
+// @Param search query string false "Search email, subject or source"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param event_type query []string false "Event types, repeatable or comma separated (e.g. Bounce,Complaint)" collectionFormat(multi)
+// @Param bounce_type query string false "Bounce type (Permanent, Transient, Undetermined)"
+// @Param sender query string false "Sender address, local@ for a local part at any domain or @domain for any address at a domain"
+// @Param recipient_domain query string false "Recipient domain (e.g. gmail.com)"
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
+// @Param tag query []string false "Tag filter as key:value, repeatable; a repeated key matches any of its values" collectionFormat(multi)
+// @Success 202 {object} ExportJobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/exports [post]
+func (h *ExportJobHandler) CreateExportJob(c *gin.Context) {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		filterError(c, err)
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatCSV))
+	if !export.IsValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected csv, ndjson or xlsx"})
+		return
+	}
+
+	columns, err := export.ParseColumns(parseListQuery(c, "columns"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+
+	job := &exportjob.Job{
+		UserID:   currentUserID(c),
+		Format:   format,
+		Columns:  names,
+		Params:   c.Request.URL.RawQuery,
+		Filter:   filter,
+		Timezone: h.monitoring.getTimezoneFromCache(),
+	}
+	if err := h.uc.Submit(c.Request.Context(), job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, h.response(job))
+}
+
+// GetExportJobs godoc
+// @Summary List export jobs
+// @Description The 100 newest export jobs of the current user; admins see the jobs of every user
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/exports [get]
+func (h *ExportJobHandler) GetExportJobs(c *gin.Context) {
+	userID := currentUserID(c)
+	if isAdmin(c) {
+		userID = 0
+	}
+
+	jobs, err := h.uc.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]ExportJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		responses = append(responses, h.response(job))
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": responses})
+}
+
+// GetExportJob godoc
+// @Summary Get an export job
+// @Description Status and progress of an export job. Once it succeeded the response carries a signed download_url that works without a bearer token until download_expires_at; fetch the job again for a fresh link.
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} ExportJobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/exports/{id} [get]
+func (h *ExportJobHandler) GetExportJob(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.response(job))
+}
+
+// DeleteExportJob godoc
+// @Summary Cancel or delete an export job
+// @Description Cancels a queued or running job, or deletes a finished job together with its file
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/exports/{id} [delete]
+func (h *ExportJobHandler) DeleteExportJob(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	message := "Export deleted"
+	if !job.IsFinished() {
+		message = "Export canceled"
+	}
+	if err := h.uc.Remove(c.Request.Context(), job); err != nil {
+		if errors.Is(err, exportjob.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// DownloadExport godoc
+// @Summary Download an export
+// @Description Download the file of a succeeded export job through the signed link returned as download_url. No bearer token is needed.
+// @Tags exports
+// @Produce application/octet-stream
+// @Param id path int true "Job ID"
+// @Param expires query int true "Link expiry (unix time)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /api/exports/{id}/download [get]
+func (h *ExportJobHandler) DownloadExport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err := h.uc.VerifyDownload(id, expires, c.Query("signature"), time.Now()); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired download link"})
+		return
+	}
+
+	job, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, exportjob.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.uc.Open(c.Request.Context(), job)
+	if err != nil {
+		if errors.Is(err, usecase.ErrExportNotReady) || errors.Is(err, fs.ErrNotExist) {
+			c.JSON(http.StatusGone, gin.H{"error": "Export file is no longer available"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	contentType := "application/gzip"
+	if job.Format == export.FormatXLSX {
+		contentType = export.ContentType(export.FormatXLSX)
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+job.FileName+`"`)
+	if job.ArtifactSize > 0 {
+		c.Header("Content-Length", strconv.FormatInt(job.ArtifactSize, 10))
+	}
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil && c.Request.Context().Err() == nil {
+		abortResponse(c)
+	}
+}
+
+// loadJob reads the job of the id parameter, which only its owner and admins may see
+func (h *ExportJobHandler) loadJob(c *gin.Context) (*exportjob.Job, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return nil, false
+	}
+
+	job, err := h.uc.Get(c.Request.Context(), id)
+	if err == nil && job.UserID != currentUserID(c) && !isAdmin(c) {
+		err = exportjob.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, exportjob.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return job, true
+}
+
+func (h *ExportJobHandler) response(job *exportjob.Job) ExportJobResponse {
+	response := ExportJobResponse{Job: job}
+	if job.Status == exportjob.StatusSucceeded {
+		expires, signature := h.uc.SignDownload(job.ID, time.Now())
+		response.DownloadURL = fmt.Sprintf("/api/exports/%d/download?expires=%d&signature=%s", job.ID, expires.Unix(), signature)
+		response.DownloadExpiresAt = &expires
+	}
+	return response
+}
+
+// currentUserID returns the user ID of the JWT claims, or 0
+func currentUserID(c *gin.Context) int64 {
+	switch v := c.Value("user_id").(type) {
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	claims, ok := c.Value("claims").(jwt.MapClaims)
+	return ok && claims["role"] == "admin"
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	firehoseAccessKeyHeader = "X-Amz-Firehose-Access-Key"
+	firehoseRequestIDHeader = "X-Amz-Firehose-Request-Id"
+)
+
+// firehoseRequest is the body of a Kinesis Data Firehose HTTP endpoint delivery
+type firehoseRequest struct {
+	RequestID string `json:"requestId"`
+	Timestamp int64  `json:"timestamp"`
+	Records   []struct {
+		Data string `json:"data"`
+	} `json:"records"`
+}
+
+// firehoseResponse is the acknowledgement Firehose expects. Any status other
+// than 200 makes Firehose retry the whole request.
+type firehoseResponse struct {
+	RequestID    string `json:"requestId"`
+	Timestamp    int64  `json:"timestamp"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+type FirehoseHandler struct {
+	ingester  *eventIngester
+	accessKey string
+	logBody   bool
+}
+
+// NewFirehoseHandler creates the Firehose HTTP endpoint handler. A nil
+// pipeline stores events synchronously and a nil archiver skips the raw
+// event archive.
+func NewFirehoseHandler(
+	uc *usecase.SESUsecase,
+	pipeline *services.IngestPipeline,
+	deadLetters *usecase.DeadLetterUsecase,
+	archiver *services.EventArchiver,
+	cfg *config.Config,
+) *FirehoseHandler {
+	return &FirehoseHandler{
+		ingester: &eventIngester{
+			uc:          uc,
+			pipeline:    pipeline,
+			deadLetters: deadLetters,
+			archiver:    archiver,
+			source:      "firehose",
+			parse:       usecase.ParseSESMessage,
+		},
+		accessKey: cfg.AWS.FirehoseAccessKey,
+		logBody:   cfg.App.LogBody,
+	}
+}
+
+// Handle godoc
+// @Summary Receive SES events from Kinesis Data Firehose
+// @Description HTTP endpoint destination for a Firehose stream carrying SES event publishing records. Authenticated with the X-Amz-Firehose-Access-Key header.
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Param X-Amz-Firehose-Access-Key header string true "Configured Firehose access key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /firehose/ses [post]
+func (h *FirehoseHandler) Handle(c *gin.Context) {
+	requestID := c.GetHeader(firehoseRequestIDHeader)
+
+	key := c.GetHeader(firehoseAccessKeyHeader)
+	if h.accessKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.accessKey)) != 1 {
+		h.respond(c, http.StatusUnauthorized, requestID, "Invalid access key")
+		return
+	}
+
+	body, err := readFirehoseBody(c)
+	if err != nil {
+		h.respond(c, http.StatusBadRequest, requestID, "Failed to read request body")
+		return
+	}
+
+	var req firehoseRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.respond(c, http.StatusBadRequest, requestID, "Invalid JSON")
+		return
+	}
+	if req.RequestID != "" {
+		requestID = req.RequestID
+	}
+
+	if h.logBody {
+		log.Printf("Received Firehose request %s with %d records", requestID, len(req.Records))
+	}
+
+	var messages []ingestMessage
+	for _, record := range req.Records {
+		data, err := base64.StdEncoding.DecodeString(record.Data)
+		if err != nil {
+			// Keep the record as received so it shows up in the dead letters
+			messages = append(messages, ingestMessage{Message: record.Data})
+			continue
+		}
+		messages = append(messages, splitFirehoseRecord(data)...)
+	}
+
+	result, err := h.ingester.ingest(c.Request.Context(), messages)
+	if errors.Is(err, services.ErrQueueFull) || errors.Is(err, services.ErrPipelineClosed) {
+		h.respond(c, http.StatusServiceUnavailable, requestID, err.Error())
+		return
+	}
+	if err != nil {
+		h.respond(c, http.StatusInternalServerError, requestID, err.Error())
+		return
+	}
+
+	if result.DeadLettered > 0 {
+		log.Printf("Firehose request %s: %d events, %d records dead-lettered", requestID, result.Events, result.DeadLettered)
+	}
+	h.respond(c, http.StatusOK, requestID, "")
+}
+
+func (h *FirehoseHandler) respond(c *gin.Context, status int, requestID, errorMessage string) {
+	c.JSON(status, firehoseResponse{
+		RequestID:    requestID,
+		Timestamp:    time.Now().UnixMilli(),
+		ErrorMessage: errorMessage,
+	})
+}
+
+// readFirehoseBody returns the request body, decompressed when the stream is
+// configured with GZIP content encoding
+func readFirehoseBody(c *gin.Context) ([]byte, error) {
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(c.GetHeader("Content-Encoding"), "gzip") {
+		return body, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+// splitFirehoseRecord returns the SES messages in a record. SES writes one
+// JSON event per record, but records may also hold several concatenated or
+// newline-delimited events, or EventBridge events routed through Firehose.
+func splitFirehoseRecord(data []byte) []ingestMessage {
+	var messages []ingestMessage
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		start := decoder.InputOffset()
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Keep the remainder as one message so it is dead-lettered intact
+			if rest := bytes.TrimSpace(data[start:]); len(rest) > 0 {
+				messages = append(messages, ingestMessage{Message: string(rest)})
+			}
+			break
+		}
+		messages = append(messages, unwrapEventBridge(raw))
+	}
+	return messages
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"log"
+
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+)
+
+// ingestMessage is one event notification unwrapped from a delivery envelope.
+// ID is empty when the envelope carries none.
+type ingestMessage struct {
+	ID       string
+	TopicArn string
+	Message  string
+}
+
+// ingestResult summarizes a batch of messages
+type ingestResult struct {
+	Messages     int
+	Events       int
+	DeadLettered int
+}
+
+// eventIngester runs unwrapped messages through the same archive, parse and
+// store steps as the SNS webhook, for endpoints that deliver them in batches.
+// parse is the parser of the provider sending them.
+type eventIngester struct {
+	uc          *usecase.SESUsecase
+	pipeline    *services.IngestPipeline
+	deadLetters *usecase.DeadLetterUsecase
+	archiver    *services.EventArchiver
+	source      string
+	parse       func(data []byte) ([]*sesevent.Event, error)
+}
+
+// ingest stores the events of all messages, or none of them when an error is
+// returned so the sender can retry the whole batch. Messages that cannot be
+// parsed are dead-lettered once the rest of the batch is accepted.
+func (i *eventIngester) ingest(ctx context.Context, messages []ingestMessage) (*ingestResult, error) {
+	result := &ingestResult{Messages: len(messages)}
+
+	var (
+		events      []*sesevent.Event
+		unparseable []*deadletter.Entry
+	)
+	for _, msg := range messages {
+		if i.archiver != nil {
+			i.archiver.Archive(services.ArchiveRecord{
+				Source:       i.source,
+				SNSMessageID: msg.ID,
+				TopicArn:     msg.TopicArn,
+				Message:      msg.Message,
+			})
+		}
+
+		parsed, err := i.parse([]byte(msg.Message))
+		if err != nil {
+			unparseable = append(unparseable, &deadletter.Entry{
+				Source:    i.source,
+				MessageID: msg.ID,
+				TopicArn:  msg.TopicArn,
+				Payload:   msg.Message,
+				Stage:     deadletter.StageParse,
+				Error:     err.Error(),
+			})
+			continue
+		}
+		if msg.ID != "" {
+			for _, event := range parsed {
+				event.SNSMessageID = msg.ID
+			}
+		}
+		events = append(events, parsed...)
+	}
+
+	if len(events) > 0 {
+		if i.pipeline != nil {
+			if err := i.pipeline.Enqueue(events); err != nil {
+				return nil, err
+			}
+		} else if _, err := i.uc.HandleEvents(ctx, events); err != nil {
+			return nil, err
+		}
+	}
+	result.Events = len(events)
+
+	// A retry after a failure here only duplicates events, which are deduplicated
+	for _, entry := range unparseable {
+		if err := i.deadLetters.Record(ctx, entry); err != nil {
+			return nil, err
+		}
+		log.Printf("Dead-lettered %s message %s: %s", i.source, entry.MessageID, entry.Error)
+		result.DeadLettered++
+	}
+	return result, nil
+}
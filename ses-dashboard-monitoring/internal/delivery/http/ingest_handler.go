@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+
+	"ses-monitoring/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IngestHandler struct {
+	pipeline *services.IngestPipeline
+}
+
+// NewIngestHandler creates the ingestion status handler. pipeline is nil when
+// events are stored synchronously.
+func NewIngestHandler(pipeline *services.IngestPipeline) *IngestHandler {
+	return &IngestHandler{pipeline: pipeline}
+}
+
+// GetStats godoc
+// @Summary Get ingestion pipeline stats
+// @Description Queue depth, throughput and batch latency of the buffered ingestion pipeline
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/settings/ingest/stats [get]
+func (h *IngestHandler) GetStats(c *gin.Context) {
+	if h.pipeline == nil {
+		c.JSON(http.StatusOK, gin.H{"async": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"async": true,
+		"stats": h.pipeline.Stats(),
+	})
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,6 +9,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// streamTokenScope marks the short-lived tokens that may only open event
+// streams, see StreamTokenHandler
+const streamTokenScope = "events:stream"
+
 func JWTAuthMiddleware(secret []byte) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -24,29 +29,68 @@ func JWTAuthMiddleware(secret []byte) gin.HandlerFunc {
 			return
 		}
 
-		claims := jwt.MapClaims{}
-		parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
-		token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return secret, nil
-		})
-		if err != nil || !token.Valid {
+		claims, err := parseToken(secret, tokenString)
+		if err != nil || claims["scope"] != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		c.Set("claims", claims)
-		if userID, ok := claims["user_id"]; ok {
-			c.Set("user_id", userID)
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// StreamAuthMiddleware authenticates event streams. Browsers cannot set
+// headers on an EventSource, so besides the Authorization header it accepts
+// a stream token in the access_token query parameter. Regular tokens are not
+// accepted there, as URLs end up in logs.
+func StreamAuthMiddleware(secret []byte) gin.HandlerFunc {
+	header := JWTAuthMiddleware(secret)
+	return func(c *gin.Context) {
+		tokenString := c.Query("access_token")
+		if tokenString == "" {
+			header(c)
+			return
 		}
-		if username, ok := claims["username"]; ok {
-			c.Set("username", username)
+
+		claims, err := parseToken(secret, tokenString)
+		if err != nil || claims["scope"] != streamTokenScope {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid stream token"})
+			c.Abort()
+			return
 		}
 
+		setClaims(c, claims)
 		c.Next()
 	}
 }
 
+func parseToken(secret []byte, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func setClaims(c *gin.Context, claims jwt.MapClaims) {
+	c.Set("claims", claims)
+	if userID, ok := claims["user_id"]; ok {
+		c.Set("user_id", userID)
+	}
+	if username, ok := claims["username"]; ok {
+		c.Set("username", username)
+	}
+}
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if claims, exists := c.Get("claims"); exists {
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testJWTSecret = []byte("test-secret")
+
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newStreamAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/events/stream", StreamAuthMiddleware(testJWTSecret), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.Value("user_id")})
+	})
+	r.GET("/api/events", JWTAuthMiddleware(testJWTSecret), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	api := r.Group("/api", JWTAuthMiddleware(testJWTSecret))
+	api.POST("/events/stream/token", NewStreamTokenHandler(testJWTSecret).CreateStreamToken)
+	return r
+}
+
+func TestStreamAuthMiddleware(t *testing.T) {
+	r := newStreamAuthRouter()
+	session := signTestToken(t, jwt.MapClaims{"user_id": 7, "role": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+
+	// A browser first asks for a stream token with its session token
+	req := httptest.NewRequest(http.MethodPost, "/api/events/stream/token", nil)
+	req.Header.Set("Authorization", "Bearer "+session)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /api/events/stream/token = %d: %s", w.Code, w.Body)
+	}
+	var issued struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &issued); err != nil || issued.Token == "" {
+		t.Fatalf("token response %s: %v", w.Body, err)
+	}
+
+	tests := []struct {
+		name   string
+		url    string
+		header string
+		want   int
+	}{
+		{"stream token in the query", "/api/events/stream?access_token=" + issued.Token, "", http.StatusOK},
+		{"session token in the header", "/api/events/stream", "Bearer " + session, http.StatusOK},
+		{"session token in the query", "/api/events/stream?access_token=" + session, "", http.StatusUnauthorized},
+		{"expired stream token", "/api/events/stream?access_token=" + signTestToken(t, jwt.MapClaims{
+			"user_id": 7, "scope": streamTokenScope, "exp": time.Now().Add(-time.Second).Unix(),
+		}), "", http.StatusUnauthorized},
+		{"stream token signed with another key", "/api/events/stream?access_token=" + func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"scope": streamTokenScope, "exp": time.Now().Add(time.Minute).Unix(),
+			}).SignedString([]byte("other-secret"))
+			return token
+		}(), "", http.StatusUnauthorized},
+		{"no token", "/api/events/stream", "", http.StatusUnauthorized},
+		{"stream token on another route", "/api/events", "Bearer " + issued.Token, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d: %s", tt.url, w.Code, tt.want, w.Body)
+			}
+			if w.Code == http.StatusOK && w.Body.String() != `{"user_id":7}` {
+				t.Errorf("claims = %s, want the user of the session", w.Body)
+			}
+		})
+	}
+}
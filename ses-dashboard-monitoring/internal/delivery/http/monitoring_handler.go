@@ -3,13 +3,17 @@ package http
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"ses-monitoring/internal/domain/sesevent"
 	"ses-monitoring/internal/domain/settings"
+	"ses-monitoring/internal/services"
 	"ses-monitoring/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +22,7 @@ import (
 type MonitoringHandler struct {
 	uc           *usecase.SESUsecase
 	settingsRepo settings.Repository
+	broadcaster  *services.EventBroadcaster
 
 	// Timezone cache
 	timezoneMu    sync.RWMutex
@@ -34,10 +39,11 @@ type metricsCacheItem struct {
 
 const metricsCacheTTL = 30 * time.Second
 
-func NewMonitoringHandler(uc *usecase.SESUsecase, settingsRepo settings.Repository) *MonitoringHandler {
+func NewMonitoringHandler(uc *usecase.SESUsecase, settingsRepo settings.Repository, broadcaster *services.EventBroadcaster) *MonitoringHandler {
 	h := &MonitoringHandler{
 		uc:            uc,
 		settingsRepo:  settingsRepo,
+		broadcaster:   broadcaster,
 		timezoneCache: "Asia/Jakarta", // default
 		metricsCache:  make(map[string]metricsCacheItem),
 	}
@@ -47,12 +53,28 @@ func NewMonitoringHandler(uc *usecase.SESUsecase, settingsRepo settings.Reposito
 
 // GetEvents godoc
 // @Summary Get SES events with pagination
-// @Description Retrieve list of SES events with pagination support
+// @Description Retrieve list of SES events, newest first. Sending cursor (empty for the first page) switches from page numbers to cursor pagination, which stays fast on deep pages: follow pagination.nextCursor and pagination.prevCursor to move through the list.
 // @Tags monitoring
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param cursor query string false "Cursor from pagination.nextCursor or pagination.prevCursor; empty for the first page"
 // @Param limit query int false "Number of events per page (default: 50, max: 1000)" minimum(1) maximum(1000)
+// @Param count query string false "Total to return: exact, approximate (planner estimate) or none (default with page: exact for filters matching up to about 100000 events, otherwise approximate; approximate with cursor)" Enums(exact, approximate, none)
+// @Param q query string false "Search query, e.g. type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01 (see README)"
+// @Param search query string false "Search email, subject or source"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param event_type query []string false "Event types, repeatable or comma separated (e.g. Bounce,Complaint)" collectionFormat(multi)
+// @Param bounce_type query string false "Bounce type (Permanent, Transient, Undetermined)"
+// @Param bounce_sub_type query string false "Bounce subtype (e.g. General, NoEmail, MailboxFull)"
+// @Param status query string false "Recipient status (e.g. SUCCESS, COMPLAINED, DELAYED)"
+// @Param sender query string false "Sender address, local@ for a local part at any domain or @domain for any address at a domain"
+// @Param recipient_domain query string false "Recipient domain (e.g. gmail.com)"
+// @Param reporting_mta query string false "Part of the reporting MTA"
+// @Param feedback_type query string false "Complaint feedback type (e.g. abuse, not-spam)"
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
+// @Param tag query []string false "Tag filter as key:value, repeatable; a repeated key matches any of its values (e.g. campaign:spring, campaign:summer)" collectionFormat(multi)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -61,9 +83,12 @@ func (h *MonitoringHandler) GetEvents(c *gin.Context) {
 	// Parse query parameters
 	page := 1
 	limit := 50
-	search := c.Query("search")
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
+
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		filterError(c, err)
+		return
+	}
 
 	if p := c.Query("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
@@ -77,29 +102,53 @@ func (h *MonitoringHandler) GetEvents(c *gin.Context) {
 		}
 	}
 
+	countMode := c.Query("count")
+	switch countMode {
+	case "", countExact, countApproximate, countNone:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid count, expected exact, approximate or none"})
+		return
+	}
+
+	if encoded, ok := c.GetQuery("cursor"); ok {
+		h.getEventsByCursor(c, filter, encoded, limit, countMode)
+		return
+	}
 	offset := (page - 1) * limit
 
 	var events []*sesevent.Event
 	var total int
-	var err error
 
 	// Use optimized queries based on filter presence
-	if search != "" || startDate != "" || endDate != "" {
-		events, err = h.uc.GetEventsWithFilter(c.Request.Context(), limit, offset, search, startDate, endDate)
+	if !filter.IsEmpty() {
+		events, err = h.uc.GetEventsWithFilter(c.Request.Context(), filter, limit, offset)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		// Get filtered count
-		total, err = h.uc.GetFilteredEventCount(c.Request.Context(), search, startDate, endDate)
 	} else {
 		events, err = h.uc.GetEventsPaginated(c.Request.Context(), limit, offset)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		// Get total count
-		total, err = h.uc.GetEventCount(c.Request.Context())
+	}
+
+	switch countMode {
+	case "":
+		// Counting every row is only affordable for narrow filters, so the
+		// default is exact below exactCountLimit estimated rows
+		total, err = h.uc.EstimateEventCount(c.Request.Context(), filter)
+		countMode = countApproximate
+		if err == nil && !filter.IsEmpty() && total <= exactCountLimit {
+			total, err = h.uc.GetFilteredEventCount(c.Request.Context(), filter)
+			countMode = countExact
+		}
+	case countExact:
+		// Count the rows the list pages through; GetEventCount counts messages
+		total, err = h.uc.GetFilteredEventCount(c.Request.Context(), filter)
+	case countApproximate:
+		total, err = h.uc.EstimateEventCount(c.Request.Context(), filter)
 	}
 
 	if err != nil {
@@ -107,37 +156,169 @@ func (h *MonitoringHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
+	// Cursor of the following page, for clients moving over to cursors
+	nextCursor := ""
+	if len(events) == limit {
+		nextCursor = sesevent.NewCursor(events[len(events)-1], false).Encode()
+	}
+
 	// Convert timestamps to configured timezone
 	if err := h.convertEventsTimezone(events); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	totalPages := (total + limit - 1) / limit // Ceiling division
+	pagination := gin.H{
+		"page":       page,
+		"limit":      limit,
+		"hasPrev":    page > 1,
+		"nextCursor": nextCursor,
+	}
+	if countMode == countNone {
+		pagination["hasNext"] = len(events) == limit
+	} else {
+		totalPages := (total + limit - 1) / limit // Ceiling division
+		pagination["total"] = total
+		pagination["totalApproximate"] = countMode == countApproximate
+		pagination["totalPages"] = totalPages
+		pagination["hasNext"] = page < totalPages
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-		"pagination": gin.H{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
-			"totalPages": totalPages,
-			"hasNext":    page < totalPages,
-			"hasPrev":    page > 1,
-		},
+		"events":     events,
+		"pagination": pagination,
 	})
 }
 
+// Values of the count parameter of GetEvents
+const (
+	countExact       = "exact"
+	countApproximate = "approximate"
+	countNone        = "none"
+
+	// exactCountLimit is the largest estimated total that page mode counts
+	// exactly when the count parameter is not set
+	exactCountLimit = 100000
+)
+
+// getEventsByCursor answers GetEvents for cursor pagination, which seeks on
+// (event_timestamp, id) instead of skipping rows with OFFSET
+func (h *MonitoringHandler) getEventsByCursor(c *gin.Context, filter sesevent.Filter, encoded string, limit int, countMode string) {
+	var cursor *sesevent.Cursor
+	if encoded != "" {
+		decoded, err := sesevent.DecodeCursor(encoded)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		cursor = decoded
+	}
+	if countMode == "" {
+		countMode = countApproximate
+	}
+
+	page, err := h.uc.GetEventPage(c.Request.Context(), filter, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	pagination := gin.H{
+		"limit":      limit,
+		"nextCursor": page.NextCursor,
+		"prevCursor": page.PrevCursor,
+		"hasNext":    page.NextCursor != "",
+		"hasPrev":    page.PrevCursor != "",
+	}
+
+	var total int
+	switch countMode {
+	case countExact:
+		total, err = h.uc.GetFilteredEventCount(c.Request.Context(), filter)
+	case countApproximate:
+		total, err = h.uc.EstimateEventCount(c.Request.Context(), filter)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if countMode != countNone {
+		pagination["total"] = total
+		pagination["totalApproximate"] = countMode == countApproximate
+	}
+
+	if err := h.convertEventsTimezone(page.Events); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if page.Events == nil {
+		page.Events = []*sesevent.Event{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":     page.Events,
+		"pagination": pagination,
+	})
+}
+
+// GetMessageTimeline godoc
+// @Summary Get the lifecycle of a message
+// @Description Every event of one sent message grouped by recipient in the order they happened, with the time since the previous event and since the send, milestone latencies (send to delivery, delivery to first open, ...) and the final state of each recipient
+// @Tags monitoring
+// @Produce json
+// @Security BearerAuth
+// @Param message_id path string true "Message ID"
+// @Param email query string false "Only this recipient"
+// @Success 200 {object} sesevent.MessageTimeline
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/messages/{message_id} [get]
+func (h *MonitoringHandler) GetMessageTimeline(c *gin.Context) {
+	messageID := c.Param("message_id")
+
+	events, err := h.uc.GetEventsByMessageID(c.Request.Context(), messageID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if email := c.Query("email"); email != "" {
+		recipientEvents := events[:0]
+		for _, e := range events {
+			if strings.EqualFold(e.Email, email) {
+				recipientEvents = append(recipientEvents, e)
+			}
+		}
+		events = recipientEvents
+	}
+
+	if len(events) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return
+	}
+
+	if err := h.convertEventsTimezone(events); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sesevent.NewMessageTimeline(messageID, events))
+}
+
 type MetricsResponse struct {
-	TotalEvents    int     `json:"total_events"`
-	SendCount      int     `json:"send_count"`
-	DeliveryCount  int     `json:"delivery_count"`
-	BounceCount    int     `json:"bounce_count"`
-	ComplaintCount int     `json:"complaint_count"`
-	OpenCount      int     `json:"open_count"`
-	ClickCount     int     `json:"click_count"`
-	BounceRate     float64 `json:"bounce_rate"`
-	DeliveryRate   float64 `json:"delivery_rate"`
+	TotalEvents           int     `json:"total_events"`
+	SendCount             int     `json:"send_count"`
+	DeliveryCount         int     `json:"delivery_count"`
+	BounceCount           int     `json:"bounce_count"`
+	ComplaintCount        int     `json:"complaint_count"`
+	OpenCount             int     `json:"open_count"`
+	ClickCount            int     `json:"click_count"`
+	DeliveryDelayCount    int     `json:"delivery_delay_count"`
+	RejectCount           int     `json:"reject_count"`
+	RenderingFailureCount int     `json:"rendering_failure_count"`
+	SubscriptionCount     int     `json:"subscription_count"`
+	BounceRate            float64 `json:"bounce_rate"`
+	DeliveryRate          float64 `json:"delivery_rate"`
 }
 
 // GetMetrics godoc
@@ -146,11 +327,19 @@ type MetricsResponse struct {
 // @Tags monitoring
 // @Produce json
 // @Security BearerAuth
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
 // @Success 200 {object} MetricsResponse
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/metrics [get]
 func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
-	cacheKey := "summary:" + h.getTimezoneFromCache()
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := "summary:" + provider + ":" + h.getTimezoneFromCache()
 	if cached, ok := h.getMetricsCache(cacheKey); ok {
 		if metrics, ok := cached.(MetricsResponse); ok {
 			c.JSON(http.StatusOK, metrics)
@@ -158,40 +347,44 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 		}
 	}
 
-	total, err := h.uc.GetEventCount(c.Request.Context())
+	total, err := h.uc.GetEventCount(c.Request.Context(), provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	bounceRate, err := h.uc.GetBounceRate(c.Request.Context())
+	bounceRate, err := h.uc.GetBounceRate(c.Request.Context(), provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	deliveryRate, err := h.uc.GetDeliveryRate(c.Request.Context())
+	deliveryRate, err := h.uc.GetDeliveryRate(c.Request.Context(), provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	counts, err := h.uc.GetEventTypeCounts(c.Request.Context())
+	counts, err := h.uc.GetEventTypeCounts(c.Request.Context(), provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	metrics := MetricsResponse{
-		TotalEvents:    total,
-		SendCount:      counts["Send"],
-		DeliveryCount:  counts["Delivery"],
-		BounceCount:    counts["Bounce"],
-		ComplaintCount: counts["Complaint"],
-		OpenCount:      counts["Open"],
-		ClickCount:     counts["Click"],
-		BounceRate:     bounceRate,
-		DeliveryRate:   deliveryRate,
+		TotalEvents:           total,
+		SendCount:             counts["Send"],
+		DeliveryCount:         counts["Delivery"],
+		BounceCount:           counts["Bounce"],
+		ComplaintCount:        counts["Complaint"],
+		OpenCount:             counts["Open"],
+		ClickCount:            counts["Click"],
+		DeliveryDelayCount:    counts["DeliveryDelay"],
+		RejectCount:           counts["Reject"],
+		RenderingFailureCount: counts["RenderingFailure"],
+		SubscriptionCount:     counts["Subscription"],
+		BounceRate:            bounceRate,
+		DeliveryRate:          deliveryRate,
 	}
 
 	h.setMetricsCache(cacheKey, metrics)
@@ -204,7 +397,9 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 // @Tags monitoring
 // @Produce json
 // @Security BearerAuth
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
 // @Success 200 {object} map[string][]sesevent.DailyMetrics
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/metrics/daily [get]
 func (h *MonitoringHandler) GetDailyMetrics(c *gin.Context) {
@@ -219,7 +414,13 @@ func (h *MonitoringHandler) GetDailyMetrics(c *gin.Context) {
 		return
 	}
 
-	cacheKey := h.buildMetricsCacheKey("daily", start, end)
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.buildMetricsCacheKey("daily:"+provider, start, end)
 	if cached, ok := h.getMetricsCache(cacheKey); ok {
 		if metrics, ok := cached.([]*sesevent.DailyMetrics); ok {
 			c.JSON(http.StatusOK, gin.H{"daily_metrics": metrics})
@@ -227,7 +428,7 @@ func (h *MonitoringHandler) GetDailyMetrics(c *gin.Context) {
 		}
 	}
 
-	metrics, err := h.uc.GetDailyMetrics(c.Request.Context(), start, end)
+	metrics, err := h.uc.GetDailyMetrics(c.Request.Context(), start, end, provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -249,7 +450,9 @@ func (h *MonitoringHandler) GetDailyMetrics(c *gin.Context) {
 // @Tags monitoring
 // @Produce json
 // @Security BearerAuth
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
 // @Success 200 {object} map[string][]sesevent.MonthlyMetrics
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/metrics/monthly [get]
 func (h *MonitoringHandler) GetMonthlyMetrics(c *gin.Context) {
@@ -265,7 +468,13 @@ func (h *MonitoringHandler) GetMonthlyMetrics(c *gin.Context) {
 		return
 	}
 
-	cacheKey := h.buildMetricsCacheKey("monthly", start, end)
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.buildMetricsCacheKey("monthly:"+provider, start, end)
 	if cached, ok := h.getMetricsCache(cacheKey); ok {
 		if metrics, ok := cached.([]*sesevent.MonthlyMetrics); ok {
 			c.JSON(http.StatusOK, gin.H{"monthly_metrics": metrics})
@@ -273,7 +482,7 @@ func (h *MonitoringHandler) GetMonthlyMetrics(c *gin.Context) {
 		}
 	}
 
-	metrics, err := h.uc.GetMonthlyMetrics(c.Request.Context(), start, end)
+	metrics, err := h.uc.GetMonthlyMetrics(c.Request.Context(), start, end, provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -295,7 +504,9 @@ func (h *MonitoringHandler) GetMonthlyMetrics(c *gin.Context) {
 // @Tags monitoring
 // @Produce json
 // @Security BearerAuth
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
 // @Success 200 {object} map[string][]sesevent.HourlyMetrics
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/metrics/hourly [get]
 func (h *MonitoringHandler) GetHourlyMetrics(c *gin.Context) {
@@ -310,7 +521,13 @@ func (h *MonitoringHandler) GetHourlyMetrics(c *gin.Context) {
 		return
 	}
 
-	cacheKey := h.buildMetricsCacheKey("hourly", start, end)
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.buildMetricsCacheKey("hourly:"+provider, start, end)
 	if cached, ok := h.getMetricsCache(cacheKey); ok {
 		if metrics, ok := cached.([]*sesevent.HourlyMetrics); ok {
 			c.JSON(http.StatusOK, gin.H{"hourly_metrics": metrics})
@@ -318,7 +535,7 @@ func (h *MonitoringHandler) GetHourlyMetrics(c *gin.Context) {
 		}
 	}
 
-	metrics, err := h.uc.GetHourlyMetrics(c.Request.Context(), start, end)
+	metrics, err := h.uc.GetHourlyMetrics(c.Request.Context(), start, end, provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -334,6 +551,66 @@ func (h *MonitoringHandler) GetHourlyMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"hourly_metrics": metrics})
 }
 
+// GetTopLinks godoc
+// @Summary Get most clicked links
+// @Description Rank clicked links by unique clicks (one per message) over a date range
+// @Tags engagement
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD, default: 30 days ago)"
+// @Param end_date query string false "End date (YYYY-MM-DD, default: today)"
+// @Param provider query string false "Only events of this provider (ses, sendgrid, mailgun, postmark)"
+// @Param limit query int false "Number of links to return (default: 20, max: 500)" minimum(1) maximum(500)
+// @Success 200 {object} map[string][]sesevent.LinkClickStats
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/engagement/links [get]
+func (h *MonitoringHandler) GetTopLinks(c *gin.Context) {
+	now := time.Now().UTC()
+	start, end, err := h.parseDateRange(
+		c,
+		now.AddDate(0, 0, -30),
+		now,
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.buildMetricsCacheKey("links:"+strconv.Itoa(limit)+":"+provider, start, end)
+	if cached, ok := h.getMetricsCache(cacheKey); ok {
+		if links, ok := cached.([]*sesevent.LinkClickStats); ok {
+			c.JSON(http.StatusOK, gin.H{"links": links})
+			return
+		}
+	}
+
+	links, err := h.uc.GetTopClickedLinks(c.Request.Context(), start, end, provider, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if links == nil {
+		links = []*sesevent.LinkClickStats{}
+	}
+
+	h.setMetricsCache(cacheKey, links)
+	c.JSON(http.StatusOK, gin.H{"links": links})
+}
+
 func (h *MonitoringHandler) buildMetricsCacheKey(prefix string, start, end *time.Time) string {
 	timezone := h.getTimezoneFromCache()
 	if start == nil && end == nil {
@@ -492,7 +769,122 @@ func (h *MonitoringHandler) convertEventsTimezone(events []*sesevent.Event) erro
 		events[i].EventTimestamp = events[i].EventTimestamp.In(loc)
 		// Convert CreatedAt
 		events[i].CreatedAt = events[i].CreatedAt.In(loc)
+		// Convert OccurredAt, which the message timeline is built from
+		if events[i].OccurredAt != nil {
+			occurredAt := events[i].OccurredAt.In(loc)
+			events[i].OccurredAt = &occurredAt
+		}
 	}
 
 	return nil
 }
+
+// parseEventFilter reads the event filter query parameters shared by the
+// event list and the event stream
+func parseEventFilter(c *gin.Context) (sesevent.Filter, error) {
+	filter := sesevent.Filter{
+		Search:          c.Query("search"),
+		StartDate:       c.Query("start_date"),
+		EndDate:         c.Query("end_date"),
+		BounceSubType:   c.Query("bounce_sub_type"),
+		Status:          c.Query("status"),
+		Sender:          strings.TrimSpace(c.Query("sender")),
+		RecipientDomain: strings.TrimSpace(c.Query("recipient_domain")),
+		ReportingMTA:    c.Query("reporting_mta"),
+		FeedbackType:    c.Query("feedback_type"),
+	}
+
+	for _, date := range []string{filter.StartDate, filter.EndDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return filter, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+		}
+	}
+
+	// Event types are repeatable and may be comma separated; they are
+	// matched case-insensitively
+	for _, value := range c.QueryArray("event_type") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			eventType := sesevent.CanonicalEventType(name)
+			if eventType == "" {
+				return filter, fmt.Errorf("invalid event type %q", name)
+			}
+			filter.EventTypes = append(filter.EventTypes, eventType)
+		}
+	}
+
+	if bounceType := c.Query("bounce_type"); bounceType != "" {
+		filter.BounceType = sesevent.CanonicalBounceType(bounceType)
+		if filter.BounceType == "" {
+			return filter, fmt.Errorf("invalid bounce type %q", bounceType)
+		}
+	}
+
+	provider, err := parseProviderFilter(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Provider = provider
+
+	tags, err := parseTagFilters(c.QueryArray("tag"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+
+	// The query language combines with the other filters
+	query, err := sesevent.ParseQuery(c.Query("q"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Query = query
+
+	return filter, nil
+}
+
+// parseTagFilters turns key:value query values into a tag filter, see
+// sesevent.ParseTag. Repeating a key accepts any of its values.
+func parseTagFilters(values []string) (map[string][]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string][]string, len(values))
+	for _, raw := range values {
+		key, value, ok := sesevent.ParseTag(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag filter %q, expected key:value", raw)
+		}
+		if !slices.Contains(tags[key], value) {
+			tags[key] = append(tags[key], value)
+		}
+	}
+	return tags, nil
+}
+
+// filterError responds to an invalid event filter. Query syntax errors carry
+// the position of the failing token.
+func filterError(c *gin.Context, err error) {
+	var syntaxErr *sesevent.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + syntaxErr.Error(), "syntax_error": syntaxErr})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// parseProviderFilter reads the optional provider query parameter. An empty
+// provider selects events of every provider.
+func parseProviderFilter(c *gin.Context) (string, error) {
+	provider := strings.ToLower(c.Query("provider"))
+	if provider != "" && !sesevent.IsValidProvider(provider) {
+		return "", fmt.Errorf("invalid provider %q", provider)
+	}
+	return provider, nil
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/esp"
+	"ses-monitoring/internal/services"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// providerWebhook is the signature check and ingester of one provider
+type providerWebhook struct {
+	verifier esp.Verifier
+	ingester *eventIngester
+}
+
+type ProviderHandler struct {
+	webhooks map[string]*providerWebhook
+	logBody  bool
+}
+
+// NewProviderHandler creates the webhook handler for the providers other than
+// SES whose credentials are configured. A nil pipeline stores events
+// synchronously and a nil archiver skips the raw event archive.
+func NewProviderHandler(
+	uc *usecase.SESUsecase,
+	pipeline *services.IngestPipeline,
+	deadLetters *usecase.DeadLetterUsecase,
+	archiver *services.EventArchiver,
+	cfg *config.Config,
+) (*ProviderHandler, error) {
+	h := &ProviderHandler{
+		webhooks: make(map[string]*providerWebhook),
+		logBody:  cfg.App.LogBody,
+	}
+	add := func(provider string, verifier esp.Verifier) {
+		h.webhooks[provider] = &providerWebhook{
+			verifier: verifier,
+			ingester: &eventIngester{
+				uc:          uc,
+				pipeline:    pipeline,
+				deadLetters: deadLetters,
+				archiver:    archiver,
+				source:      provider,
+				parse: func(data []byte) ([]*sesevent.Event, error) {
+					return usecase.ParseMessage(provider, data)
+				},
+			},
+		}
+	}
+
+	if cfg.Providers.SendGridPublicKey != "" {
+		verifier, err := esp.NewSendGridVerifier(cfg.Providers.SendGridPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		add(sesevent.ProviderSendGrid, verifier)
+	}
+	if cfg.Providers.MailgunSigningKey != "" {
+		add(sesevent.ProviderMailgun, esp.NewMailgunVerifier(cfg.Providers.MailgunSigningKey))
+	}
+	if cfg.Providers.PostmarkUsername != "" && cfg.Providers.PostmarkPassword != "" {
+		add(sesevent.ProviderPostmark, esp.NewPostmarkVerifier(cfg.Providers.PostmarkUsername, cfg.Providers.PostmarkPassword))
+	}
+	return h, nil
+}
+
+// Providers returns the names of the providers whose webhook is enabled
+func (h *ProviderHandler) Providers() []string {
+	var providers []string
+	for _, provider := range sesevent.Providers {
+		if _, ok := h.webhooks[provider]; ok {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// Handle godoc
+// @Summary Receive events from another email service provider
+// @Description Event webhook for SendGrid (signed event webhook), Mailgun (webhook signing key) and Postmark (basic auth). Events are stored with their provider next to SES events.
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider (sendgrid, mailgun, postmark)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /webhooks/{provider} [post]
+func (h *ProviderHandler) Handle(c *gin.Context) {
+	provider := c.Param("provider")
+	webhook, ok := h.webhooks[provider]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown or unconfigured provider"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	if err := webhook.verifier.Verify(c.Request, body); err != nil {
+		log.Printf("Rejected %s webhook: %v", provider, err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.logBody {
+		log.Printf("Received %s payload: %s", provider, string(body))
+	}
+
+	result, err := webhook.ingester.ingest(c.Request.Context(), []ingestMessage{{Message: string(body)}})
+	if errors.Is(err, services.ErrQueueFull) || errors.Is(err, services.ErrPipelineClosed) {
+		// All three providers retry non-2xx responses
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := "ok"
+	if webhook.ingester.pipeline != nil {
+		status = "queued"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":        status,
+		"events":        result.Events,
+		"dead_lettered": result.DeadLettered,
+	})
+}
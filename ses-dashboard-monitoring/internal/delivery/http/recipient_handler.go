@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecipientHandler struct {
+	uc *usecase.RecipientUsecase
+	// monitoring converts event times to the configured timezone
+	monitoring *MonitoringHandler
+}
+
+func NewRecipientHandler(uc *usecase.RecipientUsecase, monitoring *MonitoringHandler) *RecipientHandler {
+	return &RecipientHandler{uc: uc, monitoring: monitoring}
+}
+
+// GetRecipient godoc
+// @Summary Get the history of a recipient
+// @Description Everything known about one email address: its events (newest first, paginated), bounce and complaint counts, last delivery and engagement, suppression state in the AWS and local suppression lists, and the senders that mailed it. The address is matched case-insensitively.
+// @Tags monitoring
+// @Produce json
+// @Security BearerAuth
+// @Param email path string true "Email address"
+// @Param page query int false "Page number of the events (default: 1)" minimum(1)
+// @Param limit query int false "Number of events per page (default: 50, max: 1000)" minimum(1) maximum(1000)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/recipients/{email} [get]
+func (h *RecipientHandler) GetRecipient(c *gin.Context) {
+	email := strings.TrimSpace(c.Param("email"))
+	if !strings.Contains(email, "@") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
+	page := 1
+	limit := 50
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 1000 {
+			limit = parsed
+		}
+	}
+
+	profile, err := h.uc.GetProfile(c.Request.Context(), email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.uc.GetEvents(c.Request.Context(), email, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.monitoring.convertEventsTimezone(events); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total := profile.Stats.TotalEvents
+	totalPages := (total + limit - 1) / limit
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile": profile,
+		"events":  events,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": totalPages,
+			"hasNext":    page < totalPages,
+			"hasPrev":    page > 1,
+		},
+	})
+}
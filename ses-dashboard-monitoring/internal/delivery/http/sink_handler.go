@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+
+	"ses-monitoring/internal/domain/outbox"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SinkHandler struct {
+	repo  outbox.Repository
+	sinks []string
+}
+
+// NewSinkHandler creates the event sink status handler for the configured sinks
+func NewSinkHandler(repo outbox.Repository, sinks []string) *SinkHandler {
+	return &SinkHandler{repo: repo, sinks: sinks}
+}
+
+// GetStats godoc
+// @Summary Get event sink stats
+// @Description Configured message broker sinks and the events each still has to publish
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/settings/sinks/stats [get]
+func (h *SinkHandler) GetStats(c *gin.Context) {
+	stats, err := h.repo.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if stats == nil {
+		stats = []*outbox.SinkStats{}
+	}
+	sinks := h.sinks
+	if sinks == nil {
+		sinks = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"sinks":  sinks,
+		"outbox": stats,
+	})
+}
@@ -1,161 +1,254 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"ses-monitoring/internal/config"
-	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/domain/snssubscription"
+	"ses-monitoring/internal/infrastructure/aws"
+	"ses-monitoring/internal/services"
 	"ses-monitoring/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
-type SESEvent struct {
-	EventType string `json:"eventType"`
-	Mail      struct {
-		Timestamp     string   `json:"timestamp"`
-		MessageID     string   `json:"messageId"`
-		Source        string   `json:"source"`
-		Destination   []string `json:"destination"`
-		CommonHeaders struct {
-			Subject string `json:"subject"`
-		} `json:"commonHeaders"`
-	} `json:"mail"`
-	Bounce struct {
-		BounceType        string `json:"bounceType"`
-		BounceSubType     string `json:"bounceSubType"`
-		BouncedRecipients []struct {
-			EmailAddress   string `json:"emailAddress"`
-			Action         string `json:"action"`
-			Status         string `json:"status"`
-			DiagnosticCode string `json:"diagnosticCode"`
-		} `json:"bouncedRecipients"`
-		Timestamp    string `json:"timestamp"`
-		ReportingMTA string `json:"reportingMTA"`
-	} `json:"bounce"`
-	Delivery struct {
-		Timestamp            string   `json:"timestamp"`
-		ProcessingTimeMillis int      `json:"processingTimeMillis"`
-		Recipients           []string `json:"recipients"`
-		SmtpResponse         string   `json:"smtpResponse"`
-		RemoteMtaIp          string   `json:"remoteMtaIp"`
-		ReportingMTA         string   `json:"reportingMTA"`
-	} `json:"delivery"`
-}
-
 type SNSHandler struct {
-	uc              *usecase.SESUsecase
-	logBody         bool
-	allowedTopicARN string
+	uc               *usecase.SESUsecase
+	pipeline         *services.IngestPipeline
+	deadLetters      *usecase.DeadLetterUsecase
+	archiver         *services.EventArchiver
+	verifier         *aws.SNSVerifier
+	confirmer        *aws.SNSSubscriptionConfirmer
+	subscriptionRepo snssubscription.Repository
+	logBody          bool
+	allowedTopicARN  string
+
+	// Last time a notification was recorded per topic, to avoid a DB write per message
+	lastSeenMu sync.Mutex
+	lastSeen   map[string]time.Time
 }
 
-func NewSNSHandler(uc *usecase.SESUsecase, cfg *config.Config) *SNSHandler {
+const subscriptionTouchInterval = time.Minute
+
+// NewSNSHandler creates the SNS webhook handler. A nil verifier disables
+// signature verification, a nil pipeline stores events synchronously and a
+// nil archiver skips the raw event archive.
+func NewSNSHandler(
+	uc *usecase.SESUsecase,
+	pipeline *services.IngestPipeline,
+	deadLetters *usecase.DeadLetterUsecase,
+	archiver *services.EventArchiver,
+	verifier *aws.SNSVerifier,
+	confirmer *aws.SNSSubscriptionConfirmer,
+	subscriptionRepo snssubscription.Repository,
+	cfg *config.Config,
+) *SNSHandler {
 	return &SNSHandler{
-		uc:              uc,
-		logBody:         cfg.App.LogBody,
-		allowedTopicARN: cfg.AWS.SNSTopicARN,
+		uc:               uc,
+		pipeline:         pipeline,
+		deadLetters:      deadLetters,
+		archiver:         archiver,
+		verifier:         verifier,
+		confirmer:        confirmer,
+		subscriptionRepo: subscriptionRepo,
+		logBody:          cfg.App.LogBody,
+		allowedTopicARN:  cfg.AWS.SNSTopicARN,
+		lastSeen:         make(map[string]time.Time),
 	}
 }
 
 func (h *SNSHandler) Handle(c *gin.Context) {
-	var payload map[string]interface{}
-	if err := c.BindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
 		return
 	}
 
-	// Check for SNS Subscription Confirmation
-	if typ, ok := payload["Type"].(string); ok {
-		if typ == "SubscriptionConfirmation" {
-			if subscribeURL, ok := payload["SubscribeURL"].(string); ok {
-				log.Printf("SNS Subscription Confirmation received. SubscribeURL: %s", subscribeURL)
-				// Optionally, you can automatically confirm by making a GET request to subscribeURL
-				// But for now, just log it
-				c.JSON(http.StatusOK, gin.H{"status": "subscription confirmation logged"})
-				return
-			}
-		}
+	var msg aws.SNSMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
 	}
 
 	if h.logBody {
-		log.Printf("Received SES payload: %+v", payload)
+		log.Printf("Received SNS payload: %s", string(body))
+	}
+
+	if h.allowedTopicARN != "" && msg.TopicArn != h.allowedTopicARN {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid SNS topic"})
+		return
 	}
 
-	if h.allowedTopicARN != "" {
-		if topicArn, ok := payload["TopicArn"].(string); !ok || topicArn != h.allowedTopicARN {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid SNS topic"})
+	if h.verifier != nil {
+		if err := h.verifier.Verify(c.Request.Context(), &msg); err != nil {
+			log.Printf("Rejected SNS message %s from topic %s: %v", msg.MessageId, msg.TopicArn, err)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid SNS signature: " + err.Error()})
 			return
 		}
 	}
 
-	messageStr, ok := payload["Message"].(string)
-	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Message field"})
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		h.confirmSubscription(c, &msg)
+		return
+	case "UnsubscribeConfirmation":
+		log.Printf("SNS subscription to %s was removed", msg.TopicArn)
+		if err := h.subscriptionRepo.SetStatus(c.Request.Context(), msg.TopicArn, snssubscription.StatusUnsubscribed, "", ""); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "unsubscribed"})
 		return
 	}
 
-	var sesEvent SESEvent
-	if err := json.Unmarshal([]byte(messageStr), &sesEvent); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SES event JSON"})
+	h.recordTopicActivity(c.Request.Context(), msg.TopicArn)
+
+	if msg.Message == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Message field"})
 		return
 	}
 
-	// Parse timestamps
-	eventTimestamp, err := time.Parse(time.RFC3339, sesEvent.Mail.Timestamp)
+	// Archive before parsing so the raw history is complete even for payloads we cannot handle yet
+	if h.archiver != nil {
+		h.archiver.Archive(services.ArchiveRecord{
+			Source:       "sns",
+			SNSMessageID: msg.MessageId,
+			TopicArn:     msg.TopicArn,
+			Message:      msg.Message,
+		})
+	}
+
+	events, err := usecase.ParseSESMessage([]byte(msg.Message))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mail timestamp"})
+		h.deadLetter(c, &msg, deadletter.StageParse, err, http.StatusBadRequest)
 		return
 	}
 
-	// Serialize recipients and tags
-	recipientsJSON, _ := json.Marshal(sesEvent.Mail.Destination)
-	tagsJSON, _ := json.Marshal(map[string]interface{}{}) // Placeholder, bisa ambil dari payload jika ada
-
-	if len(sesEvent.Mail.Destination) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing destination recipients"})
+	if len(events) == 0 {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
 		return
 	}
 
-	// Create event from SES data
-	event := &sesevent.Event{
-		MessageID:      sesEvent.Mail.MessageID,
-		Email:          sesEvent.Mail.Destination[0], // Primary recipient
-		Subject:        sesEvent.Mail.CommonHeaders.Subject,
-		EventType:      sesEvent.EventType,
-		Status:         "SUCCESS",
-		Source:         sesEvent.Mail.Source,
-		Recipients:     string(recipientsJSON),
-		EventTimestamp: eventTimestamp,
-		Tags:           string(tagsJSON),
+	for _, event := range events {
+		event.SNSMessageID = msg.MessageId
 	}
 
-	// Populate based on event type
-	switch sesEvent.EventType {
-	case "Bounce":
-		if len(sesEvent.Bounce.BouncedRecipients) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing bounce recipients"})
+	if h.pipeline != nil {
+		if err := h.pipeline.Enqueue(events); err != nil {
+			// 503 makes SNS back off and redeliver later
+			c.Header("Retry-After", "5")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 			return
 		}
-		event.Status = "FAILED"
-		event.Reason = sesEvent.Bounce.BouncedRecipients[0].DiagnosticCode
-		event.BounceType = sesEvent.Bounce.BounceType
-		event.BounceSubType = sesEvent.Bounce.BounceSubType
-		event.DiagnosticCode = sesEvent.Bounce.BouncedRecipients[0].DiagnosticCode
-		event.ReportingMTA = sesEvent.Bounce.ReportingMTA
-	case "Delivery":
-		event.ProcessingTimeMillis = sesEvent.Delivery.ProcessingTimeMillis
-		event.SmtpResponse = sesEvent.Delivery.SmtpResponse
-		event.RemoteMtaIp = sesEvent.Delivery.RemoteMtaIp
-		event.ReportingMTA = sesEvent.Delivery.ReportingMTA
-	}
-
-	err = h.uc.HandleEvent(c.Request.Context(), event)
+		c.JSON(http.StatusOK, gin.H{"status": "queued"})
+		return
+	}
+
+	stored, err := h.uc.HandleEvents(c.Request.Context(), events)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.deadLetter(c, &msg, deadletter.StageStore, err, http.StatusInternalServerError)
+		return
+	}
+	// Redeliveries are acknowledged so SNS stops retrying
+	if stored == 0 {
+		c.JSON(http.StatusOK, gin.H{"status": "duplicate"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+// deadLetter keeps a payload that could not be processed. Once it is saved SNS
+// gets a success response, since redelivering the same payload would not help;
+// if it cannot be saved the original error status is returned instead.
+func (h *SNSHandler) deadLetter(c *gin.Context, msg *aws.SNSMessage, stage deadletter.Stage, cause error, failureStatus int) {
+	entry := &deadletter.Entry{
+		Source:    "sns",
+		MessageID: msg.MessageId,
+		TopicArn:  msg.TopicArn,
+		Payload:   msg.Message,
+		Stage:     stage,
+		Error:     cause.Error(),
+	}
+	if err := h.deadLetters.Record(c.Request.Context(), entry); err != nil {
+		log.Printf("Failed to dead-letter SNS message %s: %v", msg.MessageId, err)
+		c.JSON(failureStatus, gin.H{"error": cause.Error()})
+		return
+	}
+
+	log.Printf("Dead-lettered SNS message %s (%s): %v", msg.MessageId, stage, cause)
+	c.JSON(http.StatusAccepted, gin.H{"status": "dead_lettered", "id": entry.ID, "error": cause.Error()})
+}
+
+func (h *SNSHandler) confirmSubscription(c *gin.Context, msg *aws.SNSMessage) {
+	ctx := c.Request.Context()
+	log.Printf("SNS subscription confirmation received for topic %s", msg.TopicArn)
+
+	if err := h.subscriptionRepo.SetStatus(ctx, msg.TopicArn, snssubscription.StatusPending, "", ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscriptionArn, err := h.confirmer.Confirm(ctx, msg.SubscribeURL)
+	if err != nil {
+		log.Printf("Failed to confirm SNS subscription for topic %s: %v", msg.TopicArn, err)
+		if setErr := h.subscriptionRepo.SetStatus(ctx, msg.TopicArn, snssubscription.StatusFailed, "", err.Error()); setErr != nil {
+			log.Printf("Failed to store SNS subscription status: %v", setErr)
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to confirm subscription: " + err.Error()})
+		return
+	}
+
+	if err := h.subscriptionRepo.SetStatus(ctx, msg.TopicArn, snssubscription.StatusConfirmed, subscriptionArn, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("SNS subscription confirmed: %s", subscriptionArn)
+	c.JSON(http.StatusOK, gin.H{"status": "subscription confirmed"})
+}
+
+func (h *SNSHandler) recordTopicActivity(ctx context.Context, topicArn string) {
+	if topicArn == "" {
+		return
+	}
+
+	now := time.Now()
+	h.lastSeenMu.Lock()
+	if last, ok := h.lastSeen[topicArn]; ok && now.Sub(last) < subscriptionTouchInterval {
+		h.lastSeenMu.Unlock()
+		return
+	}
+	h.lastSeen[topicArn] = now
+	h.lastSeenMu.Unlock()
+
+	if err := h.subscriptionRepo.RecordMessage(ctx, topicArn); err != nil {
+		log.Printf("Failed to record SNS topic activity for %s: %v", topicArn, err)
+	}
+}
+
+// GetSubscriptions godoc
+// @Summary Get SNS subscriptions
+// @Description List the SNS topics delivering to this endpoint and their subscription status
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]snssubscription.Subscription
+// @Failure 500 {object} map[string]string
+// @Router /api/settings/sns/subscriptions [get]
+func (h *SNSHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.subscriptionRepo.GetAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []*snssubscription.Subscription{}
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
@@ -0,0 +1,278 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ses-monitoring/internal/domain/webhook"
+	"ses-monitoring/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	uc *usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(uc *usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{uc: uc}
+}
+
+type WebhookRequest struct {
+	Name string `json:"name" binding:"required"`
+	URL  string `json:"url" binding:"required"`
+	// Secret is only read on create; a random secret is generated when empty
+	Secret     string            `json:"secret"`
+	EventTypes []string          `json:"event_types"`
+	Senders    []string          `json:"senders"`
+	Tags       map[string]string `json:"tags"`
+	Enabled    *bool             `json:"enabled"`
+}
+
+func (req *WebhookRequest) apply(s *webhook.Subscription) {
+	s.Name = req.Name
+	s.URL = req.URL
+	s.EventTypes = req.EventTypes
+	s.Senders = req.Senders
+	s.Tags = req.Tags
+	if req.Enabled != nil {
+		s.Enabled = *req.Enabled
+	}
+}
+
+// GetWebhooks godoc
+// @Summary List outbound webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]webhook.Subscription
+// @Failure 500 {object} map[string]string
+// @Router /api/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	subscriptions, err := h.uc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []*webhook.Subscription{}
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": subscriptions})
+}
+
+// CreateWebhook godoc
+// @Summary Create an outbound webhook subscription
+// @Description Forward stored events matching the filters to a URL. The response contains the signing secret, which is not shown again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WebhookRequest true "Subscription"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s := &webhook.Subscription{Enabled: true, Secret: req.Secret}
+	req.apply(s)
+	if err := h.uc.Create(c.Request.Context(), s); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"webhook": s, "secret": s.Secret})
+}
+
+// GetWebhook godoc
+// @Summary Get an outbound webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} webhook.Subscription
+// @Failure 404 {object} map[string]string
+// @Router /api/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	s, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// UpdateWebhook godoc
+// @Summary Update an outbound webhook subscription
+// @Description Replace the URL and filters. Setting enabled re-enables a subscription that was disabled after repeated failures.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param request body WebhookRequest true "Subscription"
+// @Success 200 {object} webhook.Subscription
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	req.apply(s)
+	if err := h.uc.Update(c.Request.Context(), s); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete an outbound webhook subscription
+// @Description Deletes the subscription together with its delivery log
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Delete(c.Request.Context(), id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary List deliveries of a webhook subscription
+// @Description Delivery log of a subscription, newest first, with the outcome of the latest attempt
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param status query string false "Filter by status (pending, succeeded, failed)"
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param limit query int false "Deliveries per page (default: 50, max: 500)" minimum(1) maximum(500)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	page := 1
+	limit := 50
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	deliveries, total, err := h.uc.ListDeliveries(c.Request.Context(), id, webhook.DeliveryStatus(c.Query("status")), limit, (page-1)*limit)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*webhook.Delivery{}
+	}
+
+	totalPages := (total + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": totalPages,
+			"hasNext":    page < totalPages,
+			"hasPrev":    page > 1,
+		},
+	})
+}
+
+// RetryWebhookDelivery godoc
+// @Summary Retry a webhook delivery
+// @Description Schedule a pending or failed delivery for an immediate attempt
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func (h *WebhookHandler) RetryWebhookDelivery(c *gin.Context) {
+	id, ok := webhookID(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := webhookID(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.RetryDelivery(c.Request.Context(), id, deliveryID); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Delivery scheduled for retry"})
+}
+
+func webhookID(c *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+		return 0, false
+	}
+	return id, true
+}
+
+func webhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
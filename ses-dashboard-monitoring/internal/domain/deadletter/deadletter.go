@@ -0,0 +1,54 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a dead-letter entry does not exist
+var ErrNotFound = errors.New("dead-letter entry not found")
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusReplayed Status = "replayed"
+)
+
+// Stage is where processing of the payload failed
+type Stage string
+
+const (
+	StageParse Stage = "parse"
+	StageStore Stage = "store"
+	// StageVerify holds queued messages whose SNS signature did not verify
+	StageVerify Stage = "verify"
+)
+
+// Entry is an event payload that could not be processed, kept raw so it
+// can be inspected, corrected and replayed
+type Entry struct {
+	ID            int64     `json:"id"`
+	Source        string    `json:"source"`
+	MessageID     string    `json:"message_id"`
+	TopicArn      string    `json:"topic_arn"`
+	Payload       string    `json:"payload"`
+	Stage         Stage     `json:"stage"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	Status        Status    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+type Repository interface {
+	Create(ctx context.Context, entry *Entry) error
+	GetByID(ctx context.Context, id int64) (*Entry, error)
+	List(ctx context.Context, status Status, limit, offset int) ([]*Entry, error)
+	Count(ctx context.Context, status Status) (int, error)
+	UpdatePayload(ctx context.Context, id int64, payload string) error
+	RecordAttempt(ctx context.Context, id int64, stage Stage, status Status, lastError string) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,96 @@
+package exportjob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// ErrNotFound is returned when a job does not exist
+var ErrNotFound = errors.New("export job not found")
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+	// StatusExpired jobs succeeded, but their file has been deleted
+	StatusExpired Status = "expired"
+)
+
+// Job is an export of filtered events written to the object store in the
+// background
+type Job struct {
+	ID int64 `json:"id"`
+	// UserID is the user who submitted the job; only they and admins see it
+	UserID int64  `json:"user_id"`
+	Status Status `json:"status"`
+
+	Format  string   `json:"format"`
+	Columns []string `json:"columns"`
+	// Params is the query string the job was submitted with, for display
+	Params   string          `json:"params"`
+	Filter   sesevent.Filter `json:"-"`
+	Timezone string          `json:"timezone"`
+
+	// RowsWritten counts exported events, EstimatedRows is the planner's
+	// estimate of the total when the job started
+	RowsWritten   int64 `json:"rows_written"`
+	EstimatedRows int64 `json:"estimated_rows"`
+	// Attempt counts the claims of the job. A worker only updates the job
+	// while its claim is the current attempt, so a worker that stalled past
+	// its lease cannot overwrite the one that took over.
+	Attempt int `json:"attempt"`
+
+	// ArtifactKey is the object store key of the finished file
+	ArtifactKey  string `json:"-"`
+	ArtifactSize int64  `json:"artifact_size"`
+	FileName     string `json:"file_name"`
+	Error        string `json:"error"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	// ExpiresAt is when the file of a succeeded job is deleted
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// IsFinished reports whether the job will not change anymore, apart from expiring
+func (j *Job) IsFinished() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCanceled, StatusExpired:
+		return true
+	}
+	return false
+}
+
+type Repository interface {
+	Create(ctx context.Context, job *Job) error
+	Get(ctx context.Context, id int64) (*Job, error)
+	// List returns the newest jobs of a user, or of every user when userID is 0
+	List(ctx context.Context, userID int64, limit int) ([]*Job, error)
+	// Claim starts the oldest queued job, or a running job whose lease ran out
+	// because its worker died, as a new attempt and leases it until lease from
+	// now. It returns nil when there is nothing to do.
+	Claim(ctx context.Context, lease time.Duration) (*Job, error)
+	// UpdateProgress records the rows written and extends the lease. It
+	// returns false when the attempt no longer runs the job, e.g. it was
+	// canceled or claimed again.
+	UpdateProgress(ctx context.Context, id int64, attempt int, rowsWritten int64, lease time.Duration) (bool, error)
+	SetEstimate(ctx context.Context, id int64, attempt int, estimatedRows int64) error
+	// Complete marks the job succeeded; it returns false when job.Attempt no
+	// longer runs it
+	Complete(ctx context.Context, job *Job) (bool, error)
+	Fail(ctx context.Context, id int64, attempt int, reason string) error
+	// Cancel stops a queued or running job; it returns false for a finished job
+	Cancel(ctx context.Context, id int64) (bool, error)
+	Delete(ctx context.Context, id int64) error
+	// ListExpired returns succeeded jobs whose file expired before now
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*Job, error)
+	MarkExpired(ctx context.Context, id int64) error
+}
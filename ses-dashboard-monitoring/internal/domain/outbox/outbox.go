@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is a stored event waiting to be published to one event sink. Entries
+// are written in the same statement as the event, so every stored event is
+// published at least once.
+type Entry struct {
+	ID        int64
+	EventID   int64
+	Sink      string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// SinkStats summarises the entries a sink still has to publish
+type SinkStats struct {
+	Sink    string     `json:"sink"`
+	Pending int        `json:"pending"`
+	Failing int        `json:"failing"`
+	Oldest  *time.Time `json:"oldest"`
+}
+
+type Repository interface {
+	// Claim returns the oldest due entries of a sink and postpones them by
+	// lease so no other relay picks them up
+	Claim(ctx context.Context, sink string, limit int, lease time.Duration) ([]*Entry, error)
+	// Delete removes entries once the sink acknowledged them
+	Delete(ctx context.Context, ids []int64) error
+	// RecordFailure counts a failed attempt and makes the entries due again after retryIn
+	RecordFailure(ctx context.Context, ids []int64, lastError string, retryIn time.Duration) error
+	Stats(ctx context.Context) ([]*SinkStats, error)
+}
@@ -0,0 +1,116 @@
+package sesevent
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for a cursor it did not encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a position in the event list, which is ordered newest first by
+// event timestamp and then by ID. A cursor points between events: forward
+// pages start after the event it was taken from, backward pages end before it.
+type Cursor struct {
+	EventTimestamp time.Time
+	ID             int64
+	// Backward selects the page before the position rather than after it
+	Backward bool
+}
+
+// NewCursor returns the cursor of the page following (or, backward, preceding) e
+func NewCursor(e *Event, backward bool) *Cursor {
+	return &Cursor{EventTimestamp: e.EventTimestamp, ID: e.ID, Backward: backward}
+}
+
+// Encode returns the cursor as an opaque URL safe string
+func (c *Cursor) Encode() string {
+	direction := "n"
+	if c.Backward {
+		direction = "p"
+	}
+	raw := fmt.Sprintf("%s:%d:%d", direction, c.EventTimestamp.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[0] != "n" && parts[0] != "p") {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{
+		EventTimestamp: time.Unix(0, nanos).UTC(),
+		ID:             id,
+		Backward:       parts[0] == "p",
+	}, nil
+}
+
+// EventPage is one page of a cursor paginated event list, newest first.
+// The cursors are empty when there is no page in that direction.
+type EventPage struct {
+	Events     []*Event
+	NextCursor string
+	PrevCursor string
+}
+
+// NewEventPage builds the page read at cursor. events holds up to limit+1
+// rows in read order (oldest first for backward pages); the extra row only
+// tells that another page follows in the read direction.
+func NewEventPage(events []*Event, cursor *Cursor, limit int) *EventPage {
+	more := len(events) > limit
+	if more {
+		events = events[:limit]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+
+	page := &EventPage{Events: events}
+	if len(events) == 0 {
+		// Past either end; offer the way back to where the client came from
+		if cursor != nil {
+			reverse := *cursor
+			reverse.Backward = !cursor.Backward
+			if backward {
+				page.NextCursor = reverse.Encode()
+			} else {
+				page.PrevCursor = reverse.Encode()
+			}
+		}
+		return page
+	}
+
+	hasNext := more
+	hasPrev := cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		page.NextCursor = NewCursor(events[len(events)-1], false).Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = NewCursor(events[0], true).Encode()
+	}
+	return page
+}
@@ -1,6 +1,41 @@
 package sesevent
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Email service providers whose events are stored. Events of other providers
+// are mapped onto the SES event types.
+const (
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+	ProviderMailgun  = "mailgun"
+	ProviderPostmark = "postmark"
+)
+
+// Providers lists every supported provider
+var Providers = []string{ProviderSES, ProviderSendGrid, ProviderMailgun, ProviderPostmark}
+
+// IsValidProvider reports whether name is one of Providers
+func IsValidProvider(name string) bool {
+	for _, provider := range Providers {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+// How SES delivered the event
+const (
+	// PublishingMechanismEventPublishing is a configuration set event destination ("eventType" payloads)
+	PublishingMechanismEventPublishing = "event_publishing"
+	// PublishingMechanismNotification is a legacy identity feedback notification ("notificationType" payloads)
+	PublishingMechanismNotification = "notification"
+)
 
 type Event struct {
 	ID                   int64
@@ -22,43 +57,134 @@ type Event struct {
 	RemoteMtaIp          string
 	ReportingMTA         string
 	Tags                 string // JSON map
+
+	// Complaint feedback report details
+	ComplaintFeedbackType string
+	ComplaintSubType      string
+	ComplaintUserAgent    string
+	ComplaintArrivalDate  *time.Time
+	FeedbackID            string
+
+	// Engagement (Open/Click) details
+	Link      string
+	LinkTags  string // JSON map
+	IPAddress string
+	UserAgent string
+
+	// Promoted from the SES ses:configuration-set and ses:from-domain tags
+	ConfigurationSet string
+	FromDomain       string
+
+	// DeliveryDelay details
+	DelayType           string
+	DelayExpirationTime *time.Time
+
+	// RenderingFailure details (the error message is stored in Reason)
+	TemplateName string
+
+	// Subscription details
+	ContactList      string
+	TopicPreferences string // JSON {"new": ..., "old": ...}
+
+	// OccurredAt is when the event itself happened (open, click, bounce, ...),
+	// as opposed to EventTimestamp which is when the mail was sent
+	OccurredAt *time.Time
+
+	// PublishingMechanism is PublishingMechanismEventPublishing or PublishingMechanismNotification
+	PublishingMechanism string
+
+	// Provider is the email service provider that reported the event, see Providers
+	Provider string
+
+	// SNSMessageID is the MessageId of the SNS notification that carried the
+	// event, or the event ID assigned by another provider
+	SNSMessageID string
+	// EventKey identifies the event for deduplication, see Fingerprint
+	EventKey string
+
+	// RawMessage is the SES payload the event was parsed from. It is not
+	// stored; it lets a failed write be dead-lettered and replayed.
+	RawMessage string `json:"-"`
+}
+
+// Fingerprint returns a stable key for the event so redelivered notifications
+// can be recognised. Events delivered through SNS are keyed on the SNS
+// MessageId and recipient, events of other providers on their event ID;
+// anything else falls back to the event content.
+func (e *Event) Fingerprint() string {
+	var parts []string
+	if e.SNSMessageID != "" {
+		source := "sns"
+		if e.Provider != "" && e.Provider != ProviderSES {
+			source = e.Provider
+		}
+		parts = []string{source, e.SNSMessageID, e.Email}
+	} else {
+		occurredAt := e.EventTimestamp
+		if e.OccurredAt != nil {
+			occurredAt = *e.OccurredAt
+		}
+		parts = []string{"event", e.MessageID, e.Email, e.EventType, occurredAt.UTC().Format(time.RFC3339Nano), e.Link}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+type LinkClickStats struct {
+	Link          string    `json:"link"`
+	UniqueClicks  int       `json:"unique_clicks"`
+	TotalClicks   int       `json:"total_clicks"`
+	LastClickedAt time.Time `json:"last_clicked_at"`
 }
 
 type DailyMetrics struct {
-	Date           string  `json:"date"`
-	TotalEvents    int     `json:"total_events"`
-	SendCount      int     `json:"send_count"`
-	DeliveryCount  int     `json:"delivery_count"`
-	BounceCount    int     `json:"bounce_count"`
-	ComplaintCount int     `json:"complaint_count"`
-	OpenCount      int     `json:"open_count"`
-	ClickCount     int     `json:"click_count"`
-	BounceRate     float64 `json:"bounce_rate"`
-	DeliveryRate   float64 `json:"delivery_rate"`
+	Date                  string  `json:"date"`
+	TotalEvents           int     `json:"total_events"`
+	SendCount             int     `json:"send_count"`
+	DeliveryCount         int     `json:"delivery_count"`
+	BounceCount           int     `json:"bounce_count"`
+	ComplaintCount        int     `json:"complaint_count"`
+	OpenCount             int     `json:"open_count"`
+	ClickCount            int     `json:"click_count"`
+	DeliveryDelayCount    int     `json:"delivery_delay_count"`
+	RejectCount           int     `json:"reject_count"`
+	RenderingFailureCount int     `json:"rendering_failure_count"`
+	SubscriptionCount     int     `json:"subscription_count"`
+	BounceRate            float64 `json:"bounce_rate"`
+	DeliveryRate          float64 `json:"delivery_rate"`
 }
 
 type MonthlyMetrics struct {
-	Month          string  `json:"month"`
-	TotalEvents    int     `json:"total_events"`
-	SendCount      int     `json:"send_count"`
-	DeliveryCount  int     `json:"delivery_count"`
-	BounceCount    int     `json:"bounce_count"`
-	ComplaintCount int     `json:"complaint_count"`
-	OpenCount      int     `json:"open_count"`
-	ClickCount     int     `json:"click_count"`
-	BounceRate     float64 `json:"bounce_rate"`
-	DeliveryRate   float64 `json:"delivery_rate"`
+	Month                 string  `json:"month"`
+	TotalEvents           int     `json:"total_events"`
+	SendCount             int     `json:"send_count"`
+	DeliveryCount         int     `json:"delivery_count"`
+	BounceCount           int     `json:"bounce_count"`
+	ComplaintCount        int     `json:"complaint_count"`
+	OpenCount             int     `json:"open_count"`
+	ClickCount            int     `json:"click_count"`
+	DeliveryDelayCount    int     `json:"delivery_delay_count"`
+	RejectCount           int     `json:"reject_count"`
+	RenderingFailureCount int     `json:"rendering_failure_count"`
+	SubscriptionCount     int     `json:"subscription_count"`
+	BounceRate            float64 `json:"bounce_rate"`
+	DeliveryRate          float64 `json:"delivery_rate"`
 }
 
 type HourlyMetrics struct {
-	Hour           string  `json:"hour"`
-	TotalEvents    int     `json:"total_events"`
-	SendCount      int     `json:"send_count"`
-	DeliveryCount  int     `json:"delivery_count"`
-	BounceCount    int     `json:"bounce_count"`
-	ComplaintCount int     `json:"complaint_count"`
-	OpenCount      int     `json:"open_count"`
-	ClickCount     int     `json:"click_count"`
-	BounceRate     float64 `json:"bounce_rate"`
-	DeliveryRate   float64 `json:"delivery_rate"`
-}
\ No newline at end of file
+	Hour                  string  `json:"hour"`
+	TotalEvents           int     `json:"total_events"`
+	SendCount             int     `json:"send_count"`
+	DeliveryCount         int     `json:"delivery_count"`
+	BounceCount           int     `json:"bounce_count"`
+	ComplaintCount        int     `json:"complaint_count"`
+	OpenCount             int     `json:"open_count"`
+	ClickCount            int     `json:"click_count"`
+	DeliveryDelayCount    int     `json:"delivery_delay_count"`
+	RejectCount           int     `json:"reject_count"`
+	RenderingFailureCount int     `json:"rendering_failure_count"`
+	SubscriptionCount     int     `json:"subscription_count"`
+	BounceRate            float64 `json:"bounce_rate"`
+	DeliveryRate          float64 `json:"delivery_rate"`
+}
@@ -0,0 +1,77 @@
+package sesevent
+
+// EventTypes lists every stored event type. Events of other providers are
+// mapped onto these.
+var EventTypes = []string{
+	"Send",
+	"Delivery",
+	"Bounce",
+	"Complaint",
+	"Open",
+	"Click",
+	"DeliveryDelay",
+	"Reject",
+	"RenderingFailure",
+	"Subscription",
+}
+
+// IsValidEventType reports whether name is one of EventTypes
+func IsValidEventType(name string) bool {
+	for _, eventType := range EventTypes {
+		if eventType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter selects events. Every set field narrows the selection; fields that
+// hold several values match any of them.
+type Filter struct {
+	// Search matches part of the recipient, subject or sender
+	Search string
+	// StartDate and EndDate bound the send date, both inclusive (YYYY-MM-DD)
+	StartDate string
+	EndDate   string
+
+	EventTypes []string
+	// BounceType is Permanent, Transient or Undetermined
+	BounceType    string
+	BounceSubType string
+	// Status is the recipient status recorded with the event, e.g. SUCCESS or COMPLAINED
+	Status string
+	// Sender is a sender address, "local@" for a local part at any domain or
+	// "@domain" for any address at a domain
+	Sender string
+	// RecipientDomain is the domain of the recipient address, e.g. gmail.com
+	RecipientDomain string
+	// ReportingMTA matches part of the MTA that reported the bounce or delivery
+	ReportingMTA string
+	// FeedbackType is the complaint feedback type and selects complaints only
+	FeedbackType string
+	// Provider is one of Providers
+	Provider string
+	// Tags maps tag keys to accepted values. The event must carry every key
+	// with any of its values.
+	Tags map[string][]string
+	// Query is a parsed search query, see ParseQuery
+	Query *QueryExpr
+}
+
+// IsEmpty reports whether the filter selects every event
+func (f Filter) IsEmpty() bool {
+	return f.Search == "" &&
+		f.StartDate == "" &&
+		f.EndDate == "" &&
+		len(f.EventTypes) == 0 &&
+		f.BounceType == "" &&
+		f.BounceSubType == "" &&
+		f.Status == "" &&
+		f.Sender == "" &&
+		f.RecipientDomain == "" &&
+		f.ReportingMTA == "" &&
+		f.FeedbackType == "" &&
+		f.Provider == "" &&
+		len(f.Tags) == 0 &&
+		f.Query == nil
+}
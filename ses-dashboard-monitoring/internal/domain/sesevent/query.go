@@ -0,0 +1,454 @@
+package sesevent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Limits that keep a query cheap to parse and to plan
+const (
+	MaxQueryLength = 1000
+	maxQueryDepth  = 32
+)
+
+// QueryOp is the kind of a query expression
+type QueryOp int
+
+const (
+	QueryTerm QueryOp = iota
+	QueryAnd
+	QueryOr
+	QueryNot
+)
+
+// Canonical query fields. QueryFieldText is a bare word or phrase, matched
+// against the recipient, subject and sender.
+const (
+	QueryFieldText             = ""
+	QueryFieldEventType        = "event_type"
+	QueryFieldBounceType       = "bounce_type"
+	QueryFieldBounceSubType    = "bounce_sub_type"
+	QueryFieldStatus           = "status"
+	QueryFieldSender           = "sender"
+	QueryFieldRecipient        = "recipient"
+	QueryFieldRecipientDomain  = "recipient_domain"
+	QueryFieldSubject          = "subject"
+	QueryFieldReportingMTA     = "reporting_mta"
+	QueryFieldMessageID        = "message_id"
+	QueryFieldProvider         = "provider"
+	QueryFieldTag              = "tag"
+	QueryFieldFeedbackType     = "feedback_type"
+	QueryFieldConfigurationSet = "configuration_set"
+	QueryFieldAfter            = "after"
+	QueryFieldBefore           = "before"
+)
+
+// queryFields maps every field name and alias to its canonical field
+var queryFields = map[string]string{
+	"type":              QueryFieldEventType,
+	"event":             QueryFieldEventType,
+	"event_type":        QueryFieldEventType,
+	"bounce":            QueryFieldBounceType,
+	"bounce_type":       QueryFieldBounceType,
+	"subtype":           QueryFieldBounceSubType,
+	"bounce_subtype":    QueryFieldBounceSubType,
+	"bounce_sub_type":   QueryFieldBounceSubType,
+	"status":            QueryFieldStatus,
+	"from":              QueryFieldSender,
+	"source":            QueryFieldSender,
+	"sender":            QueryFieldSender,
+	"to":                QueryFieldRecipient,
+	"email":             QueryFieldRecipient,
+	"recipient":         QueryFieldRecipient,
+	"domain":            QueryFieldRecipientDomain,
+	"recipient_domain":  QueryFieldRecipientDomain,
+	"subject":           QueryFieldSubject,
+	"mta":               QueryFieldReportingMTA,
+	"reporting_mta":     QueryFieldReportingMTA,
+	"message":           QueryFieldMessageID,
+	"message_id":        QueryFieldMessageID,
+	"provider":          QueryFieldProvider,
+	"tag":               QueryFieldTag,
+	"feedback":          QueryFieldFeedbackType,
+	"feedback_type":     QueryFieldFeedbackType,
+	"config_set":        QueryFieldConfigurationSet,
+	"configuration_set": QueryFieldConfigurationSet,
+	"after":             QueryFieldAfter,
+	"before":            QueryFieldBefore,
+}
+
+// BounceTypes are the bounce types SES reports
+var BounceTypes = []string{"Permanent", "Transient", "Undetermined"}
+
+// QueryExpr is a parsed search query. Terms hold a canonical field and a
+// validated value; AND and OR hold two or more children, NOT exactly one.
+type QueryExpr struct {
+	Op       QueryOp
+	Field    string
+	Value    string
+	Children []*QueryExpr
+}
+
+// SyntaxError describes why a query could not be parsed. Position is the
+// 0-based character offset of the failing token in the query.
+type SyntaxError struct {
+	Message  string `json:"message"`
+	Position int    `json:"position"`
+	Token    string `json:"token,omitempty"`
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// ParseQuery parses a search query such as
+//
+//	type:Bounce bounce_type:Permanent domain:yahoo.com -source:marketing@ after:2026-09-01
+//
+// Terms are bare words, "quoted phrases" or field:value pairs, where the value
+// may be quoted too. Adjacent terms are ANDed; AND, OR and NOT (or a leading
+// -) combine them and parentheses group them, with NOT binding tightest and
+// OR loosest. An empty query yields nil.
+func ParseQuery(query string) (*QueryExpr, error) {
+	runes := []rune(query)
+	if len(runes) > MaxQueryLength {
+		return nil, &SyntaxError{Message: fmt.Sprintf("query is longer than %d characters", MaxQueryLength), Position: MaxQueryLength}
+	}
+
+	tokens, err := lexQuery(runes)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, tok.errorf("unexpected %s", tok.describe())
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type queryToken struct {
+	kind tokenKind
+	pos  int
+	text string
+
+	// Terms only
+	field    string
+	value    string
+	valuePos int
+}
+
+func (t queryToken) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenTerm:
+		return fmt.Sprintf("term %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+func (t queryToken) errorf(format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Position: t.pos, Token: t.text}
+}
+
+func lexQuery(runes []rune) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			return append(tokens, queryToken{kind: tokenEOF, pos: i}), nil
+		}
+
+		start := i
+		switch r := runes[i]; {
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, pos: start, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, pos: start, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, queryToken{kind: tokenNot, pos: start, text: "-"})
+			i++
+		case r == '"':
+			phrase, end, err := lexPhrase(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, queryToken{kind: tokenTerm, pos: start, text: string(runes[start:i]), value: phrase, valuePos: start})
+		default:
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			word := string(runes[start:i])
+			switch word {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: tokenAnd, pos: start, text: word})
+				continue
+			case "OR":
+				tokens = append(tokens, queryToken{kind: tokenOr, pos: start, text: word})
+				continue
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: tokenNot, pos: start, text: word})
+				continue
+			}
+
+			tok := queryToken{kind: tokenTerm, pos: start, text: word, value: word, valuePos: start}
+			if colon := strings.IndexRune(word, ':'); colon > 0 {
+				tok.field = strings.ToLower(word[:colon])
+				tok.value = word[colon+1:]
+				tok.valuePos = start + len([]rune(word[:colon])) + 1
+				if tok.value == "" {
+					if i == len(runes) || runes[i] != '"' {
+						return nil, &SyntaxError{Message: fmt.Sprintf("missing value for %q", tok.field), Position: tok.valuePos, Token: word}
+					}
+					phrase, end, err := lexPhrase(runes, i)
+					if err != nil {
+						return nil, err
+					}
+					i = end
+					tok.value = phrase
+					tok.text = string(runes[start:i])
+				}
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+}
+
+// lexPhrase reads a quoted phrase starting at the opening quote. A backslash
+// escapes the next character.
+func lexPhrase(runes []rune, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &SyntaxError{Message: "unterminated quoted phrase", Position: start, Token: string(runes[start:])}
+}
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) advance() queryToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr(depth int) (*QueryExpr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryExpr{left}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &QueryExpr{Op: QueryOr, Children: children}, nil
+}
+
+func (p *queryParser) parseAnd(depth int) (*QueryExpr, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryExpr{left}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.advance()
+		case tokenTerm, tokenNot, tokenLParen:
+			// Adjacent terms are ANDed
+		default:
+			if len(children) == 1 {
+				return left, nil
+			}
+			return &QueryExpr{Op: QueryAnd, Children: children}, nil
+		}
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+}
+
+func (p *queryParser) parseUnary(depth int) (*QueryExpr, error) {
+	if depth > maxQueryDepth {
+		tok := p.peek()
+		return nil, tok.errorf("query is nested deeper than %d levels", maxQueryDepth)
+	}
+	if p.peek().kind == tokenNot {
+		p.advance()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryExpr{Op: QueryNot, Children: []*QueryExpr{operand}}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *queryParser) parsePrimary(depth int) (*QueryExpr, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenLParen:
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokenRParen {
+			return nil, closing.errorf("expected \")\" to close the group opened at position %d, found %s", tok.pos, closing.describe())
+		}
+		p.advance()
+		return expr, nil
+	case tokenTerm:
+		return newQueryTerm(tok)
+	default:
+		return nil, tok.errorf("expected a search term, found %s", tok.describe())
+	}
+}
+
+// newQueryTerm resolves the field alias of a term and validates its value
+func newQueryTerm(tok queryToken) (*QueryExpr, error) {
+	valueError := func(format string, args ...interface{}) *SyntaxError {
+		return &SyntaxError{Message: fmt.Sprintf(format, args...), Position: tok.valuePos, Token: tok.text}
+	}
+
+	field := QueryFieldText
+	if tok.field != "" {
+		canonical, ok := queryFields[tok.field]
+		if !ok {
+			return nil, &SyntaxError{Message: fmt.Sprintf("unknown field %q", tok.field), Position: tok.pos, Token: tok.text}
+		}
+		field = canonical
+	}
+
+	value := strings.TrimSpace(tok.value)
+	if value == "" {
+		return nil, valueError("empty search term")
+	}
+
+	switch field {
+	case QueryFieldEventType:
+		canonical := CanonicalEventType(value)
+		if canonical == "" {
+			return nil, valueError("unknown event type %q", value)
+		}
+		value = canonical
+	case QueryFieldBounceType:
+		canonical := CanonicalBounceType(value)
+		if canonical == "" {
+			return nil, valueError("unknown bounce type %q", value)
+		}
+		value = canonical
+	case QueryFieldProvider:
+		value = strings.ToLower(value)
+		if !IsValidProvider(value) {
+			return nil, valueError("unknown provider %q", value)
+		}
+	case QueryFieldTag:
+		if _, _, ok := ParseTag(value); !ok {
+			return nil, valueError("invalid tag %q, expected key:value", value)
+		}
+	case QueryFieldAfter, QueryFieldBefore:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, valueError("invalid date %q, expected YYYY-MM-DD", value)
+		}
+	}
+
+	return &QueryExpr{Op: QueryTerm, Field: field, Value: value}, nil
+}
+
+// ParseTag splits a key:value tag. SES system tags keep their "ses:" prefix
+// as part of the key, so the separator is the first colon after it.
+func ParseTag(raw string) (key, value string, ok bool) {
+	start := 0
+	if strings.HasPrefix(raw, "ses:") {
+		start = len("ses:")
+	}
+	sep := strings.Index(raw[start:], ":")
+	if sep < 0 {
+		return "", "", false
+	}
+	key = raw[:start+sep]
+	value = raw[start+sep+1:]
+	if key == "" || key == "ses:" || value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// CanonicalEventType returns the event type spelled the way it is stored,
+// e.g. "Bounce" for "bounce", or "" for an unknown event type
+func CanonicalEventType(name string) string {
+	return canonicalName(name, EventTypes)
+}
+
+// CanonicalBounceType returns one of BounceTypes ignoring case, or ""
+func CanonicalBounceType(name string) string {
+	return canonicalName(name, BounceTypes)
+}
+
+// canonicalName returns the entry of names that equals name ignoring case, or ""
+func canonicalName(name string, names []string) string {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate
+		}
+	}
+	return ""
+}
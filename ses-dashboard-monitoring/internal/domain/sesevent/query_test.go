@@ -0,0 +1,194 @@
+package sesevent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// formatQuery renders a parsed query as a compact s-expression
+func formatQuery(expr *QueryExpr) string {
+	if expr == nil {
+		return "<nil>"
+	}
+	switch expr.Op {
+	case QueryAnd, QueryOr:
+		op := "AND"
+		if expr.Op == QueryOr {
+			op = "OR"
+		}
+		parts := []string{op}
+		for _, child := range expr.Children {
+			parts = append(parts, formatQuery(child))
+		}
+		return "(" + strings.Join(parts, " ") + ")"
+	case QueryNot:
+		return "(NOT " + formatQuery(expr.Children[0]) + ")"
+	}
+	if expr.Field == QueryFieldText {
+		return "text=" + expr.Value
+	}
+	return expr.Field + "=" + expr.Value
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"empty", "", "<nil>"},
+		{"blank", "   \t", "<nil>"},
+		{"bare word", "invoice", "text=invoice"},
+		{"field", "type:bounce", "event_type=Bounce"},
+		{"adjacent terms are ANDed", "type:Bounce domain:gmail.com", "(AND event_type=Bounce recipient_domain=gmail.com)"},
+		{"explicit AND", "type:Bounce AND domain:gmail.com", "(AND event_type=Bounce recipient_domain=gmail.com)"},
+		{"AND binds tighter than OR", "a b OR c", "(OR (AND text=a text=b) text=c)"},
+		{"OR then AND", "a OR b c", "(OR text=a (AND text=b text=c))"},
+		{"NOT binds tighter than AND", "NOT a b", "(AND (NOT text=a) text=b)"},
+		{"parentheses group", "(a OR b) c", "(AND (OR text=a text=b) text=c)"},
+		{"OR chain is flat", "a OR b OR c", "(OR text=a text=b text=c)"},
+		{"minus negates", "-domain:gmail.com", "(NOT recipient_domain=gmail.com)"},
+		{"minus negates a group", "-(a OR b)", "(NOT (OR text=a text=b))"},
+		{"double negation", "NOT -a", "(NOT (NOT text=a))"},
+		{"minus inside a word is literal", "re-send", "text=re-send"},
+		{"lone minus is a term", "a - b", "(AND text=a text=- text=b)"},
+		{"lowercase operators are words", "a or b", "(AND text=a text=or text=b)"},
+		{"quoted phrase", `"payment failed"`, "text=payment failed"},
+		{"quoted field value", `subject:"Your invoice"`, "subject=Your invoice"},
+		{"escaped quote", `subject:"say \"hi\""`, `subject=say "hi"`},
+		{"escaped backslash", `"a\\b"`, `text=a\b`},
+		{"quoted operator is a term", `"OR"`, "text=OR"},
+		{"phrase keeps parentheses", `"(draft)"`, "text=(draft)"},
+		{"field names ignore case", "TYPE:Delivery", "event_type=Delivery"},
+		{"value after first colon", "tag:campaign:spring", "tag=campaign:spring"},
+		{"ses tag", "tag:ses:configuration-set:marketing", "tag=ses:configuration-set:marketing"},
+		{"bounce type canonical", "bounce:permanent", "bounce_type=Permanent"},
+		{"provider lowercased", "provider:SendGrid", "provider=sendgrid"},
+		{"dates", "after:2026-09-01 before:2026-10-01", "(AND after=2026-09-01 before=2026-10-01)"},
+		{"leading colon is a word", ":foo", "text=:foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error: %v", tt.query, err)
+			}
+			if got := formatQuery(expr); got != tt.want {
+				t.Fatalf("ParseQuery(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryAliases(t *testing.T) {
+	aliases := map[string][]string{
+		QueryFieldEventType:        {"type", "event", "event_type"},
+		QueryFieldBounceSubType:    {"subtype", "bounce_subtype", "bounce_sub_type"},
+		QueryFieldSender:           {"from", "source", "sender"},
+		QueryFieldRecipient:        {"to", "email", "recipient"},
+		QueryFieldRecipientDomain:  {"domain", "recipient_domain"},
+		QueryFieldReportingMTA:     {"mta", "reporting_mta"},
+		QueryFieldMessageID:        {"message", "message_id"},
+		QueryFieldFeedbackType:     {"feedback", "feedback_type"},
+		QueryFieldConfigurationSet: {"config_set", "configuration_set"},
+	}
+	values := map[string]string{QueryFieldEventType: "Click"}
+	for field, names := range aliases {
+		value := values[field]
+		if value == "" {
+			value = "x"
+		}
+		for _, name := range names {
+			expr, err := ParseQuery(name + ":" + value)
+			if err != nil {
+				t.Fatalf("ParseQuery(%s:%s) error: %v", name, value, err)
+			}
+			if expr.Field != field {
+				t.Errorf("alias %q resolved to %q, want %q", name, expr.Field, field)
+			}
+		}
+	}
+}
+
+func TestParseQuerySyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		message  string
+		position int
+		token    string
+	}{
+		{"unknown field", "a colour:red", `unknown field "colour"`, 2, "colour:red"},
+		{"unknown event type", "type:Bounced", `unknown event type "Bounced"`, 5, "type:Bounced"},
+		{"unknown bounce type", "bounce:hard", `unknown bounce type "hard"`, 7, "bounce:hard"},
+		{"unknown provider", "provider:ses2", `unknown provider "ses2"`, 9, "provider:ses2"},
+		{"invalid tag", "tag:campaign", `invalid tag "campaign", expected key:value`, 4, "tag:campaign"},
+		{"invalid date", "after:yesterday", `invalid date "yesterday", expected YYYY-MM-DD`, 6, "after:yesterday"},
+		{"missing value", "type: Bounce", `missing value for "type"`, 5, "type:"},
+		{"empty quoted value", `subject:""`, "empty search term", 8, `subject:""`},
+		{"unterminated phrase", `a "open`, "unterminated quoted phrase", 2, `"open`},
+		{"unclosed group", "(a OR b", `expected ")" to close the group opened at position 0, found end of query`, 7, ""},
+		{"unexpected closing", "a)", `unexpected ")"`, 1, ")"},
+		{"dangling OR", "a OR", "expected a search term, found end of query", 4, ""},
+		{"leading AND", "AND a", `expected a search term, found "AND"`, 0, "AND"},
+		{"dangling NOT", "a NOT", "expected a search term, found end of query", 5, ""},
+		{"empty group", "()", `expected a search term, found ")"`, 1, ")"},
+		{"positions count characters", "über type:x", `unknown event type "x"`, 10, "type:x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseQuery(%q) = %v, want a SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Message != tt.message || syntaxErr.Position != tt.position || syntaxErr.Token != tt.token {
+				t.Fatalf("ParseQuery(%q) = %+v, want {Message:%s Position:%d Token:%s}", tt.query, *syntaxErr, tt.message, tt.position, tt.token)
+			}
+		})
+	}
+}
+
+func TestParseQueryLimits(t *testing.T) {
+	t.Run("length", func(t *testing.T) {
+		if _, err := ParseQuery(strings.Repeat("a", MaxQueryLength)); err != nil {
+			t.Fatalf("query of %d characters: %v", MaxQueryLength, err)
+		}
+		// Characters, not bytes, count towards the limit
+		if _, err := ParseQuery(strings.Repeat("ü", MaxQueryLength)); err != nil {
+			t.Fatalf("query of %d multi-byte characters: %v", MaxQueryLength, err)
+		}
+		_, err := ParseQuery(strings.Repeat("a", MaxQueryLength+1))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Position != MaxQueryLength {
+			t.Fatalf("query of %d characters = %v, want a SyntaxError at %d", MaxQueryLength+1, err, MaxQueryLength)
+		}
+	})
+
+	t.Run("group depth", func(t *testing.T) {
+		nested := func(depth int) string {
+			return strings.Repeat("(", depth) + "a" + strings.Repeat(")", depth)
+		}
+		if _, err := ParseQuery(nested(maxQueryDepth)); err != nil {
+			t.Fatalf("%d nested groups: %v", maxQueryDepth, err)
+		}
+		_, err := ParseQuery(nested(maxQueryDepth + 1))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || !strings.Contains(syntaxErr.Message, "nested deeper") {
+			t.Fatalf("%d nested groups = %v, want a depth error", maxQueryDepth+1, err)
+		}
+		if syntaxErr.Position != maxQueryDepth+1 {
+			t.Fatalf("depth error at %d, want %d", syntaxErr.Position, maxQueryDepth+1)
+		}
+	})
+
+	t.Run("negation depth", func(t *testing.T) {
+		if _, err := ParseQuery(strings.Repeat("NOT ", maxQueryDepth) + "a"); err != nil {
+			t.Fatalf("%d negations: %v", maxQueryDepth, err)
+		}
+		if _, err := ParseQuery(strings.Repeat("-", maxQueryDepth+1) + "a"); err == nil {
+			t.Fatalf("%d negations parsed, want a depth error", maxQueryDepth+1)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package sesevent
+
+import "time"
+
+// RecipientStats summarises every event stored for one email address
+type RecipientStats struct {
+	Email           string `json:"email"`
+	TotalEvents     int    `json:"total_events"`
+	Messages        int    `json:"messages"`
+	SendCount       int    `json:"send_count"`
+	DeliveryCount   int    `json:"delivery_count"`
+	BounceCount     int    `json:"bounce_count"`
+	HardBounceCount int    `json:"hard_bounce_count"`
+	ComplaintCount  int    `json:"complaint_count"`
+	OpenCount       int    `json:"open_count"`
+	ClickCount      int    `json:"click_count"`
+
+	FirstSeenAt     *time.Time `json:"first_seen_at"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
+	LastDeliveryAt  *time.Time `json:"last_delivery_at"`
+	LastBounceAt    *time.Time `json:"last_bounce_at"`
+	LastComplaintAt *time.Time `json:"last_complaint_at"`
+	// LastEngagementAt is the last open or click, LastEngagementType which one it was
+	LastEngagementAt   *time.Time `json:"last_engagement_at"`
+	LastEngagementType string     `json:"last_engagement_type"`
+}
+
+// RecipientSender is a sender address that mailed a recipient
+type RecipientSender struct {
+	Source     string    `json:"source"`
+	Messages   int       `json:"messages"`
+	LastSentAt time.Time `json:"last_sent_at"`
+}
@@ -2,22 +2,52 @@ package sesevent
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrDuplicateEvent is returned by Save when an event with the same key is already stored
+var ErrDuplicateEvent = errors.New("duplicate event")
+
 type Repository interface {
 	Save(ctx context.Context, event *Event) error
+	SaveBatch(ctx context.Context, events []*Event) ([]*Event, error)
+	UpsertBatch(ctx context.Context, events []*Event) (int, error)
 	GetEvents(ctx context.Context) ([]*Event, error)
 	GetEventsPaginated(ctx context.Context, limit, offset int) ([]*Event, error)
-	GetEventsWithFilter(ctx context.Context, limit, offset int, search, startDate, endDate string) ([]*Event, error)
-	GetFilteredEventCount(ctx context.Context, search, startDate, endDate string) (int, error)
-	GetEventCount(ctx context.Context) (int, error)
+	GetEventsWithFilter(ctx context.Context, filter Filter, limit, offset int) ([]*Event, error)
+	GetFilteredEventCount(ctx context.Context, filter Filter) (int, error)
+	// GetEventsByCursor returns up to limit filtered events from the cursor
+	// position, newest first, or oldest first for a backward cursor. A nil
+	// cursor starts at the newest event.
+	GetEventsByCursor(ctx context.Context, filter Filter, cursor *Cursor, limit int) ([]*Event, error)
+	// ForEachEvent calls fn with every filtered event, newest first, without
+	// holding them all in memory. An error from fn stops the iteration and is returned.
+	ForEachEvent(ctx context.Context, filter Filter, fn func(*Event) error) error
+	// EstimateEventCount returns the planner's estimate of the number of filtered events
+	EstimateEventCount(ctx context.Context, filter Filter) (int, error)
+	// GetEventsAfterID returns up to limit filtered events with an ID above
+	// afterID that is not in exclude, oldest first
+	GetEventsAfterID(ctx context.Context, filter Filter, afterID int64, exclude []int64, limit int) ([]*Event, error)
+	GetLatestEventID(ctx context.Context) (int64, error)
+	// GetFirstRecentEventID returns the lowest ID of the events stored within
+	// window, or 0 if there are none
+	GetFirstRecentEventID(ctx context.Context, window time.Duration) (int64, error)
+	GetEventCount(ctx context.Context, provider string) (int, error)
 	GetEventsByType(ctx context.Context, eventType string) ([]*Event, error)
-	GetBounceRate(ctx context.Context) (float64, error)
-	GetDeliveryRate(ctx context.Context) (float64, error)
-	GetDailyMetrics(ctx context.Context, start, end *time.Time) ([]*DailyMetrics, error)
-	GetMonthlyMetrics(ctx context.Context, start, end *time.Time) ([]*MonthlyMetrics, error)
-	GetHourlyMetrics(ctx context.Context, start, end *time.Time) ([]*HourlyMetrics, error)
-	GetEventTypeCounts(ctx context.Context) (map[string]int, error)
+	GetEventsByMessageID(ctx context.Context, messageID string) ([]*Event, error)
+	GetEventsByIDs(ctx context.Context, ids []int64) ([]*Event, error)
+	GetEventsByRecipient(ctx context.Context, email string, limit, offset int) ([]*Event, error)
+	GetRecipientStats(ctx context.Context, email string) (*RecipientStats, error)
+	GetRecipientSenders(ctx context.Context, email string, limit int) ([]*RecipientSender, error)
+	GetBounceRate(ctx context.Context, provider string) (float64, error)
+	GetDeliveryRate(ctx context.Context, provider string) (float64, error)
+	GetDailyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*DailyMetrics, error)
+	GetMonthlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*MonthlyMetrics, error)
+	GetHourlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*HourlyMetrics, error)
+	GetEventTypeCounts(ctx context.Context, provider string) (map[string]int, error)
+	GetTopClickedLinks(ctx context.Context, start, end *time.Time, provider string, limit int) ([]*LinkClickStats, error)
 	DeleteOldEvents(ctx context.Context, cutoffDate time.Time) (int64, error)
+	CountDuplicateEvents(ctx context.Context) (int64, error)
+	DeleteDuplicateEvents(ctx context.Context) (int64, error)
 }
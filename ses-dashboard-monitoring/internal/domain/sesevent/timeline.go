@@ -0,0 +1,209 @@
+package sesevent
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Final delivery states of a recipient. A later event only replaces the state
+// when it is more conclusive, so an open after a complaint still reports the
+// complaint.
+const (
+	RecipientStateSent            = "sent"
+	RecipientStateDelayed         = "delayed"
+	RecipientStateDelivered       = "delivered"
+	RecipientStateOpened          = "opened"
+	RecipientStateClicked         = "clicked"
+	RecipientStateRenderingFailed = "rendering_failed"
+	RecipientStateRejected        = "rejected"
+	RecipientStateBounced         = "bounced"
+	RecipientStateComplained      = "complained"
+)
+
+// recipientStates maps event types to the state they leave a recipient in,
+// ranked by how conclusive the state is. Subscription events do not change it.
+var recipientStates = map[string]struct {
+	state string
+	rank  int
+}{
+	"Send":             {RecipientStateSent, 1},
+	"DeliveryDelay":    {RecipientStateDelayed, 2},
+	"Delivery":         {RecipientStateDelivered, 3},
+	"Open":             {RecipientStateOpened, 4},
+	"Click":            {RecipientStateClicked, 5},
+	"RenderingFailure": {RecipientStateRenderingFailed, 6},
+	"Reject":           {RecipientStateRejected, 7},
+	"Bounce":           {RecipientStateBounced, 8},
+	"Complaint":        {RecipientStateComplained, 9},
+}
+
+// MessageTimeline is the lifecycle of one sent message for each of its recipients
+type MessageTimeline struct {
+	MessageID string `json:"message_id"`
+	Source    string `json:"source"`
+	Subject   string `json:"subject"`
+	Provider  string `json:"provider"`
+	// SentAt is when the provider accepted the message
+	SentAt     time.Time            `json:"sent_at"`
+	Recipients []*RecipientTimeline `json:"recipients"`
+}
+
+// RecipientTimeline lists the events of one recipient in the order they
+// happened, with the milestones and the latencies between them. Latencies are
+// null when either milestone was not reached.
+type RecipientTimeline struct {
+	// Email is empty for the opens and clicks of a message with several
+	// recipients, which SES does not attribute to one of them
+	Email      string `json:"email"`
+	FinalState string `json:"final_state"`
+	// Delivered reports whether the receiving server accepted the message
+	Delivered bool `json:"delivered"`
+	Opens     int  `json:"opens"`
+	Clicks    int  `json:"clicks"`
+
+	SentAt         *time.Time `json:"sent_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	FirstOpenedAt  *time.Time `json:"first_opened_at"`
+	FirstClickedAt *time.Time `json:"first_clicked_at"`
+	BouncedAt      *time.Time `json:"bounced_at"`
+	ComplainedAt   *time.Time `json:"complained_at"`
+
+	SendToDeliveryMs        *int64 `json:"send_to_delivery_ms"`
+	DeliveryToFirstOpenMs   *int64 `json:"delivery_to_first_open_ms"`
+	FirstOpenToFirstClickMs *int64 `json:"first_open_to_first_click_ms"`
+	SendToBounceMs          *int64 `json:"send_to_bounce_ms"`
+	DeliveryToComplaintMs   *int64 `json:"delivery_to_complaint_ms"`
+
+	Events []*TimelineEvent `json:"events"`
+}
+
+// TimelineEvent is a stored event with its offsets from the recipient's
+// previous event and from the send
+type TimelineEvent struct {
+	OccurredAt      time.Time `json:"occurred_at"`
+	SincePreviousMs *int64    `json:"since_previous_ms"`
+	SinceSendMs     *int64    `json:"since_send_ms"`
+	Event           *Event    `json:"event"`
+}
+
+// OccurredTime returns when the event itself happened. SES only reports a
+// separate time for some event types; the others happened when the mail was
+// sent.
+func (e *Event) OccurredTime() time.Time {
+	if e.OccurredAt != nil {
+		return *e.OccurredAt
+	}
+	return e.EventTimestamp
+}
+
+// NewMessageTimeline builds the timeline of a message from its stored events.
+// Recipients are ordered by their first event.
+func NewMessageTimeline(messageID string, events []*Event) *MessageTimeline {
+	sorted := make([]*Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].OccurredTime(), sorted[j].OccurredTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	timeline := &MessageTimeline{MessageID: messageID, Recipients: []*RecipientTimeline{}}
+	byEmail := make(map[string]*RecipientTimeline)
+	for _, e := range sorted {
+		if timeline.Source == "" {
+			timeline.Source = e.Source
+		}
+		if timeline.Subject == "" {
+			timeline.Subject = e.Subject
+		}
+		if timeline.Provider == "" {
+			timeline.Provider = e.Provider
+		}
+		if timeline.SentAt.IsZero() || e.EventTimestamp.Before(timeline.SentAt) {
+			timeline.SentAt = e.EventTimestamp
+		}
+
+		key := strings.ToLower(e.Email)
+		recipient, ok := byEmail[key]
+		if !ok {
+			recipient = &RecipientTimeline{Email: e.Email, Events: []*TimelineEvent{}}
+			byEmail[key] = recipient
+			timeline.Recipients = append(timeline.Recipients, recipient)
+		}
+		recipient.Events = append(recipient.Events, &TimelineEvent{OccurredAt: e.OccurredTime(), Event: e})
+	}
+
+	for _, recipient := range timeline.Recipients {
+		recipient.finish(timeline.SentAt)
+	}
+	return timeline
+}
+
+// finish derives the state, milestones and latencies from the ordered events
+func (r *RecipientTimeline) finish(messageSentAt time.Time) {
+	rank := 0
+	for _, te := range r.Events {
+		at := te.OccurredAt
+		switch te.Event.EventType {
+		case "Send":
+			r.SentAt = firstTime(r.SentAt, at)
+		case "Delivery":
+			r.DeliveredAt = firstTime(r.DeliveredAt, at)
+		case "Open":
+			r.Opens++
+			r.FirstOpenedAt = firstTime(r.FirstOpenedAt, at)
+		case "Click":
+			r.Clicks++
+			r.FirstClickedAt = firstTime(r.FirstClickedAt, at)
+		case "Bounce":
+			r.BouncedAt = firstTime(r.BouncedAt, at)
+		case "Complaint":
+			r.ComplainedAt = firstTime(r.ComplainedAt, at)
+		}
+		if s, ok := recipientStates[te.Event.EventType]; ok && s.rank >= rank {
+			rank = s.rank
+			r.FinalState = s.state
+		}
+	}
+	if r.FinalState == "" {
+		r.FinalState = RecipientStateSent
+	}
+	// Opens, clicks and complaints can only come from a delivered message,
+	// even if the delivery event itself is missing
+	r.Delivered = r.DeliveredAt != nil || r.FirstOpenedAt != nil || r.FirstClickedAt != nil || r.ComplainedAt != nil
+
+	sentAt := messageSentAt
+	if r.SentAt != nil {
+		sentAt = *r.SentAt
+	}
+	var previous *time.Time
+	for _, te := range r.Events {
+		te.SinceSendMs = millisBetween(&sentAt, &te.OccurredAt)
+		te.SincePreviousMs = millisBetween(previous, &te.OccurredAt)
+		previous = &te.OccurredAt
+	}
+
+	r.SendToDeliveryMs = millisBetween(&sentAt, r.DeliveredAt)
+	r.DeliveryToFirstOpenMs = millisBetween(r.DeliveredAt, r.FirstOpenedAt)
+	r.FirstOpenToFirstClickMs = millisBetween(r.FirstOpenedAt, r.FirstClickedAt)
+	r.SendToBounceMs = millisBetween(&sentAt, r.BouncedAt)
+	r.DeliveryToComplaintMs = millisBetween(r.DeliveredAt, r.ComplainedAt)
+}
+
+func firstTime(current *time.Time, t time.Time) *time.Time {
+	if current != nil {
+		return current
+	}
+	return &t
+}
+
+func millisBetween(from, to *time.Time) *int64 {
+	if from == nil || to == nil || from.IsZero() {
+		return nil
+	}
+	ms := to.Sub(*from).Milliseconds()
+	return &ms
+}
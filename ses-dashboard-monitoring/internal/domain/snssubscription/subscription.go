@@ -0,0 +1,32 @@
+package snssubscription
+
+import (
+	"context"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending      Status = "pending"
+	StatusConfirmed    Status = "confirmed"
+	StatusUnsubscribed Status = "unsubscribed"
+	StatusFailed       Status = "failed"
+)
+
+type Subscription struct {
+	TopicArn        string     `json:"topic_arn"`
+	SubscriptionArn string     `json:"subscription_arn"`
+	Status          Status     `json:"status"`
+	LastError       string     `json:"last_error,omitempty"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	LastMessageAt   *time.Time `json:"last_message_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type Repository interface {
+	SetStatus(ctx context.Context, topicArn string, status Status, subscriptionArn, lastError string) error
+	RecordMessage(ctx context.Context, topicArn string) error
+	GetAll(ctx context.Context) ([]*Subscription, error)
+}
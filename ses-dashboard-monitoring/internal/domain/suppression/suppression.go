@@ -44,6 +44,8 @@ type Repository interface {
 	Search(ctx context.Context, query string, limit, offset int) ([]*SuppressionEntry, error)
 	GetSearchCount(ctx context.Context, query string) (int, error)
 	IsSupressed(ctx context.Context, email string) (bool, error)
+	// GetByEmail returns the entry of an address, also when it was removed, or nil
+	GetByEmail(ctx context.Context, email string) (*SuppressionEntry, error)
 	
 	// AWS sync operations
 	UpdateAWSStatus(ctx context.Context, email string, status AWSStatus) error
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+var (
+	// ErrNotFound is returned when a subscription does not exist
+	ErrNotFound = errors.New("webhook subscription not found")
+	// ErrDeliveryNotFound is returned when a delivery does not exist
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Subscription forwards stored events to an outbound URL. Empty filters match
+// every event; a non-empty filter must match for the event to be sent.
+type Subscription struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret signs every payload, see the README. It is only returned when the
+	// subscription is created.
+	Secret string `json:"-"`
+
+	// EventTypes are SES event types, e.g. Bounce and Complaint
+	EventTypes []string `json:"event_types"`
+	// Senders are sender addresses or domains
+	Senders []string `json:"senders"`
+	// Tags must all be present on the event
+	Tags map[string]string `json:"tags"`
+
+	Enabled             bool      `json:"enabled"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	DisabledReason      string    `json:"disabled_reason"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the event passes every filter of the subscription
+func (s *Subscription) Matches(e *sesevent.Event) bool {
+	if len(s.EventTypes) > 0 && !containsFold(s.EventTypes, e.EventType) {
+		return false
+	}
+	if len(s.Senders) > 0 && !matchesSender(s.Senders, e) {
+		return false
+	}
+	if len(s.Tags) > 0 {
+		var tags map[string][]string
+		if err := json.Unmarshal([]byte(e.Tags), &tags); err != nil {
+			return false
+		}
+		for key, value := range s.Tags {
+			if !containsFold(tags[key], value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesSender accepts a full sender address or a domain, which also matches
+// the from domain SES reports
+func matchesSender(senders []string, e *sesevent.Event) bool {
+	source := strings.ToLower(e.Source)
+	for _, sender := range senders {
+		sender = strings.ToLower(sender)
+		if strings.Contains(sender, "@") {
+			if source == sender {
+				return true
+			}
+			continue
+		}
+		if strings.HasSuffix(source, "@"+sender) || strings.EqualFold(e.FromDomain, sender) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body posted for one event
+type Payload struct {
+	EventKey  string          `json:"event_key"`
+	EventType string          `json:"event_type"`
+	Provider  string          `json:"provider"`
+	Event     *sesevent.Event `json:"event"`
+}
+
+// Delivery is one event queued for a subscription, with the outcome of its
+// latest attempt
+type Delivery struct {
+	ID             int64          `json:"id"`
+	SubscriptionID int64          `json:"subscription_id"`
+	EventKey       string         `json:"event_key"`
+	EventType      string         `json:"event_type"`
+	Payload        string         `json:"payload"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode int            `json:"last_status_code"`
+	LastError      string         `json:"last_error"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+}
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, s *Subscription) error
+	GetSubscription(ctx context.Context, id int64) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*Subscription, error)
+	// UpdateSubscription saves name, URL, filters and the enabled flag.
+	// Enabling a subscription clears its failure count.
+	UpdateSubscription(ctx context.Context, s *Subscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+	// RecordSubscriptionResult tracks consecutive failed attempts and disables
+	// the subscription once they reach disableAfter. It reports whether this
+	// call disabled it.
+	RecordSubscriptionResult(ctx context.Context, id int64, success bool, disableAfter int, reason string) (bool, error)
+
+	// EnqueueDeliveries queues deliveries, skipping events already queued for
+	// the subscription, and returns how many were new
+	EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) (int, error)
+	// ClaimDueDeliveries returns pending deliveries of enabled subscriptions
+	// that are due, and postpones them by lease so no other worker picks them up
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	// RecordDeliveryAttempt stores the outcome of an attempt. A pending
+	// delivery is retried after retryIn.
+	RecordDeliveryAttempt(ctx context.Context, id int64, status DeliveryStatus, statusCode int, lastError string, retryIn time.Duration) error
+	GetDelivery(ctx context.Context, id int64) (*Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, status DeliveryStatus, limit, offset int) ([]*Delivery, error)
+	CountDeliveries(ctx context.Context, subscriptionID int64, status DeliveryStatus) (int, error)
+	// RetryDelivery makes a delivery pending and due again
+	RetryDelivery(ctx context.Context, id int64) error
+	// DeleteFinishedDeliveries removes succeeded and failed deliveries older than maxAge
+	DeleteFinishedDeliveries(ctx context.Context, maxAge time.Duration) (int64, error)
+}
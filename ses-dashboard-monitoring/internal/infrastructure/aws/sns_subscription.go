@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SNSSubscriptionConfirmer visits the SubscribeURL SNS sends with a
+// SubscriptionConfirmation message
+type SNSSubscriptionConfirmer struct {
+	client *http.Client
+}
+
+func NewSNSSubscriptionConfirmer(client *http.Client) *SNSSubscriptionConfirmer {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &SNSSubscriptionConfirmer{client: client}
+}
+
+type confirmSubscriptionResponse struct {
+	SubscriptionArn string `xml:"ConfirmSubscriptionResult>SubscriptionArn"`
+}
+
+// Confirm confirms the subscription and returns the resulting subscription ARN
+func (c *SNSSubscriptionConfirmer) Confirm(ctx context.Context, subscribeURL string) (string, error) {
+	if err := ValidateSNSURL(subscribeURL); err != nil {
+		return "", fmt.Errorf("invalid SubscribeURL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read confirmation response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to confirm subscription: HTTP %d", resp.StatusCode)
+	}
+
+	var result confirmSubscriptionResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("invalid confirmation response: %w", err)
+	}
+
+	return result.SubscriptionArn, nil
+}
@@ -0,0 +1,310 @@
+package aws
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSNSUnsigned           = errors.New("SNS message is not signed")
+	ErrSNSSignatureInvalid   = errors.New("SNS signature verification failed")
+	ErrSNSUnsupportedVersion = errors.New("unsupported SNS signature version")
+	ErrSNSInvalidCertURL     = errors.New("invalid SNS signing certificate URL")
+	ErrSNSMessageExpired     = errors.New("SNS message timestamp outside allowed window")
+	ErrSNSUnknownMessageType = errors.New("unknown SNS message type")
+	ErrSNSInvalidTimestamp   = errors.New("invalid SNS message timestamp")
+)
+
+const (
+	defaultSNSMaxMessageAge   = time.Hour
+	defaultSNSMaxClockSkew    = 5 * time.Minute
+	defaultSNSCertCacheTTL    = 24 * time.Hour
+	maxSNSCertificateBodySize = 64 * 1024
+)
+
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9\-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSMessage is the HTTP/S envelope SNS posts to subscribed endpoints
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageId        string `json:"MessageId"`
+	Token            string `json:"Token,omitempty"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+	UnsubscribeURL   string `json:"UnsubscribeURL,omitempty"`
+}
+
+// CertFetcher retrieves the certificate SNS used to sign a message
+type CertFetcher interface {
+	Fetch(ctx context.Context, certURL string) (*x509.Certificate, error)
+}
+
+// HTTPCertFetcher downloads signing certificates over HTTPS. Redirects are
+// not followed: the certificate URL host is validated before the fetch, so a
+// redirect could lead anywhere.
+type HTTPCertFetcher struct {
+	client *http.Client
+}
+
+func NewHTTPCertFetcher() *HTTPCertFetcher {
+	return &HTTPCertFetcher{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (f *HTTPCertFetcher) Fetch(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch signing certificate: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSNSCertificateBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+
+	return ParseCertificatePEM(body)
+}
+
+// ParseCertificatePEM decodes the first certificate in a PEM bundle
+func ParseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("signing certificate is not a PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+type cachedCert struct {
+	cert      *x509.Certificate
+	expiresAt time.Time
+}
+
+// SNSVerifier checks SNS message signatures (SignatureVersion 1 and 2)
+type SNSVerifier struct {
+	fetcher      CertFetcher
+	maxAge       time.Duration
+	maxClockSkew time.Duration
+	cacheTTL     time.Duration
+	now          func() time.Time
+
+	mu    sync.RWMutex
+	certs map[string]cachedCert
+}
+
+func NewSNSVerifier(fetcher CertFetcher) *SNSVerifier {
+	if fetcher == nil {
+		fetcher = NewHTTPCertFetcher()
+	}
+	return &SNSVerifier{
+		fetcher:      fetcher,
+		maxAge:       defaultSNSMaxMessageAge,
+		maxClockSkew: defaultSNSMaxClockSkew,
+		cacheTTL:     defaultSNSCertCacheTTL,
+		now:          time.Now,
+		certs:        make(map[string]cachedCert),
+	}
+}
+
+// SetMaxMessageAge changes how old a message timestamp may be before it is rejected
+func (v *SNSVerifier) SetMaxMessageAge(maxAge time.Duration) {
+	if maxAge > 0 {
+		v.maxAge = maxAge
+	}
+}
+
+// Verify validates the timestamp, certificate URL and signature of an SNS message
+func (v *SNSVerifier) Verify(ctx context.Context, msg *SNSMessage) error {
+	if msg.Signature == "" || msg.SigningCertURL == "" {
+		return ErrSNSUnsigned
+	}
+
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("%w: %q", ErrSNSUnsupportedVersion, msg.SignatureVersion)
+	}
+
+	if err := v.checkTimestamp(msg.Timestamp); err != nil {
+		return err
+	}
+
+	if err := ValidateSNSURL(msg.SigningCertURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrSNSInvalidCertURL, err)
+	}
+	if !strings.HasSuffix(msg.SigningCertURL, ".pem") {
+		return fmt.Errorf("%w: certificate must be a .pem file", ErrSNSInvalidCertURL)
+	}
+
+	stringToSign, err := buildSNSStringToSign(msg)
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not valid base64", ErrSNSSignatureInvalid)
+	}
+
+	cert, err := v.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: signing certificate does not contain an RSA key", ErrSNSSignatureInvalid)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		digest = sum[:]
+	}
+
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return ErrSNSSignatureInvalid
+	}
+	return nil
+}
+
+func (v *SNSVerifier) checkTimestamp(value string) error {
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ErrSNSInvalidTimestamp
+	}
+
+	now := v.now()
+	if now.Sub(ts) > v.maxAge || ts.Sub(now) > v.maxClockSkew {
+		return ErrSNSMessageExpired
+	}
+	return nil
+}
+
+func (v *SNSVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	now := v.now()
+
+	v.mu.RLock()
+	cached, ok := v.certs[certURL]
+	v.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.cert, nil
+	}
+
+	cert, err := v.fetcher.Fetch(ctx, certURL)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("%w: signing certificate is not currently valid", ErrSNSSignatureInvalid)
+	}
+
+	expiresAt := now.Add(v.cacheTTL)
+	if cert.NotAfter.Before(expiresAt) {
+		expiresAt = cert.NotAfter
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cachedCert{cert: cert, expiresAt: expiresAt}
+	v.mu.Unlock()
+
+	return cert, nil
+}
+
+// ValidateSNSURL ensures a URL points at an SNS endpoint over HTTPS
+func ValidateSNSURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return errors.New("URL must use https")
+	}
+	if !snsHostPattern.MatchString(strings.ToLower(u.Hostname())) {
+		return fmt.Errorf("host %q is not an SNS endpoint", u.Hostname())
+	}
+	return nil
+}
+
+func buildSNSStringToSign(msg *SNSMessage) (string, error) {
+	var fields [][2]string
+	switch msg.Type {
+	case "Notification":
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageId},
+		}
+		if msg.Subject != "" {
+			fields = append(fields, [2]string{"Subject", msg.Subject})
+		}
+		fields = append(fields,
+			[2]string{"Timestamp", msg.Timestamp},
+			[2]string{"TopicArn", msg.TopicArn},
+			[2]string{"Type", msg.Type},
+		)
+	case "SubscriptionConfirmation", "UnsubscribeConfirmation":
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageId},
+			{"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp},
+			{"Token", msg.Token},
+			{"TopicArn", msg.TopicArn},
+			{"Type", msg.Type},
+		}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrSNSUnknownMessageType, msg.Type)
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0])
+		b.WriteByte('\n')
+		b.WriteString(field[1])
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
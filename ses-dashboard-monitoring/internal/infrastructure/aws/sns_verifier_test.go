@@ -0,0 +1,254 @@
+package aws
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+
+// fakeCertFetcher stands in for the SNS certificate endpoint
+type fakeCertFetcher struct {
+	cert  *x509.Certificate
+	calls []string
+}
+
+func (f *fakeCertFetcher) Fetch(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	f.calls = append(f.calls, certURL)
+	return f.cert, nil
+}
+
+type snsSigner struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newSNSSigner(t *testing.T, now time.Time) *snsSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &snsSigner{key: key, cert: cert}
+}
+
+// sign sets the signature of msg the way SNS does for its SignatureVersion
+func (s *snsSigner) sign(t *testing.T, msg *SNSMessage) {
+	t.Helper()
+	stringToSign, err := buildSNSStringToSign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hash crypto.Hash
+	var digest []byte
+	if msg.SignatureVersion == "1" {
+		sum := sha1.Sum([]byte(stringToSign))
+		hash, digest = crypto.SHA1, sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		hash, digest = crypto.SHA256, sum[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, hash, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestSNSVerifierVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signer := newSNSSigner(t, now)
+
+	tests := []struct {
+		name string
+		// modify changes the signed message before it is verified
+		modify func(msg *SNSMessage)
+		// unsigned changes the message before it is signed
+		unsigned func(msg *SNSMessage)
+		wantErr  error
+		fetched  bool
+	}{
+		{name: "valid", fetched: true},
+		{
+			name:    "tampered body",
+			modify:  func(msg *SNSMessage) { msg.Message = `{"eventType":"Complaint"}` },
+			wantErr: ErrSNSSignatureInvalid,
+			fetched: true,
+		},
+		{
+			name:     "expired timestamp",
+			unsigned: func(msg *SNSMessage) { msg.Timestamp = now.Add(-2 * time.Hour).Format(time.RFC3339) },
+			wantErr:  ErrSNSMessageExpired,
+		},
+		{
+			name:     "timestamp in the future",
+			unsigned: func(msg *SNSMessage) { msg.Timestamp = now.Add(10 * time.Minute).Format(time.RFC3339) },
+			wantErr:  ErrSNSMessageExpired,
+		},
+		{
+			name: "non-AWS certificate host",
+			unsigned: func(msg *SNSMessage) {
+				msg.SigningCertURL = "https://sns.us-east-1.amazonaws.com.evil.example/cert.pem"
+			},
+			wantErr: ErrSNSInvalidCertURL,
+		},
+		{
+			name:     "plain HTTP certificate URL",
+			unsigned: func(msg *SNSMessage) { msg.SigningCertURL = "http://sns.us-east-1.amazonaws.com/cert.pem" },
+			wantErr:  ErrSNSInvalidCertURL,
+		},
+		{
+			name:    "missing signature",
+			modify:  func(msg *SNSMessage) { msg.Signature = "" },
+			wantErr: ErrSNSUnsigned,
+		},
+	}
+
+	for _, version := range []string{"1", "2"} {
+		for _, tt := range tests {
+			t.Run("v"+version+"/"+tt.name, func(t *testing.T) {
+				msg := &SNSMessage{
+					Type:             "Notification",
+					MessageId:        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+					TopicArn:         "arn:aws:sns:us-east-1:123456789012:ses-events",
+					Subject:          "Amazon SES Email Event Notification",
+					Message:          `{"eventType":"Delivery"}`,
+					Timestamp:        now.Add(-time.Minute).Format(time.RFC3339),
+					SignatureVersion: version,
+					SigningCertURL:   testCertURL,
+				}
+				if tt.unsigned != nil {
+					tt.unsigned(msg)
+				}
+				signer.sign(t, msg)
+				if tt.modify != nil {
+					tt.modify(msg)
+				}
+
+				fetcher := &fakeCertFetcher{cert: signer.cert}
+				verifier := NewSNSVerifier(fetcher)
+				verifier.now = func() time.Time { return now }
+
+				err := verifier.Verify(context.Background(), msg)
+				if tt.wantErr == nil && err != nil {
+					t.Fatalf("Verify() = %v, want nil", err)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() = %v, want %v", err, tt.wantErr)
+				}
+				if fetched := len(fetcher.calls) > 0; fetched != tt.fetched {
+					t.Fatalf("certificate fetched = %v, want %v", fetched, tt.fetched)
+				}
+			})
+		}
+	}
+}
+
+func TestSNSVerifierSubscriptionConfirmation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signer := newSNSSigner(t, now)
+
+	for _, version := range []string{"1", "2"} {
+		msg := &SNSMessage{
+			Type:             "SubscriptionConfirmation",
+			MessageId:        "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+			Token:            "2336412f37fb687f5d51e6e241d09c805a5a57b30d712f794cc5f6a988666d92768dd60a747ba6f3beb71854e285d6ad02428b09ceece29417f1f02d609c582afbacc99c583a916b9981dd2728f4ae6fdb82efd087cc3b7849e05798d2d2785c03b0879594eeac82c01f235d0e717736",
+			TopicArn:         "arn:aws:sns:us-east-1:123456789012:ses-events",
+			Message:          "You have chosen to subscribe to the topic.",
+			SubscribeURL:     "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription",
+			Timestamp:        now.Format(time.RFC3339),
+			SignatureVersion: version,
+			SigningCertURL:   testCertURL,
+		}
+		signer.sign(t, msg)
+
+		verifier := NewSNSVerifier(&fakeCertFetcher{cert: signer.cert})
+		verifier.now = func() time.Time { return now }
+		if err := verifier.Verify(context.Background(), msg); err != nil {
+			t.Fatalf("v%s: Verify() = %v, want nil", version, err)
+		}
+
+		msg.Token = "forged"
+		if err := verifier.Verify(context.Background(), msg); !errors.Is(err, ErrSNSSignatureInvalid) {
+			t.Fatalf("v%s: Verify() with a changed token = %v, want %v", version, err, ErrSNSSignatureInvalid)
+		}
+	}
+}
+
+func TestSNSVerifierCachesCertificate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signer := newSNSSigner(t, now)
+	fetcher := &fakeCertFetcher{cert: signer.cert}
+	verifier := NewSNSVerifier(fetcher)
+	verifier.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		msg := &SNSMessage{
+			Type:             "Notification",
+			MessageId:        "message",
+			TopicArn:         "arn:aws:sns:us-east-1:123456789012:ses-events",
+			Message:          "{}",
+			Timestamp:        now.Format(time.RFC3339),
+			SignatureVersion: "2",
+			SigningCertURL:   testCertURL,
+		}
+		signer.sign(t, msg)
+		if err := verifier.Verify(context.Background(), msg); err != nil {
+			t.Fatalf("Verify() = %v", err)
+		}
+	}
+	if len(fetcher.calls) != 1 {
+		t.Fatalf("certificate fetched %d times, want 1", len(fetcher.calls))
+	}
+}
+
+func TestHTTPCertFetcherDoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/elsewhere.pem" {
+			redirected = true
+			w.Write([]byte("not reached"))
+			return
+		}
+		http.Redirect(w, r, "/elsewhere.pem", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	fetcher := NewHTTPCertFetcher()
+	fetcher.client.Transport = srv.Client().Transport
+
+	_, err := fetcher.Fetch(context.Background(), srv.URL+"/cert.pem")
+	if err == nil {
+		t.Fatal("Fetch() followed a redirect, want an error")
+	}
+	if redirected {
+		t.Fatal("Fetch() requested the redirect target")
+	}
+}
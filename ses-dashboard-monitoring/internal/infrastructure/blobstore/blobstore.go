@@ -0,0 +1,61 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"ses-monitoring/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist. It matches
+// fs.ErrNotExist, so callers need not depend on this package to check for it.
+var ErrNotFound = fmt.Errorf("object not found: %w", fs.ErrNotExist)
+
+// Store is a flat key/value object store. Keys use "/" as separator.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns every key under prefix, in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Config struct {
+	Backend   string // "local" or "s3"
+	LocalPath string
+
+	S3Bucket       string
+	S3Region       string
+	S3Endpoint     string // set for MinIO or other S3-compatible storage
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UsePathStyle bool
+}
+
+// New creates the store selected by cfg.Backend
+func New(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStore(cfg.LocalPath)
+	case "s3":
+		return NewS3Store(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// NewFromConfig creates the store described by the storage section of the app config
+func NewFromConfig(ctx context.Context, cfg *config.Config) (Store, error) {
+	return New(ctx, Config{
+		Backend:        cfg.Storage.Backend,
+		LocalPath:      cfg.Storage.LocalPath,
+		S3Bucket:       cfg.Storage.S3Bucket,
+		S3Region:       cfg.Storage.S3Region,
+		S3Endpoint:     cfg.Storage.S3Endpoint,
+		S3AccessKey:    cfg.Storage.S3AccessKey,
+		S3SecretKey:    cfg.Storage.S3SecretKey,
+		S3UsePathStyle: cfg.Storage.S3UsePathStyle,
+	})
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sns_subscriptions (
+    topic_arn VARCHAR(255) PRIMARY KEY,
+    subscription_arn VARCHAR(512) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'confirmed', 'unsubscribed', 'failed'
+    last_error TEXT NOT NULL DEFAULT '',
+    confirmed_at TIMESTAMP,
+    last_message_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
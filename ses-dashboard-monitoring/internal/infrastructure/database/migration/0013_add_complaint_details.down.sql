@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_ses_events_complaint_feedback_type;
+
+ALTER TABLE ses_events DROP COLUMN IF EXISTS feedback_id;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS complaint_arrival_date;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS complaint_user_agent;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS complaint_sub_type;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS complaint_feedback_type;
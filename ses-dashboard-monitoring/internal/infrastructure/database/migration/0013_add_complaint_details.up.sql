@@ -0,0 +1,7 @@
+ALTER TABLE ses_events ADD COLUMN complaint_feedback_type VARCHAR(50) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN complaint_sub_type VARCHAR(50) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN complaint_user_agent TEXT DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN complaint_arrival_date TIMESTAMP;
+ALTER TABLE ses_events ADD COLUMN feedback_id VARCHAR(255) DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_ses_events_complaint_feedback_type ON ses_events(complaint_feedback_type) WHERE event_type = 'Complaint';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_ses_events_click_occurred_at;
+
+ALTER TABLE ses_events DROP COLUMN IF EXISTS occurred_at;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS user_agent;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS link_tags;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS link;
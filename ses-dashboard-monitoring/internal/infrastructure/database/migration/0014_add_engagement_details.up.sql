@@ -0,0 +1,7 @@
+ALTER TABLE ses_events ADD COLUMN link TEXT DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN link_tags TEXT DEFAULT ''; -- JSON map
+ALTER TABLE ses_events ADD COLUMN ip_address VARCHAR(50) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN user_agent TEXT DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN occurred_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_ses_events_click_occurred_at ON ses_events(occurred_at DESC) WHERE event_type = 'Click';
@@ -0,0 +1,5 @@
+ALTER TABLE ses_events DROP COLUMN IF EXISTS topic_preferences;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS contact_list;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS template_name;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS delay_expiration_time;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS delay_type;
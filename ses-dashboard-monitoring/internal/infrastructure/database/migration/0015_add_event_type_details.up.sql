@@ -0,0 +1,5 @@
+ALTER TABLE ses_events ADD COLUMN delay_type VARCHAR(50) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN delay_expiration_time TIMESTAMP;
+ALTER TABLE ses_events ADD COLUMN template_name VARCHAR(255) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN contact_list VARCHAR(255) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN topic_preferences TEXT DEFAULT ''; -- JSON {"new": ..., "old": ...}
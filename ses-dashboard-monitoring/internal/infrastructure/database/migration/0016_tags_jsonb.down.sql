@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_ses_events_from_domain;
+DROP INDEX IF EXISTS idx_ses_events_configuration_set;
+DROP INDEX IF EXISTS idx_ses_events_tags;
+
+ALTER TABLE ses_events DROP COLUMN IF EXISTS from_domain;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS configuration_set;
+
+ALTER TABLE ses_events ALTER COLUMN tags DROP NOT NULL;
+ALTER TABLE ses_events ALTER COLUMN tags DROP DEFAULT;
+ALTER TABLE ses_events ALTER COLUMN tags TYPE TEXT USING tags::text;
@@ -0,0 +1,17 @@
+ALTER TABLE ses_events ALTER COLUMN tags TYPE JSONB USING (
+  CASE WHEN tags IS NULL OR tags = '' THEN '{}' ELSE tags END
+)::jsonb;
+ALTER TABLE ses_events ALTER COLUMN tags SET DEFAULT '{}';
+ALTER TABLE ses_events ALTER COLUMN tags SET NOT NULL;
+
+ALTER TABLE ses_events ADD COLUMN configuration_set VARCHAR(255) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN from_domain VARCHAR(255) DEFAULT '';
+
+UPDATE ses_events SET
+  configuration_set = COALESCE(tags->'ses:configuration-set'->>0, ''),
+  from_domain = COALESCE(tags->'ses:from-domain'->>0, '')
+WHERE tags ? 'ses:configuration-set' OR tags ? 'ses:from-domain';
+
+CREATE INDEX IF NOT EXISTS idx_ses_events_tags ON ses_events USING GIN (tags jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_ses_events_configuration_set ON ses_events(configuration_set);
+CREATE INDEX IF NOT EXISTS idx_ses_events_from_domain ON ses_events(from_domain);
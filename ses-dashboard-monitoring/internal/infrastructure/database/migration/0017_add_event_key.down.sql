@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_ses_events_event_key;
+
+ALTER TABLE ses_events DROP COLUMN IF EXISTS event_key;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS sns_message_id;
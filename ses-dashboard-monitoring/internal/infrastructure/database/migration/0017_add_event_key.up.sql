@@ -0,0 +1,5 @@
+ALTER TABLE ses_events ADD COLUMN sns_message_id VARCHAR(100) DEFAULT '';
+ALTER TABLE ses_events ADD COLUMN event_key VARCHAR(64);
+
+-- Rows stored before this migration keep a NULL key; run cmd/dedupe to clean up their duplicates
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ses_events_event_key ON ses_events(event_key);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+  id BIGSERIAL PRIMARY KEY,
+  source VARCHAR(50) NOT NULL DEFAULT 'sns',
+  message_id VARCHAR(100) NOT NULL DEFAULT '',
+  topic_arn VARCHAR(255) NOT NULL DEFAULT '',
+  payload TEXT NOT NULL, -- raw SES event JSON
+  stage VARCHAR(20) NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  attempts INTEGER NOT NULL DEFAULT 1,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now(),
+  last_attempt_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_status_created_at ON dead_letters(status, created_at DESC);
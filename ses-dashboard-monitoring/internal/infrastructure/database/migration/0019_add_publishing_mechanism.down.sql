@@ -0,0 +1 @@
+ALTER TABLE ses_events DROP COLUMN IF EXISTS publishing_mechanism;
@@ -0,0 +1,5 @@
+-- event_publishing (configuration set event destinations) or notification (legacy identity notifications)
+ALTER TABLE ses_events ADD COLUMN publishing_mechanism VARCHAR(32) NOT NULL DEFAULT 'event_publishing';
+
+-- Identity notifications were stored without an event type before they were recognised
+UPDATE ses_events SET publishing_mechanism = 'notification' WHERE event_type IS NULL OR event_type = '';
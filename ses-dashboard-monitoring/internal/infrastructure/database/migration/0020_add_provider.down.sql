@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ses_events_provider_timestamp;
+ALTER TABLE ses_events DROP COLUMN IF EXISTS provider;
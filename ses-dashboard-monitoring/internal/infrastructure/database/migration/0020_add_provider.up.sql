@@ -0,0 +1,4 @@
+-- Email service provider that reported the event (ses, sendgrid, mailgun, postmark)
+ALTER TABLE ses_events ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT 'ses';
+
+CREATE INDEX IF NOT EXISTS idx_ses_events_provider_timestamp ON ses_events(provider, event_timestamp);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  url TEXT NOT NULL,
+  secret VARCHAR(128) NOT NULL,
+  event_types JSONB NOT NULL DEFAULT '[]', -- empty matches every event type
+  senders JSONB NOT NULL DEFAULT '[]', -- sender addresses or domains
+  tags JSONB NOT NULL DEFAULT '{}', -- tag key/value pairs that must all match
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  consecutive_failures INTEGER NOT NULL DEFAULT 0,
+  disabled_reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  event_key VARCHAR(64) NOT NULL,
+  event_type VARCHAR(50) NOT NULL DEFAULT '',
+  payload TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+  last_status_code INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now(),
+  delivered_at TIMESTAMP
+);
+
+-- An event is delivered to a subscription once, however often it is ingested
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_event ON webhook_deliveries(subscription_id, event_key);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_created_at ON webhook_deliveries(subscription_id, created_at DESC);
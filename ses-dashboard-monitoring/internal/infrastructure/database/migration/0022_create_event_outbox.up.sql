@@ -0,0 +1,13 @@
+-- Stored events waiting to be published, one row per event and sink
+CREATE TABLE IF NOT EXISTS event_outbox (
+  id BIGSERIAL PRIMARY KEY,
+  event_id BIGINT NOT NULL REFERENCES ses_events(id) ON DELETE CASCADE,
+  sink VARCHAR(32) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_sink_due ON event_outbox(sink, next_attempt_at, id);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_event_id ON event_outbox(event_id);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_ses_events_recipient;
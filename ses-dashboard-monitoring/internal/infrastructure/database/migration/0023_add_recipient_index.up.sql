@@ -0,0 +1,3 @@
+-- Recipient lookups match lower(email); built concurrently so ingestion
+-- keeps writing while the index is created on a large table
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_ses_events_recipient ON ses_events (lower(email), event_timestamp DESC, id DESC);
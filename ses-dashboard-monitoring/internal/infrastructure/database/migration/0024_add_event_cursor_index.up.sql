@@ -0,0 +1,3 @@
+-- Cursor pagination seeks on (event_timestamp, id); built concurrently so
+-- ingestion keeps writing while the index is created on a large table
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_ses_events_timestamp_id ON ses_events (event_timestamp DESC, id DESC);
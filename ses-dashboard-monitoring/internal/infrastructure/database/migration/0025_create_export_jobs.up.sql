@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS export_jobs (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL DEFAULT 0,
+  status VARCHAR(20) NOT NULL DEFAULT 'queued',
+  format VARCHAR(10) NOT NULL,
+  columns JSONB NOT NULL DEFAULT '[]',
+  params TEXT NOT NULL DEFAULT '', -- query string the job was submitted with
+  filter JSONB NOT NULL DEFAULT '{}',
+  timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  rows_written BIGINT NOT NULL DEFAULT 0,
+  estimated_rows BIGINT NOT NULL DEFAULT 0,
+  artifact_key TEXT NOT NULL DEFAULT '',
+  artifact_size BIGINT NOT NULL DEFAULT 0,
+  file_name TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  lease_until TIMESTAMP, -- a running job whose lease ran out is picked up again
+  attempt INT NOT NULL DEFAULT 0, -- bumped by every claim; only the worker holding the current attempt may update the job
+  created_at TIMESTAMP DEFAULT now(),
+  started_at TIMESTAMP,
+  finished_at TIMESTAMP,
+  expires_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_claim ON export_jobs(status, id) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_export_jobs_user_created_at ON export_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires_at ON export_jobs(expires_at) WHERE status = 'succeeded';
@@ -0,0 +1,107 @@
+package esp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Mailgun retries a failed webhook for about 8 hours (10m, 10m, 15m, 30m, 1h,
+// 2h and 4h) with the original signature, so a signature stays valid for the
+// whole retry schedule plus some clock skew
+const mailgunMaxSignatureAge = 8*time.Hour + 10*time.Minute
+
+// MailgunVerifier checks the HMAC signature Mailgun embeds in every webhook
+// body. The signature only covers the timestamp and token, not the event
+// data, so each token is remembered with a digest of its body until the
+// signature expires: a retry repeats the same body, while a captured
+// signature attached to another body is rejected. The cache is per process.
+type MailgunVerifier struct {
+	signingKey []byte
+	now        func() time.Time
+
+	mu        sync.Mutex
+	seen      map[string]seenToken
+	nextSweep time.Time
+}
+
+type seenToken struct {
+	body      [sha256.Size]byte
+	expiresAt time.Time
+}
+
+// NewMailgunVerifier takes the HTTP webhook signing key of the Mailgun account
+func NewMailgunVerifier(signingKey string) *MailgunVerifier {
+	return &MailgunVerifier{
+		signingKey: []byte(signingKey),
+		now:        time.Now,
+		seen:       make(map[string]seenToken),
+	}
+}
+
+// Verify checks that signature is the HMAC-SHA256 of timestamp followed by
+// token, that the timestamp is recent and that the token was not used for a
+// different body
+func (v *MailgunVerifier) Verify(r *http.Request, body []byte) error {
+	var payload struct {
+		Signature struct {
+			Timestamp string `json:"timestamp"`
+			Token     string `json:"token"`
+			Signature string `json:"signature"`
+		} `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ErrMissingSignature
+	}
+	sig := payload.Signature
+	if sig.Timestamp == "" || sig.Token == "" || sig.Signature == "" {
+		return ErrMissingSignature
+	}
+
+	expected, err := hex.DecodeString(sig.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, v.signingKey)
+	mac.Write([]byte(sig.Timestamp))
+	mac.Write([]byte(sig.Token))
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrInvalidSignature
+	}
+
+	now := v.now()
+	if err := checkTimestamp(sig.Timestamp, now, mailgunMaxSignatureAge); err != nil {
+		return err
+	}
+	return v.remember(sig.Token, sha256.Sum256(body), now)
+}
+
+// remember records the body a token was first seen with and rejects the
+// token with any other body
+func (v *MailgunVerifier) remember(token string, body [sha256.Size]byte, now time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if now.After(v.nextSweep) {
+		for t, entry := range v.seen {
+			if now.After(entry.expiresAt) {
+				delete(v.seen, t)
+			}
+		}
+		v.nextSweep = now.Add(time.Minute)
+	}
+
+	if entry, ok := v.seen[token]; ok && now.Before(entry.expiresAt) {
+		if entry.body != body {
+			return ErrReplayedSignature
+		}
+		return nil
+	}
+	v.seen[token] = seenToken{body: body, expiresAt: now.Add(mailgunMaxSignatureAge + maxClockSkew)}
+	return nil
+}
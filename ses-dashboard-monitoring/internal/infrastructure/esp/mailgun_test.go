@@ -0,0 +1,106 @@
+package esp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testMailgunKey = "key-test"
+
+// mailgunBody returns a webhook body signed with testMailgunKey
+func mailgunBody(t *testing.T, timestamp time.Time, token, event string) []byte {
+	t.Helper()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testMailgunKey))
+	mac.Write([]byte(ts + token))
+	body, err := json.Marshal(map[string]interface{}{
+		"signature": map[string]string{
+			"timestamp": ts,
+			"token":     token,
+			"signature": hex.EncodeToString(mac.Sum(nil)),
+		},
+		"event-data": map[string]string{"event": event},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestMailgunVerifierVerify(t *testing.T) {
+	now := time.Unix(1772366400, 0)
+	tests := []struct {
+		name string
+		body []byte
+		want error
+	}{
+		{"valid", mailgunBody(t, now, "token-valid", "delivered"), nil},
+		{"retried within the retry schedule", mailgunBody(t, now.Add(-8*time.Hour), "token-retried", "delivered"), nil},
+		{"expired", mailgunBody(t, now.Add(-9*time.Hour), "token-expired", "delivered"), ErrStaleSignature},
+		{"from the future", mailgunBody(t, now.Add(10*time.Minute), "token-future", "delivered"), ErrStaleSignature},
+		{"unsigned", []byte(`{"event-data":{"event":"delivered"}}`), ErrMissingSignature},
+		{"not JSON", []byte("event=delivered"), ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewMailgunVerifier(testMailgunKey)
+			v.now = func() time.Time { return now }
+			err := v.Verify(httptest.NewRequest("POST", "/webhooks/mailgun", nil), tt.body)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMailgunVerifierRejectsWrongKey(t *testing.T) {
+	v := NewMailgunVerifier("another-key")
+	body := mailgunBody(t, time.Now(), "token", "delivered")
+	if err := v.Verify(httptest.NewRequest("POST", "/webhooks/mailgun", nil), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestMailgunVerifierReplays(t *testing.T) {
+	now := time.Unix(1772366400, 0)
+	v := NewMailgunVerifier(testMailgunKey)
+	v.now = func() time.Time { return now }
+	r := httptest.NewRequest("POST", "/webhooks/mailgun", nil)
+
+	original := mailgunBody(t, now, "token-1", "delivered")
+	if err := v.Verify(r, original); err != nil {
+		t.Fatalf("first delivery: Verify() = %v", err)
+	}
+
+	// Mailgun retries with the same signature and body
+	now = now.Add(10 * time.Minute)
+	if err := v.Verify(r, original); err != nil {
+		t.Errorf("retry: Verify() = %v, want nil", err)
+	}
+
+	// The signature block does not cover the event data, so a captured one
+	// could otherwise be attached to any body
+	forged := mailgunBody(t, now.Add(-10*time.Minute), "token-1", "complained")
+	if err := v.Verify(r, forged); !errors.Is(err, ErrReplayedSignature) {
+		t.Errorf("forged body: Verify() = %v, want %v", err, ErrReplayedSignature)
+	}
+
+	// Seen tokens are forgotten once their signature expired
+	now = now.Add(mailgunMaxSignatureAge + maxClockSkew)
+	if err := v.Verify(r, mailgunBody(t, now, "token-2", "delivered")); err != nil {
+		t.Fatalf("later delivery: Verify() = %v", err)
+	}
+	v.mu.Lock()
+	_, kept := v.seen["token-1"]
+	v.mu.Unlock()
+	if kept {
+		t.Error("expired token is still cached")
+	}
+}
@@ -0,0 +1,30 @@
+package esp
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// PostmarkVerifier checks the basic auth credentials configured in the
+// Postmark webhook URL. Postmark does not sign webhook requests.
+type PostmarkVerifier struct {
+	username string
+	password string
+}
+
+func NewPostmarkVerifier(username, password string) *PostmarkVerifier {
+	return &PostmarkVerifier{username: username, password: password}
+}
+
+func (v *PostmarkVerifier) Verify(r *http.Request, body []byte) error {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return ErrMissingSignature
+	}
+	userMatch := subtle.ConstantTimeCompare([]byte(username), []byte(v.username))
+	passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(v.password))
+	if userMatch&passwordMatch != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package esp
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+
+	// sendGridMaxSignatureAge bounds how long a captured request can be replayed
+	sendGridMaxSignatureAge = 15 * time.Minute
+)
+
+// SendGridVerifier checks the ECDSA signature of the SendGrid Signed Event Webhook
+type SendGridVerifier struct {
+	publicKey *ecdsa.PublicKey
+	now       func() time.Time
+}
+
+// NewSendGridVerifier takes the verification key shown in the SendGrid Event
+// Webhook settings, base64 encoded or as a PEM block.
+func NewSendGridVerifier(publicKey string) (*SendGridVerifier, error) {
+	publicKey = strings.TrimSpace(publicKey)
+
+	var der []byte
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SendGrid verification key: %w", err)
+		}
+		der = decoded
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SendGrid verification key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("invalid SendGrid verification key: not an ECDSA key")
+	}
+	return &SendGridVerifier{publicKey: ecKey, now: time.Now}, nil
+}
+
+// Verify checks the signature over the timestamp header followed by the body,
+// and that the timestamp is recent
+func (v *SendGridVerifier) Verify(r *http.Request, body []byte) error {
+	signature := r.Header.Get(sendGridSignatureHeader)
+	timestamp := r.Header.Get(sendGridTimestampHeader)
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	digest := sha256.New()
+	digest.Write([]byte(timestamp))
+	digest.Write(body)
+	if !ecdsa.VerifyASN1(v.publicKey, digest.Sum(nil), sig) {
+		return ErrInvalidSignature
+	}
+	return checkTimestamp(timestamp, v.now(), sendGridMaxSignatureAge)
+}
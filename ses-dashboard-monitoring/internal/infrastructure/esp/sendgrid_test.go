@@ -0,0 +1,81 @@
+package esp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type sendGridSigner struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+}
+
+func newSendGridSigner(t *testing.T) *sendGridSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &sendGridSigner{key: key, publicKey: base64.StdEncoding.EncodeToString(der)}
+}
+
+// request returns a webhook request signed the way SendGrid does
+func (s *sendGridSigner) request(t *testing.T, timestamp time.Time, body []byte) *http.Request {
+	t.Helper()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	digest := sha256.Sum256(append([]byte(ts), body...))
+	signature, err := ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("POST", "/webhooks/sendgrid", nil)
+	r.Header.Set(sendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	r.Header.Set(sendGridTimestampHeader, ts)
+	return r
+}
+
+func TestSendGridVerifierVerify(t *testing.T) {
+	signer := newSendGridSigner(t)
+	v, err := NewSendGridVerifier(signer.publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1772366400, 0)
+	v.now = func() time.Time { return now }
+	body := []byte(`[{"event":"delivered","email":"jane@example.com"}]`)
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		body    []byte
+		want    error
+	}{
+		{"valid", signer.request(t, now, body), body, nil},
+		{"recent", signer.request(t, now.Add(-10*time.Minute), body), body, nil},
+		{"tampered body", signer.request(t, now, body), []byte(`[{"event":"spamreport"}]`), ErrInvalidSignature},
+		{"expired", signer.request(t, now.Add(-time.Hour), body), body, ErrStaleSignature},
+		{"from the future", signer.request(t, now.Add(10*time.Minute), body), body, ErrStaleSignature},
+		{"unsigned", httptest.NewRequest("POST", "/webhooks/sendgrid", nil), body, ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(tt.request, tt.body); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
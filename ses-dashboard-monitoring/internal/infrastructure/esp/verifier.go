@@ -0,0 +1,39 @@
+// Package esp verifies webhook requests of email service providers other than SES
+package esp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrMissingSignature  = errors.New("webhook signature missing")
+	ErrInvalidSignature  = errors.New("webhook signature verification failed")
+	ErrStaleSignature    = errors.New("webhook signature timestamp outside allowed window")
+	ErrReplayedSignature = errors.New("webhook signature already used for another payload")
+)
+
+// maxClockSkew is how far a signature timestamp may lie in the future
+const maxClockSkew = 5 * time.Minute
+
+// Verifier checks that a webhook request was sent by the provider. body is
+// the raw request body, which some providers sign.
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
+
+// checkTimestamp parses a signature timestamp in unix seconds and rejects it
+// when it is older than maxAge or too far in the future
+func checkTimestamp(value string, now time.Time, maxAge time.Duration) error {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	signedAt := time.Unix(seconds, 0)
+	if now.Sub(signedAt) > maxAge || signedAt.Sub(now) > maxClockSkew {
+		return ErrStaleSignature
+	}
+	return nil
+}
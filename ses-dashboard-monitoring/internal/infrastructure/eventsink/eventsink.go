@@ -0,0 +1,155 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"ses-monitoring/internal/config"
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// DefaultTopicTemplate publishes every event type to its own topic, e.g. ses.events.bounce
+const DefaultTopicTemplate = "ses.events.{event_type}"
+
+// Sink publishes events to a message broker
+type Sink interface {
+	// Name identifies the sink in the event outbox
+	Name() string
+	// Publish returns once the broker acknowledged every message. On error
+	// the whole batch is retried, so brokers may see a message more than once.
+	Publish(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+// Message is one event on its way to a broker
+type Message struct {
+	Topic string
+	// ID is the event key; brokers that support it drop redelivered duplicates
+	ID string
+	// Key groups the events of one email, e.g. onto one Kafka partition
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// NewMessage encodes a stored event. The value is the event as returned by
+// the API; the headers repeat the fields consumers route on.
+func NewMessage(topic string, e *sesevent.Event) (Message, error) {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Topic: topic,
+		ID:    e.EventKey,
+		Key:   e.MessageID,
+		Value: value,
+		Headers: map[string]string{
+			"event-key":  e.EventKey,
+			"event-type": e.EventType,
+			"provider":   e.Provider,
+			"message-id": e.MessageID,
+		},
+	}, nil
+}
+
+// Topics names the topic, subject or stream an event is published to
+type Topics struct {
+	// Template may contain {event_type}, the snake_case event type such as
+	// delivery_delay, and {provider}
+	Template string
+	// Overrides maps event types to a fixed topic
+	Overrides map[string]string
+}
+
+// ParseTopics reads overrides written as "Bounce=ses.bounces,Complaint=ses.complaints"
+func ParseTopics(template, overrides string) (Topics, error) {
+	if template == "" {
+		template = DefaultTopicTemplate
+	}
+	t := Topics{Template: template, Overrides: make(map[string]string)}
+	for _, pair := range strings.Split(overrides, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		eventType, topic, ok := strings.Cut(pair, "=")
+		eventType, topic = strings.TrimSpace(eventType), strings.TrimSpace(topic)
+		if !ok || eventType == "" || topic == "" {
+			return Topics{}, fmt.Errorf("invalid topic override %q, expected EventType=topic", pair)
+		}
+		t.Overrides[strings.ToLower(eventType)] = topic
+	}
+	return t, nil
+}
+
+func (t Topics) For(e *sesevent.Event) string {
+	if topic, ok := t.Overrides[strings.ToLower(e.EventType)]; ok {
+		return topic
+	}
+	provider := e.Provider
+	if provider == "" {
+		provider = sesevent.ProviderSES
+	}
+	return strings.NewReplacer(
+		"{event_type}", snakeCase(e.EventType),
+		"{provider}", provider,
+	).Replace(t.Template)
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NewFromConfig creates a sink for every broker configured in the sinks
+// section of the app config. Connections are made lazily, so an unreachable
+// broker does not prevent startup; its events wait in the outbox.
+func NewFromConfig(cfg *config.Config, topics Topics) ([]Sink, error) {
+	var sinks []Sink
+	if cfg.Sinks.NATSURL != "" {
+		sink, err := NewNATSSink(cfg.Sinks.NATSURL, cfg.Sinks.NATSStream, streamSubjects(topics))
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Sinks.KafkaBrokers != "" {
+		var brokers []string
+		for _, broker := range strings.Split(cfg.Sinks.KafkaBrokers, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				brokers = append(brokers, broker)
+			}
+		}
+		sinks = append(sinks, NewKafkaSink(brokers))
+	}
+	if cfg.Sinks.RedisURL != "" {
+		sink, err := NewRedisSink(cfg.Sinks.RedisURL, int64(cfg.Sinks.RedisMaxLen))
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// Names returns the outbox name of every sink
+func Names(sinks []Sink) []string {
+	names := make([]string, 0, len(sinks))
+	for _, sink := range sinks {
+		names = append(names, sink.Name())
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package eventsink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink produces to one topic per event type. Messages are keyed by the
+// SES message ID, so the events of one email stay in order on one partition.
+type KafkaSink struct {
+	brokers []string
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+func NewKafkaSink(brokers []string) *KafkaSink {
+	return &KafkaSink{brokers: brokers, writers: make(map[string]*kafka.Writer)}
+}
+
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, messages []Message) error {
+	// Group by topic without reordering messages within a topic
+	var topics []string
+	byTopic := make(map[string][]kafka.Message)
+	for _, m := range messages {
+		if _, ok := byTopic[m.Topic]; !ok {
+			topics = append(topics, m.Topic)
+		}
+		headers := make([]kafka.Header, 0, len(m.Headers))
+		for key, value := range m.Headers {
+			headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+		}
+		byTopic[m.Topic] = append(byTopic[m.Topic], kafka.Message{
+			Key:     []byte(m.Key),
+			Value:   m.Value,
+			Headers: headers,
+		})
+	}
+
+	for _, topic := range topics {
+		if err := s.writer(topic).WriteMessages(ctx, byTopic[topic]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *KafkaSink) writer(topic string) *kafka.Writer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.writers[topic]
+	if !ok {
+		w = kafka.NewWriter(kafka.WriterConfig{
+			Brokers:  s.brokers,
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+			// Wait for every in-sync replica before the outbox entry is removed
+			RequiredAcks: -1,
+			// Publish is called with whole batches, so there is nothing to wait for
+			BatchTimeout: 10 * time.Millisecond,
+		})
+		s.writers[topic] = w
+	}
+	return w
+}
+
+func (s *KafkaSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for topic, w := range s.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.writers, topic)
+	}
+	return firstErr
+}
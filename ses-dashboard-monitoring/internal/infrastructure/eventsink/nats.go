@@ -0,0 +1,150 @@
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DefaultNATSStream is the JetStream stream created for the event subjects
+const DefaultNATSStream = "SES_EVENTS"
+
+// NATSSink publishes to JetStream, so every message is persisted and
+// acknowledged by the server. The event key is sent as Nats-Msg-Id, which
+// lets the stream drop duplicates within its deduplication window.
+type NATSSink struct {
+	conn     *nats.Conn
+	js       jetstream.JetStream
+	stream   string
+	subjects []string
+
+	// The stream is looked up on first publish, when the server is reachable
+	streamMu    sync.Mutex
+	streamReady bool
+}
+
+// NewNATSSink connects to url. The stream is created with subjects if it does
+// not exist; an existing stream is used as configured.
+func NewNATSSink(url, stream string, subjects []string) (*NATSSink, error) {
+	if stream == "" {
+		stream = DefaultNATSStream
+	}
+	conn, err := nats.Connect(url,
+		nats.Name("ses-monitoring"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &NATSSink{conn: conn, js: js, stream: stream, subjects: subjects}, nil
+}
+
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+func (s *NATSSink) Publish(ctx context.Context, messages []Message) error {
+	if err := s.ensureStream(ctx); err != nil {
+		return err
+	}
+
+	futures := make([]jetstream.PubAckFuture, 0, len(messages))
+	for _, m := range messages {
+		msg := nats.NewMsg(m.Topic)
+		msg.Data = m.Value
+		for key, value := range m.Headers {
+			msg.Header.Set(key, value)
+		}
+		msg.Header.Set(jetstream.MsgIDHeader, m.ID)
+
+		future, err := s.js.PublishMsgAsync(msg)
+		if err != nil {
+			return err
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *NATSSink) ensureStream(ctx context.Context) error {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	if s.streamReady {
+		return nil
+	}
+
+	_, err := s.js.Stream(ctx, s.stream)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = s.js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     s.stream,
+			Subjects: s.subjects,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("stream %s: %w", s.stream, err)
+	}
+	s.streamReady = true
+	return nil
+}
+
+func (s *NATSSink) Close() error {
+	if err := s.conn.Drain(); err != nil {
+		s.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// streamSubjects are the NATS subjects the topics can produce: the template
+// with its placeholders as wildcards, plus the overrides it does not cover,
+// since a stream rejects overlapping subjects
+func streamSubjects(topics Topics) []string {
+	wildcard := strings.NewReplacer("{event_type}", "*", "{provider}", "*").Replace(topics.Template)
+	var overrides []string
+	seen := map[string]bool{wildcard: true}
+	for _, topic := range topics.Overrides {
+		if !seen[topic] && !subjectMatches(wildcard, topic) {
+			overrides = append(overrides, topic)
+		}
+		seen[topic] = true
+	}
+	sort.Strings(overrides)
+	return append([]string{wildcard}, overrides...)
+}
+
+// subjectMatches reports whether subject matches a pattern with * and > wildcards
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,56 @@
+package eventsink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisMaxLen caps each stream at roughly this many entries
+const DefaultRedisMaxLen = 1000000
+
+// RedisSink appends to one Redis stream per topic. Each entry carries the
+// event JSON in the "event" field next to the message headers.
+type RedisSink struct {
+	client *redis.Client
+	maxLen int64
+}
+
+func NewRedisSink(url string, maxLen int64) (*RedisSink, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse REDIS_URL: %w", err)
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultRedisMaxLen
+	}
+	return &RedisSink{client: redis.NewClient(opts), maxLen: maxLen}, nil
+}
+
+func (s *RedisSink) Name() string {
+	return "redis"
+}
+
+func (s *RedisSink) Publish(ctx context.Context, messages []Message) error {
+	pipe := s.client.Pipeline()
+	for _, m := range messages {
+		values := make([]interface{}, 0, 2*len(m.Headers)+2)
+		for key, value := range m.Headers {
+			values = append(values, key, value)
+		}
+		values = append(values, "event", m.Value)
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: m.Topic,
+			MaxLen: s.maxLen,
+			Approx: true,
+			Values: values,
+		})
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisSink) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,252 @@
+//go:build integration
+
+package eventsink
+
+// Integration tests against real brokers. Start them with
+//
+//	docker compose --profile brokers up -d nats kafka redis
+//
+// and run
+//
+//	NATS_URL=nats://localhost:4222 KAFKA_BROKERS=localhost:9092 REDIS_URL=redis://localhost:6379 \
+//		go test -tags integration ./internal/infrastructure/eventsink/
+//
+// A broker whose address is not set is skipped.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+)
+
+const integrationTimeout = 30 * time.Second
+
+func brokerAddress(t *testing.T, env string) string {
+	t.Helper()
+	address := os.Getenv(env)
+	if address == "" {
+		t.Skipf("%s is not set", env)
+	}
+	return address
+}
+
+// testMessages are two events of one email on a topic unique to the test run
+func testMessages(topic string) []Message {
+	messages := make([]Message, 2)
+	for i := range messages {
+		key := fmt.Sprintf("%s-event-%d", topic, i)
+		messages[i] = Message{
+			Topic: topic,
+			ID:    key,
+			Key:   "message-1",
+			Value: []byte(fmt.Sprintf(`{"event_key":%q}`, key)),
+			Headers: map[string]string{
+				"event-key":  key,
+				"event-type": "Delivery",
+				"provider":   "ses",
+				"message-id": "message-1",
+			},
+		}
+	}
+	return messages
+}
+
+// publish retries like the outbox relay does, since a broker may still be
+// creating the stream or topic on the first attempt
+func publish(t *testing.T, sink Sink, messages []Message) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+	for {
+		err := sink.Publish(ctx, messages)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("%s: Publish() = %v", sink.Name(), err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// received is a message as read back from a broker
+type received struct {
+	value   string
+	headers map[string]string
+}
+
+func checkReceived(t *testing.T, got []received, want []Message) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("received %d messages, want %d", len(got), len(want))
+	}
+	for i, m := range want {
+		if got[i].value != string(m.Value) {
+			t.Errorf("message %d value = %s, want %s", i, got[i].value, m.Value)
+		}
+		for key, value := range m.Headers {
+			if got[i].headers[key] != value {
+				t.Errorf("message %d header %s = %q, want %q", i, key, got[i].headers[key], value)
+			}
+		}
+	}
+}
+
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+}
+
+func TestNATSSink(t *testing.T) {
+	url := brokerAddress(t, "NATS_URL")
+	stream := uniqueName("SINKTEST_")
+	prefix := strings.ToLower(stream)
+	topic := prefix + ".delivery"
+
+	sink, err := NewNATSSink(url, stream, []string{prefix + ".*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	conn, err := nats.Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer js.DeleteStream(context.Background(), stream)
+
+	messages := testMessages(topic)
+	publish(t, sink, messages)
+	// A redelivered batch is dropped by the stream's duplicate window
+	publish(t, sink, messages)
+
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+	info, err := js.Stream(ctx, stream)
+	if err != nil {
+		t.Fatalf("sink did not create stream %s: %v", stream, err)
+	}
+	if msgs := info.CachedInfo().State.Msgs; msgs != uint64(len(messages)) {
+		t.Errorf("stream holds %d messages after a redelivery, want %d", msgs, len(messages))
+	}
+
+	consumer, err := js.OrderedConsumer(ctx, stream, jetstream.OrderedConsumerConfig{FilterSubjects: []string{topic}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := consumer.Fetch(len(messages), jetstream.FetchMaxWait(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []received
+	for msg := range batch.Messages() {
+		headers := map[string]string{}
+		for key := range msg.Headers() {
+			headers[key] = msg.Headers().Get(key)
+		}
+		got = append(got, received{value: string(msg.Data()), headers: headers})
+	}
+	checkReceived(t, got, messages)
+	for i, r := range got {
+		if r.headers[jetstream.MsgIDHeader] != messages[i].ID {
+			t.Errorf("message %d %s = %q, want the event key", i, jetstream.MsgIDHeader, r.headers[jetstream.MsgIDHeader])
+		}
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	var brokers []string
+	for _, broker := range strings.Split(brokerAddress(t, "KAFKA_BROKERS"), ",") {
+		brokers = append(brokers, strings.TrimSpace(broker))
+	}
+	topic := uniqueName("sinktest.delivery.")
+
+	sink := NewKafkaSink(brokers)
+	defer sink.Close()
+
+	messages := testMessages(topic)
+	publish(t, sink, messages)
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     topic,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+	var got []received
+	partitions := map[int]bool{}
+	for len(got) < len(messages) {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			t.Fatalf("read %d of %d messages: %v", len(got), len(messages), err)
+		}
+		headers := map[string]string{}
+		for _, header := range msg.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		if string(msg.Key) != "message-1" {
+			t.Errorf("message key = %q, want the SES message ID", msg.Key)
+		}
+		partitions[msg.Partition] = true
+		got = append(got, received{value: string(msg.Value), headers: headers})
+	}
+	checkReceived(t, got, messages)
+	// Keyed by message ID, so the events of one email stay in order
+	if len(partitions) != 1 {
+		t.Errorf("events of one email spread over %d partitions", len(partitions))
+	}
+}
+
+func TestRedisSink(t *testing.T) {
+	url := brokerAddress(t, "REDIS_URL")
+	stream := uniqueName("sinktest.delivery.")
+
+	sink, err := NewRedisSink(url, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+	defer client.Del(context.Background(), stream)
+
+	messages := testMessages(stream)
+	publish(t, sink, messages)
+
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+	entries, err := client.XRange(ctx, stream, "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []received
+	for _, entry := range entries {
+		headers := map[string]string{}
+		for key, value := range entry.Values {
+			headers[key] = fmt.Sprint(value)
+		}
+		got = append(got, received{value: headers["event"], headers: headers})
+	}
+	checkReceived(t, got, messages)
+}
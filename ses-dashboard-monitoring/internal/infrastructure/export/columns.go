@@ -0,0 +1,106 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// Column is an exported event field. Value returns a string, an int64 or a
+// *time.Time, which is nil when the event has no value.
+type Column struct {
+	Name  string
+	Value func(e *sesevent.Event) interface{}
+}
+
+func stringColumn(name string, value func(e *sesevent.Event) string) Column {
+	return Column{Name: name, Value: func(e *sesevent.Event) interface{} { return value(e) }}
+}
+
+func timeColumn(name string, value func(e *sesevent.Event) *time.Time) Column {
+	return Column{Name: name, Value: func(e *sesevent.Event) interface{} { return value(e) }}
+}
+
+// Columns lists every exportable column in the default export order
+var Columns = []Column{
+	{Name: "id", Value: func(e *sesevent.Event) interface{} { return e.ID }},
+	timeColumn("event_timestamp", func(e *sesevent.Event) *time.Time { return &e.EventTimestamp }),
+	timeColumn("occurred_at", func(e *sesevent.Event) *time.Time { return e.OccurredAt }),
+	stringColumn("event_type", func(e *sesevent.Event) string { return e.EventType }),
+	stringColumn("message_id", func(e *sesevent.Event) string { return e.MessageID }),
+	stringColumn("email", func(e *sesevent.Event) string { return e.Email }),
+	stringColumn("source", func(e *sesevent.Event) string { return e.Source }),
+	stringColumn("subject", func(e *sesevent.Event) string { return e.Subject }),
+	stringColumn("status", func(e *sesevent.Event) string { return e.Status }),
+	stringColumn("reason", func(e *sesevent.Event) string { return e.Reason }),
+	stringColumn("bounce_type", func(e *sesevent.Event) string { return e.BounceType }),
+	stringColumn("bounce_sub_type", func(e *sesevent.Event) string { return e.BounceSubType }),
+	stringColumn("diagnostic_code", func(e *sesevent.Event) string { return e.DiagnosticCode }),
+	stringColumn("smtp_response", func(e *sesevent.Event) string { return e.SmtpResponse }),
+	stringColumn("reporting_mta", func(e *sesevent.Event) string { return e.ReportingMTA }),
+	stringColumn("remote_mta_ip", func(e *sesevent.Event) string { return e.RemoteMtaIp }),
+	{Name: "processing_time_millis", Value: func(e *sesevent.Event) interface{} { return int64(e.ProcessingTimeMillis) }},
+	stringColumn("complaint_feedback_type", func(e *sesevent.Event) string { return e.ComplaintFeedbackType }),
+	stringColumn("complaint_sub_type", func(e *sesevent.Event) string { return e.ComplaintSubType }),
+	stringColumn("complaint_user_agent", func(e *sesevent.Event) string { return e.ComplaintUserAgent }),
+	timeColumn("complaint_arrival_date", func(e *sesevent.Event) *time.Time { return e.ComplaintArrivalDate }),
+	stringColumn("feedback_id", func(e *sesevent.Event) string { return e.FeedbackID }),
+	stringColumn("link", func(e *sesevent.Event) string { return e.Link }),
+	stringColumn("link_tags", func(e *sesevent.Event) string { return e.LinkTags }),
+	stringColumn("ip_address", func(e *sesevent.Event) string { return e.IPAddress }),
+	stringColumn("user_agent", func(e *sesevent.Event) string { return e.UserAgent }),
+	stringColumn("delay_type", func(e *sesevent.Event) string { return e.DelayType }),
+	timeColumn("delay_expiration_time", func(e *sesevent.Event) *time.Time { return e.DelayExpirationTime }),
+	stringColumn("template_name", func(e *sesevent.Event) string { return e.TemplateName }),
+	stringColumn("contact_list", func(e *sesevent.Event) string { return e.ContactList }),
+	stringColumn("topic_preferences", func(e *sesevent.Event) string { return e.TopicPreferences }),
+	stringColumn("configuration_set", func(e *sesevent.Event) string { return e.ConfigurationSet }),
+	stringColumn("from_domain", func(e *sesevent.Event) string { return e.FromDomain }),
+	stringColumn("tags", func(e *sesevent.Event) string { return e.Tags }),
+	stringColumn("recipients", func(e *sesevent.Event) string { return e.Recipients }),
+	stringColumn("provider", func(e *sesevent.Event) string { return e.Provider }),
+	stringColumn("publishing_mechanism", func(e *sesevent.Event) string { return e.PublishingMechanism }),
+	stringColumn("sns_message_id", func(e *sesevent.Event) string { return e.SNSMessageID }),
+	timeColumn("created_at", func(e *sesevent.Event) *time.Time { return &e.CreatedAt }),
+}
+
+// DefaultColumns are exported when no columns are chosen
+var DefaultColumns = []string{
+	"id", "event_timestamp", "occurred_at", "event_type", "message_id", "email", "source", "subject",
+	"status", "bounce_type", "bounce_sub_type", "diagnostic_code", "reporting_mta",
+	"complaint_feedback_type", "provider",
+}
+
+// ParseColumns resolves a list of column names, in the given order. An
+// empty list selects DefaultColumns.
+func ParseColumns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	byName := make(map[string]Column, len(Columns))
+	for _, column := range Columns {
+		byName[column.Name] = column
+	}
+
+	columns := make([]Column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		seen[name] = true
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return columns, nil
+}
@@ -0,0 +1,176 @@
+// Package export writes events as CSV, NDJSON or XLSX one row at a time, so
+// exports of any size stream with constant memory.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// Export formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Formats lists every export format
+var Formats = []string{FormatCSV, FormatNDJSON, FormatXLSX}
+
+// IsValidFormat reports whether format is one of Formats
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Writer writes events in an export format. Close finishes the file but
+// does not close the underlying writer.
+type Writer interface {
+	Write(e *sesevent.Event) error
+	Close() error
+}
+
+// NewWriter returns a Writer of format that writes columns to w, with times
+// converted to loc
+func NewWriter(format string, w io.Writer, columns []Column, loc *time.Location) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns, loc)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, columns, loc), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns, loc)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// formatValue renders a column value as text; times use RFC 3339 in loc
+func formatValue(value interface{}, loc *time.Location) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return ""
+		}
+		return v.In(loc).Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+	loc     *time.Location
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column, loc *time.Location) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, loc: loc, record: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.record[i] = column.Name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(e *sesevent.Event) error {
+	for i, column := range cw.columns {
+		value := column.Value(e)
+		text := formatValue(value, cw.loc)
+		if _, ok := value.(string); ok {
+			text = escapeFormula(text)
+		}
+		cw.record[i] = text
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// escapeFormula keeps spreadsheets from evaluating text such as a subject
+// starting with "=" as a formula
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	loc     *time.Location
+}
+
+func newNDJSONWriter(w io.Writer, columns []Column, loc *time.Location) *ndjsonWriter {
+	return &ndjsonWriter{w: bufio.NewWriter(w), columns: columns, loc: loc}
+}
+
+// Write writes the event as one JSON object with the columns in order; empty
+// times are null
+func (nw *ndjsonWriter) Write(e *sesevent.Event) error {
+	nw.w.WriteByte('{')
+	for i, column := range nw.columns {
+		if i > 0 {
+			nw.w.WriteByte(',')
+		}
+		name, _ := json.Marshal(column.Name)
+		nw.w.Write(name)
+		nw.w.WriteByte(':')
+
+		var encoded []byte
+		switch v := column.Value(e).(type) {
+		case int64:
+			encoded = []byte(strconv.FormatInt(v, 10))
+		case *time.Time:
+			if v == nil || v.IsZero() {
+				encoded = []byte("null")
+				break
+			}
+			encoded, _ = json.Marshal(formatValue(v, nw.loc))
+		default:
+			encoded, _ = json.Marshal(formatValue(v, nw.loc))
+		}
+		nw.w.Write(encoded)
+	}
+	nw.w.WriteByte('}')
+	return nw.w.WriteByte('\n')
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+const (
+	// xlsxMaxRows is the row limit of a worksheet; longer exports continue on
+	// another sheet
+	xlsxMaxRows = 1048576
+	// xlsxMaxCellLength is the number of characters a cell holds
+	xlsxMaxCellLength = 32767
+	// xlsxDateStyle is the cellXfs index of the date format in xlsxStyles
+	xlsxDateStyle = 1
+)
+
+// xlsxEpoch is day zero of spreadsheet date serial numbers
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxWriter writes a minimal Office Open XML workbook. Sheets are streamed
+// into the zip with inline strings, so no shared string table has to be
+// kept in memory; the parts listing the sheets are written last.
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	columns []Column
+	loc     *time.Location
+	sheets  int
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer, columns []Column, loc *time.Location) (*xlsxWriter, error) {
+	xw := &xlsxWriter{zip: zip.NewWriter(w), columns: columns, loc: loc}
+	if err := xw.startSheet(); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) startSheet() error {
+	if xw.sheet != nil {
+		if err := xw.endSheet(); err != nil {
+			return err
+		}
+	}
+
+	xw.sheets++
+	part, err := xw.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", xw.sheets))
+	if err != nil {
+		return err
+	}
+	xw.sheet = bufio.NewWriter(part)
+	xw.rows = 0
+
+	xw.sheet.WriteString(xml.Header)
+	xw.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+	xw.sheet.WriteString("<row>")
+	for _, column := range xw.columns {
+		xw.writeString(column.Name)
+	}
+	xw.sheet.WriteString("</row>")
+	xw.rows++
+	return nil
+}
+
+func (xw *xlsxWriter) endSheet() error {
+	xw.sheet.WriteString("</sheetData></worksheet>")
+	return xw.sheet.Flush()
+}
+
+func (xw *xlsxWriter) Write(e *sesevent.Event) error {
+	if xw.rows == xlsxMaxRows {
+		if err := xw.startSheet(); err != nil {
+			return err
+		}
+	}
+
+	xw.sheet.WriteString("<row>")
+	for _, column := range xw.columns {
+		switch v := column.Value(e).(type) {
+		case int64:
+			xw.sheet.WriteString(`<c><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case *time.Time:
+			if v == nil || v.IsZero() {
+				xw.sheet.WriteString("<c/>")
+				continue
+			}
+			xw.sheet.WriteString(`<c s="` + strconv.Itoa(xlsxDateStyle) + `"><v>` + xlsxSerial(v.In(xw.loc)) + `</v></c>`)
+		default:
+			xw.writeString(formatValue(v, xw.loc))
+		}
+	}
+	xw.rows++
+	_, err := xw.sheet.WriteString("</row>")
+	return err
+}
+
+func (xw *xlsxWriter) writeString(text string) {
+	if text == "" {
+		xw.sheet.WriteString("<c/>")
+		return
+	}
+	if len(text) > xlsxMaxCellLength {
+		// Cut on a rune boundary at most xlsxMaxCellLength characters in
+		runes := []rune(text)
+		if len(runes) > xlsxMaxCellLength {
+			text = string(runes[:xlsxMaxCellLength])
+		}
+	}
+	xw.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	// EscapeText also replaces characters XML cannot hold
+	xml.EscapeText(xw.sheet, []byte(text))
+	xw.sheet.WriteString(`</t></is></c>`)
+}
+
+// xlsxSerial returns the spreadsheet date serial of the wall clock time of t
+func xlsxSerial(t time.Time) string {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return strconv.FormatFloat(wall.Sub(xlsxEpoch).Hours()/24, 'f', -1, 64)
+}
+
+func (xw *xlsxWriter) Close() error {
+	if err := xw.endSheet(); err != nil {
+		return err
+	}
+
+	var sheets, sheetRels, sheetTypes strings.Builder
+	for i := 1; i <= xw.sheets; i++ {
+		name := "Events"
+		if i > 1 {
+			name = fmt.Sprintf("Events %d", i)
+		}
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, name, i, i)
+		fmt.Fprintf(&sheetRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+		fmt.Fprintf(&sheetTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			sheetTypes.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			sheetRels.String() +
+			fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, xw.sheets+1) +
+			`</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		w, err := xw.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, xml.Header+part.content); err != nil {
+			return err
+		}
+	}
+	return xw.zip.Close()
+}
+
+// xlsxStyles defines the default cell style and, at xlsxDateStyle, a date and time format
+const xlsxStyles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"ses-monitoring/internal/domain/deadletter"
+)
+
+const deadLetterColumns = `id, source, message_id, topic_arn, payload, stage, error, attempts, status,
+		created_at, updated_at, last_attempt_at`
+
+type deadLetterRepo struct {
+	db *sql.DB
+}
+
+func NewDeadLetterRepository(db *sql.DB) deadletter.Repository {
+	return &deadLetterRepo{db: db}
+}
+
+func (r *deadLetterRepo) Create(ctx context.Context, e *deadletter.Entry) error {
+	query := `
+		INSERT INTO dead_letters (source, message_id, topic_arn, payload, stage, error, attempts, status)
+		VALUES ($1, $2, $3, $4, $5, $6, 1, $7)
+		RETURNING id, attempts, created_at, updated_at, last_attempt_at
+	`
+	if e.Status == "" {
+		e.Status = deadletter.StatusPending
+	}
+	return r.db.QueryRowContext(ctx, query, e.Source, e.MessageID, e.TopicArn, e.Payload, string(e.Stage), e.Error, string(e.Status)).
+		Scan(&e.ID, &e.Attempts, &e.CreatedAt, &e.UpdatedAt, &e.LastAttemptAt)
+}
+
+func (r *deadLetterRepo) GetByID(ctx context.Context, id int64) (*deadletter.Entry, error) {
+	query := `SELECT ` + deadLetterColumns + ` FROM dead_letters WHERE id = $1`
+	e, err := scanDeadLetter(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, deadletter.ErrNotFound
+	}
+	return e, err
+}
+
+// List returns entries newest first; an empty status lists every entry
+func (r *deadLetterRepo) List(ctx context.Context, status deadletter.Status, limit, offset int) ([]*deadletter.Entry, error) {
+	query := `
+		SELECT ` + deadLetterColumns + `
+		FROM dead_letters
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, string(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*deadletter.Entry
+	for rows.Next() {
+		e, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *deadLetterRepo) Count(ctx context.Context, status deadletter.Status) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM dead_letters WHERE ($1 = '' OR status = $1)`, string(status)).Scan(&count)
+	return count, err
+}
+
+func (r *deadLetterRepo) UpdatePayload(ctx context.Context, id int64, payload string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE dead_letters SET payload = $2, updated_at = NOW() WHERE id = $1`, id, payload)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// RecordAttempt counts a replay attempt and stores its outcome
+func (r *deadLetterRepo) RecordAttempt(ctx context.Context, id int64, stage deadletter.Stage, status deadletter.Status, lastError string) error {
+	query := `
+		UPDATE dead_letters
+		SET attempts = attempts + 1, stage = $2, status = $3, error = $4, last_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, id, string(stage), string(status), lastError)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+func (r *deadLetterRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM dead_letters WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDeadLetter(row rowScanner) (*deadletter.Entry, error) {
+	e := &deadletter.Entry{}
+	var stage, status string
+	err := row.Scan(&e.ID, &e.Source, &e.MessageID, &e.TopicArn, &e.Payload, &stage, &e.Error, &e.Attempts, &status,
+		&e.CreatedAt, &e.UpdatedAt, &e.LastAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	e.Stage = deadletter.Stage(stage)
+	e.Status = deadletter.Status(status)
+	return e, nil
+}
+
+func requireRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return deadletter.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ses-monitoring/internal/domain/exportjob"
+)
+
+const exportJobColumns = `id, user_id, status, format, columns, params, filter, timezone,
+		rows_written, estimated_rows, attempt, artifact_key, artifact_size, file_name, error,
+		created_at, started_at, finished_at, expires_at`
+
+type exportJobRepo struct {
+	db *sql.DB
+}
+
+func NewExportJobRepository(db *sql.DB) exportjob.Repository {
+	return &exportJobRepo{db: db}
+}
+
+func (r *exportJobRepo) Create(ctx context.Context, job *exportjob.Job) error {
+	columns, err := json.Marshal(job.Columns)
+	if err != nil {
+		return err
+	}
+	filter, err := json.Marshal(job.Filter)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO export_jobs (user_id, status, format, columns, params, filter, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	job.Status = exportjob.StatusQueued
+	return r.db.QueryRowContext(ctx, query, job.UserID, job.Status, job.Format, columns, job.Params, filter, job.Timezone).
+		Scan(&job.ID, &job.CreatedAt)
+}
+
+func (r *exportJobRepo) Get(ctx context.Context, id int64) (*exportjob.Job, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id = $1`
+	job, err := scanExportJob(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, exportjob.ErrNotFound
+	}
+	return job, err
+}
+
+func (r *exportJobRepo) List(ctx context.Context, userID int64, limit int) ([]*exportjob.Job, error) {
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE ($1 = 0 OR user_id = $1)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	return r.queryJobs(ctx, query, userID, limit)
+}
+
+func (r *exportJobRepo) Claim(ctx context.Context, lease time.Duration) (*exportjob.Job, error) {
+	query := `
+		UPDATE export_jobs
+		SET status = 'running',
+			started_at = COALESCE(started_at, NOW()),
+			lease_until = NOW() + make_interval(secs => $1),
+			attempt = attempt + 1,
+			rows_written = 0
+		WHERE id = (
+			SELECT id
+			FROM export_jobs
+			WHERE status = 'queued' OR (status = 'running' AND lease_until < NOW())
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportJobColumns
+	job, err := scanExportJob(r.db.QueryRowContext(ctx, query, lease.Seconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return job, err
+}
+
+func (r *exportJobRepo) UpdateProgress(ctx context.Context, id int64, attempt int, rowsWritten int64, lease time.Duration) (bool, error) {
+	query := `
+		UPDATE export_jobs
+		SET rows_written = $3, lease_until = NOW() + make_interval(secs => $4)
+		WHERE id = $1 AND attempt = $2 AND status = 'running'
+	`
+	result, err := r.db.ExecContext(ctx, query, id, attempt, rowsWritten, lease.Seconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *exportJobRepo) SetEstimate(ctx context.Context, id int64, attempt int, estimatedRows int64) error {
+	query := `UPDATE export_jobs SET estimated_rows = $3 WHERE id = $1 AND attempt = $2 AND status = 'running'`
+	_, err := r.db.ExecContext(ctx, query, id, attempt, estimatedRows)
+	return err
+}
+
+func (r *exportJobRepo) Complete(ctx context.Context, job *exportjob.Job) (bool, error) {
+	query := `
+		UPDATE export_jobs
+		SET status = 'succeeded', rows_written = $3, artifact_key = $4, artifact_size = $5,
+			file_name = $6, expires_at = $7, finished_at = NOW(), lease_until = NULL
+		WHERE id = $1 AND attempt = $2 AND status = 'running'
+		RETURNING finished_at
+	`
+	var finishedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, job.ID, job.Attempt, job.RowsWritten, job.ArtifactKey, job.ArtifactSize,
+		job.FileName, job.ExpiresAt).Scan(&finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	job.Status = exportjob.StatusSucceeded
+	job.FinishedAt = &finishedAt
+	return true, nil
+}
+
+func (r *exportJobRepo) Fail(ctx context.Context, id int64, attempt int, reason string) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'failed', error = $3, finished_at = NOW(), lease_until = NULL
+		WHERE id = $1 AND attempt = $2 AND status = 'running'
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempt, reason)
+	return err
+}
+
+func (r *exportJobRepo) Cancel(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE export_jobs
+		SET status = 'canceled', finished_at = NOW(), lease_until = NULL
+		WHERE id = $1 AND status IN ('queued', 'running')
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *exportJobRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return exportjob.ErrNotFound
+	}
+	return nil
+}
+
+func (r *exportJobRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*exportjob.Job, error) {
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE status = 'succeeded' AND expires_at < $1
+		ORDER BY expires_at
+		LIMIT $2
+	`
+	return r.queryJobs(ctx, query, now, limit)
+}
+
+func (r *exportJobRepo) MarkExpired(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE export_jobs SET status = 'expired' WHERE id = $1 AND status = 'succeeded'`, id)
+	return err
+}
+
+func (r *exportJobRepo) queryJobs(ctx context.Context, query string, args ...interface{}) ([]*exportjob.Job, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*exportjob.Job
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanExportJob(row rowScanner) (*exportjob.Job, error) {
+	job := &exportjob.Job{}
+	var status string
+	var columns, filter []byte
+	var createdAt, startedAt, finishedAt, expiresAt sql.NullTime
+	err := row.Scan(&job.ID, &job.UserID, &status, &job.Format, &columns, &job.Params, &filter, &job.Timezone,
+		&job.RowsWritten, &job.EstimatedRows, &job.Attempt, &job.ArtifactKey, &job.ArtifactSize, &job.FileName, &job.Error,
+		&createdAt, &startedAt, &finishedAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = exportjob.Status(status)
+	if err := json.Unmarshal(columns, &job.Columns); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &job.Filter); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		job.CreatedAt = createdAt.Time
+	}
+	job.StartedAt = nullTimePtr(startedAt)
+	job.FinishedAt = nullTimePtr(finishedAt)
+	job.ExpiresAt = nullTimePtr(expiresAt)
+	return job, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ses-monitoring/internal/domain/outbox"
+
+	"github.com/lib/pq"
+)
+
+type outboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) outbox.Repository {
+	return &outboxRepo{db: db}
+}
+
+func (r *outboxRepo) Claim(ctx context.Context, sink string, limit int, lease time.Duration) ([]*outbox.Entry, error) {
+	// Entries are returned in insertion order so events reach the broker in
+	// the order they were stored
+	query := `
+		WITH claimed AS (
+			UPDATE event_outbox
+			SET next_attempt_at = NOW() + make_interval(secs => $3)
+			WHERE id IN (
+				SELECT id
+				FROM event_outbox
+				WHERE sink = $1 AND next_attempt_at <= NOW()
+				ORDER BY id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, event_id, sink, attempts, last_error, created_at
+		)
+		SELECT id, event_id, sink, attempts, last_error, created_at FROM claimed ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, sink, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*outbox.Entry
+	for rows.Next() {
+		e := &outbox.Entry{}
+		var createdAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.EventID, &e.Sink, &e.Attempts, &e.LastError, &createdAt); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			e.CreatedAt = createdAt.Time
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *outboxRepo) Delete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+func (r *outboxRepo) RecordFailure(ctx context.Context, ids []int64, lastError string, retryIn time.Duration) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `
+		UPDATE event_outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+		WHERE id = ANY($1)
+	`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids), lastError, retryIn.Seconds())
+	return err
+}
+
+func (r *outboxRepo) Stats(ctx context.Context) ([]*outbox.SinkStats, error) {
+	query := `
+		SELECT sink, COUNT(*), COUNT(*) FILTER (WHERE attempts > 0), MIN(created_at)
+		FROM event_outbox
+		GROUP BY sink
+		ORDER BY sink
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*outbox.SinkStats
+	for rows.Next() {
+		s := &outbox.SinkStats{}
+		var oldest sql.NullTime
+		if err := rows.Scan(&s.Sink, &s.Pending, &s.Failing, &oldest); err != nil {
+			return nil, err
+		}
+		if oldest.Valid {
+			s.Oldest = &oldest.Time
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
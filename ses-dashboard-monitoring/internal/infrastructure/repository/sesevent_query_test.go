@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+func TestCompileQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []interface{}
+	}{
+		{
+			name:  "text searches recipient, subject and sender",
+			query: "invoice",
+			sql:   "(email ILIKE $1 OR subject ILIKE $1 OR source ILIKE $1)",
+			args:  []interface{}{"%invoice%"},
+		},
+		{
+			name:  "LIKE wildcards are escaped",
+			query: `"100%_off\\"`,
+			sql:   "(email ILIKE $1 OR subject ILIKE $1 OR source ILIKE $1)",
+			args:  []interface{}{`%100\%\_off\\%`},
+		},
+		{
+			name:  "AND and OR are parenthesised",
+			query: "type:Bounce (domain:gmail.com OR domain:@yahoo.com)",
+			sql:   "((event_type = $1) AND ((split_part(lower(email), '@', 2) = lower($2)) OR (split_part(lower(email), '@', 2) = lower($3))))",
+			args:  []interface{}{"Bounce", "gmail.com", "yahoo.com"},
+		},
+		{
+			name:  "NOT keeps rows with NULL columns",
+			query: "-bounce:Transient",
+			sql:   "(NOT COALESCE((bounce_type = $1), false))",
+			args:  []interface{}{"Transient"},
+		},
+		{
+			name:  "sender domain, local part and address",
+			query: "from:@acme.com OR from:noreply@ OR from:Billing@Acme.com",
+			sql:   "((split_part(lower(source), '@', 2) = lower($1)) OR (split_part(lower(source), '@', 1) = lower($2)) OR (lower(source) = lower($3)))",
+			args:  []interface{}{"acme.com", "noreply", "Billing@Acme.com"},
+		},
+		{
+			name:  "recipient address",
+			query: "to:jane@example.com",
+			sql:   "(lower(email) = lower($1))",
+			args:  []interface{}{"jane@example.com"},
+		},
+		{
+			name:  "tag containment",
+			query: "tag:ses:configuration-set:marketing",
+			sql:   "(tags @> $1::jsonb)",
+			args:  []interface{}{`{"ses:configuration-set":["marketing"]}`},
+		},
+		{
+			name:  "feedback type selects complaints",
+			query: "feedback:abuse",
+			sql:   "(event_type = 'Complaint' AND complaint_feedback_type = $1)",
+			args:  []interface{}{"abuse"},
+		},
+		{
+			name:  "date range",
+			query: "after:2026-09-01 before:2026-10-01",
+			sql:   "((event_timestamp >= $1::date) AND (event_timestamp < $2::date))",
+			args:  []interface{}{"2026-09-01", "2026-10-01"},
+		},
+		{
+			name:  "case-insensitive matches",
+			query: "subtype:general status:success mta:mx subject:\"Reset\"",
+			sql:   "((lower(bounce_sub_type) = lower($1)) AND (upper(status) = upper($2)) AND (reporting_mta ILIKE $3) AND (subject ILIKE $4))",
+			args:  []interface{}{"general", "success", "%mx%", "%Reset%"},
+		},
+		{
+			name:  "exact matches",
+			query: "message:0100abc provider:ses config_set:default",
+			sql:   "((message_id = $1) AND (provider = $2) AND (configuration_set = $3))",
+			args:  []interface{}{"0100abc", "ses", "default"},
+		},
+		{
+			name:  "quotes and SQL never reach the statement",
+			query: `subject:"'; DROP TABLE ses_events; --"`,
+			sql:   "(subject ILIKE $1)",
+			args:  []interface{}{`%'; DROP TABLE ses\_events; --%`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := sesevent.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error: %v", tt.query, err)
+			}
+			var args []interface{}
+			sql := compileQuery(expr, func(value interface{}) string {
+				args = append(args, value)
+				return fmt.Sprintf("$%d", len(args))
+			})
+			if sql != tt.sql {
+				t.Errorf("SQL\n got: %s\nwant: %s", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args\n got: %#v\nwant: %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBuildEventFilterQuery(t *testing.T) {
+	expr, err := sesevent.ParseQuery("type:Bounce -domain:gmail.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args := buildEventFilter(sesevent.Filter{Provider: "ses", Query: expr})
+
+	wantWhere := " AND provider = $1 AND ((event_type = $2) AND (NOT COALESCE((split_part(lower(email), '@', 2) = lower($3)), false)))"
+	if where != wantWhere {
+		t.Errorf("where\n got: %s\nwant: %s", where, wantWhere)
+	}
+	wantArgs := []interface{}{"ses", "Bounce", "gmail.com"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args\n got: %#v\nwant: %#v", args, wantArgs)
+	}
+}
+
+func TestBuildEventFilterTags(t *testing.T) {
+	where, args := buildEventFilter(sesevent.Filter{Tags: map[string][]string{
+		"campaign":              {"spring", "summer"},
+		"ses:configuration-set": {"marketing"},
+	}})
+
+	wantWhere := " AND (tags @> $1::jsonb OR tags @> $2::jsonb) AND (tags @> $3::jsonb)"
+	if where != wantWhere {
+		t.Errorf("where\n got: %s\nwant: %s", where, wantWhere)
+	}
+	wantArgs := []interface{}{
+		`{"campaign":["spring"]}`,
+		`{"campaign":["summer"]}`,
+		`{"ses:configuration-set":["marketing"]}`,
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args\n got: %#v\nwant: %#v", args, wantArgs)
+	}
+}
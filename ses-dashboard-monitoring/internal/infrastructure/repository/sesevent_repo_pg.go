@@ -3,32 +3,238 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"ses-monitoring/internal/domain/sesevent"
+
+	"github.com/lib/pq"
 )
 
+// sesEventColumns is the column list every event query selects, in scanEvents order.
+// Rows stored before migration 0017 have no event key.
+const sesEventColumns = `message_id, email, subject, event_type, status, reason, source, recipients,
+			   event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
+			   processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags,
+			   complaint_feedback_type, complaint_sub_type, complaint_user_agent,
+			   complaint_arrival_date, feedback_id, link, link_tags, ip_address,
+			   user_agent, occurred_at, delay_type, delay_expiration_time, template_name,
+			   contact_list, topic_preferences, configuration_set, from_domain, sns_message_id,
+			   publishing_mechanism, provider, id, COALESCE(event_key, '') AS event_key`
+
+// eventCursorBatchSize is the number of rows ForEachEvent fetches at a time
+const eventCursorBatchSize = 1000
+
 type sesEventRepo struct {
 	db *sql.DB
+	// outboxSinks are the event sinks every newly stored event is queued for
+	outboxSinks []string
 }
 
 func NewSESEventRepository(db *sql.DB) sesevent.Repository {
 	return &sesEventRepo{db: db}
 }
 
-func (r *sesEventRepo) Save(ctx context.Context, e *sesevent.Event) error {
-	query := `
-		INSERT INTO ses_events (
-			message_id, email, subject, event_type, status, reason, source, recipients,
+// NewSESEventRepositoryWithOutbox returns a repository that queues every newly
+// stored event in the event outbox for each of the sinks, in the same
+// statement that stores the event
+func NewSESEventRepositoryWithOutbox(db *sql.DB, sinks []string) sesevent.Repository {
+	return &sesEventRepo{db: db, outboxSinks: sinks}
+}
+
+// sesEventInsertColumns is the column list written by Save and SaveBatch, in insertArgs order
+const sesEventInsertColumns = `message_id, email, subject, event_type, status, reason, source, recipients,
 			event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
-			processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-	`
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
+			processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags,
+			complaint_feedback_type, complaint_sub_type, complaint_user_agent,
+			complaint_arrival_date, feedback_id, link, link_tags, ip_address,
+			user_agent, occurred_at, delay_type, delay_expiration_time, template_name,
+			contact_list, topic_preferences, configuration_set, from_domain, sns_message_id,
+			publishing_mechanism, provider, event_key`
+
+const sesEventInsertColumnCount = 38
+
+// maxBatchRows keeps a multi-row INSERT under PostgreSQL's 65535 bind parameter limit
+const maxBatchRows = 65535 / sesEventInsertColumnCount
+
+func (r *sesEventRepo) Save(ctx context.Context, e *sesevent.Event) error {
+	query, args := r.insertQuery([]string{insertPlaceholders(1, sesEventInsertColumnCount)}, insertArgs(e), `ON CONFLICT (event_key) DO NOTHING`, true)
+
+	var key string
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&e.ID, &key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sesevent.ErrDuplicateEvent
+	}
+	return err
+}
+
+// SaveBatch writes events with multi-row INSERTs inside one transaction and
+// returns the events that were new, with their ID set. Duplicates are
+// skipped, not reported.
+func (r *sesEventRepo) SaveBatch(ctx context.Context, events []*sesevent.Event) ([]*sesevent.Event, error) {
+	ids, err := r.insertBatch(ctx, events, `ON CONFLICT (event_key) DO NOTHING`, true)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]*sesevent.Event, 0, len(ids))
+	for _, e := range events {
+		key := e.EventKey
+		if key == "" {
+			key = e.Fingerprint()
+		}
+		// A key repeated within the batch was inserted once
+		if id, ok := ids[key]; ok {
+			e.ID = id
+			stored = append(stored, e)
+			delete(ids, key)
+		}
+	}
+	return stored, nil
+}
+
+// UpsertBatch writes events like SaveBatch but overwrites stored rows with the
+// same event key, so re-parsed events can backfill columns added later. It
+// returns the number of rows inserted or updated.
+func (r *sesEventRepo) UpsertBatch(ctx context.Context, events []*sesevent.Event) (int, error) {
+	// A statement may update each row only once, so keep the last event per key
+	byKey := make(map[string]int, len(events))
+	unique := make([]*sesevent.Event, 0, len(events))
+	for _, e := range events {
+		if e.EventKey == "" {
+			e.EventKey = e.Fingerprint()
+		}
+		if i, ok := byKey[e.EventKey]; ok {
+			unique[i] = e
+			continue
+		}
+		byKey[e.EventKey] = len(unique)
+		unique = append(unique, e)
+	}
+
+	var set []string
+	for _, column := range strings.Split(sesEventInsertColumns, ",") {
+		column = strings.TrimSpace(column)
+		if column != "event_key" {
+			set = append(set, column+" = EXCLUDED."+column)
+		}
+	}
+	// Backfilled events were published when they were first stored
+	ids, err := r.insertBatch(ctx, unique, `ON CONFLICT (event_key) DO UPDATE SET `+strings.Join(set, ", "), false)
+	return len(ids), err
+}
+
+// insertBatch returns the ID of every row written, by event key
+func (r *sesEventRepo) insertBatch(ctx context.Context, events []*sesevent.Event, onConflict string, queue bool) (map[string]int64, error) {
+	if len(events) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids := make(map[string]int64, len(events))
+	for start := 0; start < len(events); start += maxBatchRows {
+		end := start + maxBatchRows
+		if end > len(events) {
+			end = len(events)
+		}
+		chunk := events[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*sesEventInsertColumnCount)
+		rows := make([]string, 0, len(chunk))
+		for i, e := range chunk {
+			rows = append(rows, insertPlaceholders(i*sesEventInsertColumnCount+1, sesEventInsertColumnCount))
+			args = append(args, insertArgs(e)...)
+		}
+
+		query, args := r.insertQuery(rows, args, onConflict, queue)
+		result, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for result.Next() {
+			var id int64
+			var key string
+			if err := result.Scan(&id, &key); err != nil {
+				result.Close()
+				return nil, err
+			}
+			ids[key] = id
+		}
+		result.Close()
+		if err := result.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// insertQuery renders the INSERT of rows returning id and event_key. With
+// queue set and outbox sinks configured, the inserted rows are queued in the
+// event outbox by the same statement, so an event is never stored without
+// being queued.
+func (r *sesEventRepo) insertQuery(rows []string, args []interface{}, onConflict string, queue bool) (string, []interface{}) {
+	insert := `INSERT INTO ses_events (` + sesEventInsertColumns + `) VALUES ` +
+		strings.Join(rows, ", ") + ` ` + onConflict + ` RETURNING id, event_key`
+	if !queue || len(r.outboxSinks) == 0 {
+		return insert, args
+	}
+
+	query := fmt.Sprintf(`
+		WITH inserted AS (%s),
+		queued AS (
+			INSERT INTO event_outbox (event_id, sink)
+			SELECT inserted.id, sink FROM inserted CROSS JOIN unnest($%d::text[]) AS sink
+		)
+		SELECT id, event_key FROM inserted`, insert, len(args)+1)
+	return query, append(args, pq.Array(r.outboxSinks))
+}
+
+// insertPlaceholders renders "($first, ..., $first+count-1)"
+func insertPlaceholders(first, count int) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "$%d", first+i)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func insertArgs(e *sesevent.Event) []interface{} {
+	tags := e.Tags
+	if tags == "" {
+		tags = "{}"
+	}
+	provider := e.Provider
+	if provider == "" {
+		provider = sesevent.ProviderSES
+	}
+	// Publishing mechanisms only exist for SES
+	publishingMechanism := e.PublishingMechanism
+	if publishingMechanism == "" && provider == sesevent.ProviderSES {
+		publishingMechanism = sesevent.PublishingMechanismEventPublishing
+	}
+	eventKey := e.EventKey
+	if eventKey == "" {
+		eventKey = e.Fingerprint()
+	}
+	return []interface{}{
 		e.MessageID,
 		e.Email,
 		e.Subject,
@@ -45,16 +251,34 @@ func (r *sesEventRepo) Save(ctx context.Context, e *sesevent.Event) error {
 		e.SmtpResponse,
 		e.RemoteMtaIp,
 		e.ReportingMTA,
-		e.Tags,
-	)
-	return err
+		tags,
+		e.ComplaintFeedbackType,
+		e.ComplaintSubType,
+		e.ComplaintUserAgent,
+		e.ComplaintArrivalDate,
+		e.FeedbackID,
+		e.Link,
+		e.LinkTags,
+		e.IPAddress,
+		e.UserAgent,
+		e.OccurredAt,
+		e.DelayType,
+		e.DelayExpirationTime,
+		e.TemplateName,
+		e.ContactList,
+		e.TopicPreferences,
+		e.ConfigurationSet,
+		e.FromDomain,
+		e.SNSMessageID,
+		publishingMechanism,
+		provider,
+		eventKey,
+	}
 }
 
 func (r *sesEventRepo) GetEvents(ctx context.Context) ([]*sesevent.Event, error) {
 	query := `
-		SELECT message_id, email, subject, event_type, status, reason, source, recipients,
-			   event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
-			   processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags
+		SELECT ` + sesEventColumns + `
 		FROM ses_events
 		ORDER BY event_timestamp DESC
 	`
@@ -64,43 +288,14 @@ func (r *sesEventRepo) GetEvents(ctx context.Context) ([]*sesevent.Event, error)
 	}
 	defer rows.Close()
 
-	var events []*sesevent.Event
-	for rows.Next() {
-		e := &sesevent.Event{}
-		err := rows.Scan(
-			&e.MessageID,
-			&e.Email,
-			&e.Subject,
-			&e.EventType,
-			&e.Status,
-			&e.Reason,
-			&e.Source,
-			&e.Recipients,
-			&e.EventTimestamp,
-			&e.BounceType,
-			&e.BounceSubType,
-			&e.DiagnosticCode,
-			&e.ProcessingTimeMillis,
-			&e.SmtpResponse,
-			&e.RemoteMtaIp,
-			&e.ReportingMTA,
-			&e.Tags,
-		)
-		if err != nil {
-			return nil, err
-		}
-		events = append(events, e)
-	}
-	return events, nil
+	return scanEvents(rows)
 }
 
 func (r *sesEventRepo) GetEventsPaginated(ctx context.Context, limit, offset int) ([]*sesevent.Event, error) {
 	query := `
-		SELECT message_id, email, subject, event_type, status, reason, source, recipients,
-			   event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
-			   processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags
+		SELECT ` + sesEventColumns + `
 		FROM ses_events
-		ORDER BY event_timestamp DESC
+		ORDER BY event_timestamp DESC, id DESC
 		LIMIT $1 OFFSET $2
 	`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
@@ -109,68 +304,57 @@ func (r *sesEventRepo) GetEventsPaginated(ctx context.Context, limit, offset int
 	}
 	defer rows.Close()
 
-	var events []*sesevent.Event
-	for rows.Next() {
-		e := &sesevent.Event{}
-		err := rows.Scan(
-			&e.MessageID,
-			&e.Email,
-			&e.Subject,
-			&e.EventType,
-			&e.Status,
-			&e.Reason,
-			&e.Source,
-			&e.Recipients,
-			&e.EventTimestamp,
-			&e.BounceType,
-			&e.BounceSubType,
-			&e.DiagnosticCode,
-			&e.ProcessingTimeMillis,
-			&e.SmtpResponse,
-			&e.RemoteMtaIp,
-			&e.ReportingMTA,
-			&e.Tags,
-		)
-		if err != nil {
-			return nil, err
-		}
-		events = append(events, e)
-	}
-	return events, nil
+	return scanEvents(rows)
 }
 
-func (r *sesEventRepo) GetEventsWithFilter(ctx context.Context, limit, offset int, search, startDate, endDate string) ([]*sesevent.Event, error) {
+func (r *sesEventRepo) GetEventsWithFilter(ctx context.Context, filter sesevent.Filter, limit, offset int) ([]*sesevent.Event, error) {
+	where, args := buildEventFilter(filter)
 	query := `
-		SELECT message_id, email, subject, event_type, status, reason, source, recipients,
-			   event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
-			   processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags
+		SELECT ` + sesEventColumns + `
 		FROM ses_events
-		WHERE 1=1
-	`
-	args := []interface{}{}
-	argIndex := 0
+		WHERE 1=1` + where
+	query += " ORDER BY event_timestamp DESC, id DESC"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
 
-	if search != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND (email ILIKE $%d OR subject ILIKE $%d OR source ILIKE $%d)", argIndex, argIndex, argIndex)
-		args = append(args, "%"+search+"%")
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if startDate != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND event_timestamp >= $%d", argIndex)
-		args = append(args, startDate)
-	}
+	return scanEvents(rows)
+}
 
-	if endDate != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND event_timestamp <= $%d", argIndex)
-		args = append(args, endDate+" 23:59:59")
-	}
+func (r *sesEventRepo) GetFilteredEventCount(ctx context.Context, filter sesevent.Filter) (int, error) {
+	where, args := buildEventFilter(filter)
+	query := `SELECT COUNT(*) FROM ses_events WHERE 1=1` + where
 
-	query += " ORDER BY event_timestamp DESC"
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex+1, argIndex+2)
-	args = append(args, limit, offset)
+	var count int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func (r *sesEventRepo) GetEventsByCursor(ctx context.Context, filter sesevent.Filter, cursor *sesevent.Cursor, limit int) ([]*sesevent.Event, error) {
+	where, args := buildEventFilter(filter)
+	query := `
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE 1=1` + where
+
+	// Row comparison on (event_timestamp, id) walks idx_ses_events_timestamp_id
+	order := " ORDER BY event_timestamp DESC, id DESC"
+	if cursor != nil {
+		comparison := "<"
+		if cursor.Backward {
+			comparison = ">"
+			order = " ORDER BY event_timestamp, id"
+		}
+		query += fmt.Sprintf(" AND (event_timestamp, id) %s ($%d, $%d)", comparison, len(args)+1, len(args)+2)
+		args = append(args, cursor.EventTimestamp, cursor.ID)
+	}
+	query += order + fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -178,76 +362,314 @@ func (r *sesEventRepo) GetEventsWithFilter(ctx context.Context, limit, offset in
 	}
 	defer rows.Close()
 
-	var events []*sesevent.Event
-	for rows.Next() {
-		e := &sesevent.Event{}
-		err := rows.Scan(
-			&e.MessageID,
-			&e.Email,
-			&e.Subject,
-			&e.EventType,
-			&e.Status,
-			&e.Reason,
-			&e.Source,
-			&e.Recipients,
-			&e.EventTimestamp,
-			&e.BounceType,
-			&e.BounceSubType,
-			&e.DiagnosticCode,
-			&e.ProcessingTimeMillis,
-			&e.SmtpResponse,
-			&e.RemoteMtaIp,
-			&e.ReportingMTA,
-			&e.Tags,
-		)
+	return scanEvents(rows)
+}
+
+// ForEachEvent reads the events through a server side cursor, so neither
+// the driver nor the caller holds more than one batch of rows
+func (r *sesEventRepo) ForEachEvent(ctx context.Context, filter sesevent.Filter, fn func(*sesevent.Event) error) error {
+	// Cursors only live inside a transaction; a repeatable read snapshot keeps
+	// the result consistent however long the caller takes
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	where, args := buildEventFilter(filter)
+	declare := `DECLARE export_cursor NO SCROLL CURSOR FOR
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE 1=1` + where + `
+		ORDER BY event_timestamp DESC, id DESC`
+	if _, err := tx.ExecContext(ctx, declare, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", eventCursorBatchSize)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		events = append(events, e)
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			e, err := scanEvent(rows)
+			if err == nil {
+				err = fn(e)
+			}
+			if err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if fetched < eventCursorBatchSize {
+			return nil
+		}
+	}
+}
+
+// EstimateEventCount reads the row estimate of the planner instead of
+// counting, which is instant but only as accurate as the table statistics
+func (r *sesEventRepo) EstimateEventCount(ctx context.Context, filter sesevent.Filter) (int, error) {
+	if filter.IsEmpty() {
+		var estimate float64
+		err := r.db.QueryRowContext(ctx, `SELECT reltuples FROM pg_class WHERE oid = 'ses_events'::regclass`).Scan(&estimate)
+		if err != nil {
+			return 0, err
+		}
+		// reltuples is -1 until the table is first vacuumed or analyzed
+		if estimate >= 0 {
+			return int(estimate), nil
+		}
+	}
+
+	where, args := buildEventFilter(filter)
+	var plan []byte
+	err := r.db.QueryRowContext(ctx, `EXPLAIN (FORMAT JSON) SELECT 1 FROM ses_events WHERE 1=1`+where, args...).Scan(&plan)
+	if err != nil {
+		return 0, err
 	}
-	return events, nil
+
+	var explained []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, err
+	}
+	if len(explained) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+	return int(explained[0].Plan.PlanRows), nil
+}
+
+// GetEventsAfterID returns filtered events with an ID above afterID, oldest
+// first. IDs are taken at insert but become visible at commit, which
+// concurrent writers do out of order, so a live event stream reads from a
+// little behind its position and excludes the events it already sent.
+func (r *sesEventRepo) GetEventsAfterID(ctx context.Context, filter sesevent.Filter, afterID int64, exclude []int64, limit int) ([]*sesevent.Event, error) {
+	where, args := buildEventFilter(filter)
+	query := `
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE 1=1` + where
+	query += fmt.Sprintf(" AND id > $%d", len(args)+1)
+	args = append(args, afterID)
+	if len(exclude) > 0 {
+		query += fmt.Sprintf(" AND NOT (id = ANY($%d))", len(args)+1)
+		args = append(args, pq.Array(exclude))
+	}
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// GetLatestEventID returns the ID of the most recently stored event, or 0
+func (r *sesEventRepo) GetLatestEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM ses_events`).Scan(&id)
+	return id, err
+}
+
+func (r *sesEventRepo) GetFirstRecentEventID(ctx context.Context, window time.Duration) (int64, error) {
+	query := `SELECT COALESCE(MIN(id), 0) FROM ses_events WHERE created_at >= NOW() - make_interval(secs => $1)`
+	var id int64
+	err := r.db.QueryRowContext(ctx, query, window.Seconds()).Scan(&id)
+	return id, err
 }
 
-func (r *sesEventRepo) GetFilteredEventCount(ctx context.Context, search, startDate, endDate string) (int, error) {
-	query := `SELECT COUNT(*) FROM ses_events WHERE 1=1`
+// buildEventFilter returns the AND conditions and arguments shared by the filtered event queries
+func buildEventFilter(filter sesevent.Filter) (string, []interface{}) {
+	query := ""
 	args := []interface{}{}
-	argIndex := 0
+	// arg adds a query argument and returns its placeholder
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
-	if search != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND (email ILIKE $%d OR subject ILIKE $%d OR source ILIKE $%d)", argIndex, argIndex, argIndex)
-		args = append(args, "%"+search+"%")
+	if filter.Search != "" {
+		p := arg("%" + filter.Search + "%")
+		query += fmt.Sprintf(" AND (email ILIKE %s OR subject ILIKE %s OR source ILIKE %s)", p, p, p)
 	}
 
-	if startDate != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND event_timestamp >= $%d", argIndex)
-		args = append(args, startDate)
+	if filter.StartDate != "" {
+		query += " AND event_timestamp >= " + arg(filter.StartDate)
 	}
 
-	if endDate != "" {
-		argIndex++
-		query += fmt.Sprintf(" AND event_timestamp <= $%d", argIndex)
-		args = append(args, endDate+" 23:59:59")
+	if filter.EndDate != "" {
+		query += " AND event_timestamp <= " + arg(filter.EndDate+" 23:59:59")
 	}
 
-	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
-	return count, err
+	if len(filter.EventTypes) > 0 {
+		query += " AND event_type = ANY(" + arg(pq.Array(filter.EventTypes)) + ")"
+	}
+
+	if filter.BounceType != "" {
+		query += " AND bounce_type = " + arg(filter.BounceType)
+	}
+
+	if filter.BounceSubType != "" {
+		query += " AND bounce_sub_type = " + arg(filter.BounceSubType)
+	}
+
+	if filter.Status != "" {
+		query += " AND upper(status) = upper(" + arg(filter.Status) + ")"
+	}
+
+	if filter.Sender != "" {
+		query += " AND " + addressCondition("source", filter.Sender, arg)
+	}
+
+	if filter.RecipientDomain != "" {
+		query += " AND split_part(lower(email), '@', 2) = lower(" + arg(strings.TrimPrefix(filter.RecipientDomain, "@")) + ")"
+	}
+
+	if filter.ReportingMTA != "" {
+		query += " AND reporting_mta ILIKE " + arg("%"+filter.ReportingMTA+"%")
+	}
+
+	if filter.FeedbackType != "" {
+		query += " AND event_type = 'Complaint' AND complaint_feedback_type = " + arg(filter.FeedbackType)
+	}
+
+	if filter.Provider != "" {
+		query += " AND provider = " + arg(filter.Provider)
+	}
+
+	// SES tag values are arrays, so containment matches any event carrying the
+	// value. Keys are sorted so the same filter always builds the same query.
+	tagKeys := make([]string, 0, len(filter.Tags))
+	for key := range filter.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		values := filter.Tags[key]
+		conditions := make([]string, len(values))
+		for i, value := range values {
+			conditions[i] = tagCondition(key, value, arg)
+		}
+		query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	if filter.Query != nil {
+		query += " AND " + compileQuery(filter.Query, arg)
+	}
+
+	return query, args
+}
+
+// addressCondition matches an address column against an address, "local@"
+// for a local part at any domain or "@domain" for any address at a domain
+func addressCondition(column, address string, arg func(interface{}) string) string {
+	switch {
+	case strings.HasPrefix(address, "@"):
+		return "split_part(lower(" + column + "), '@', 2) = lower(" + arg(address[1:]) + ")"
+	case strings.HasSuffix(address, "@"):
+		return "split_part(lower(" + column + "), '@', 1) = lower(" + arg(strings.TrimSuffix(address, "@")) + ")"
+	default:
+		return "lower(" + column + ") = lower(" + arg(address) + ")"
+	}
+}
+
+func tagCondition(key, value string, arg func(interface{}) string) string {
+	containment, _ := json.Marshal(map[string][]string{key: {value}})
+	return "tags @> " + arg(string(containment)) + "::jsonb"
+}
+
+// compileQuery turns a parsed search query into a parenthesised SQL condition.
+// Values only ever become arguments.
+func compileQuery(expr *sesevent.QueryExpr, arg func(interface{}) string) string {
+	switch expr.Op {
+	case sesevent.QueryAnd, sesevent.QueryOr:
+		op := " AND "
+		if expr.Op == sesevent.QueryOr {
+			op = " OR "
+		}
+		parts := make([]string, 0, len(expr.Children))
+		for _, child := range expr.Children {
+			parts = append(parts, compileQuery(child, arg))
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	case sesevent.QueryNot:
+		// Columns of old rows can be NULL; NOT must still select those rows
+		return "(NOT COALESCE(" + compileQuery(expr.Children[0], arg) + ", false))"
+	}
+
+	value := expr.Value
+	switch expr.Field {
+	case sesevent.QueryFieldEventType:
+		return "(event_type = " + arg(value) + ")"
+	case sesevent.QueryFieldBounceType:
+		return "(bounce_type = " + arg(value) + ")"
+	case sesevent.QueryFieldBounceSubType:
+		return "(lower(bounce_sub_type) = lower(" + arg(value) + "))"
+	case sesevent.QueryFieldStatus:
+		return "(upper(status) = upper(" + arg(value) + "))"
+	case sesevent.QueryFieldSender:
+		return "(" + addressCondition("source", value, arg) + ")"
+	case sesevent.QueryFieldRecipient:
+		return "(" + addressCondition("email", value, arg) + ")"
+	case sesevent.QueryFieldRecipientDomain:
+		return "(split_part(lower(email), '@', 2) = lower(" + arg(strings.TrimPrefix(value, "@")) + "))"
+	case sesevent.QueryFieldSubject:
+		return "(subject ILIKE " + arg(containsPattern(value)) + ")"
+	case sesevent.QueryFieldReportingMTA:
+		return "(reporting_mta ILIKE " + arg(containsPattern(value)) + ")"
+	case sesevent.QueryFieldMessageID:
+		return "(message_id = " + arg(value) + ")"
+	case sesevent.QueryFieldProvider:
+		return "(provider = " + arg(value) + ")"
+	case sesevent.QueryFieldTag:
+		key, tagValue, _ := sesevent.ParseTag(value)
+		return "(" + tagCondition(key, tagValue, arg) + ")"
+	case sesevent.QueryFieldFeedbackType:
+		return "(event_type = 'Complaint' AND complaint_feedback_type = " + arg(value) + ")"
+	case sesevent.QueryFieldConfigurationSet:
+		return "(configuration_set = " + arg(value) + ")"
+	case sesevent.QueryFieldAfter:
+		return "(event_timestamp >= " + arg(value) + "::date)"
+	case sesevent.QueryFieldBefore:
+		return "(event_timestamp < " + arg(value) + "::date)"
+	default:
+		p := arg(containsPattern(value))
+		return fmt.Sprintf("(email ILIKE %s OR subject ILIKE %s OR source ILIKE %s)", p, p, p)
+	}
+}
+
+// containsPattern is an ILIKE pattern matching value literally anywhere
+func containsPattern(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	return "%" + escaped + "%"
 }
 
-func (r *sesEventRepo) GetEventCount(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(DISTINCT message_id) FROM ses_events`
+// GetEventCount counts messages of one provider. An empty provider, here and
+// in the metric queries below, counts events of every provider.
+func (r *sesEventRepo) GetEventCount(ctx context.Context, provider string) (int, error) {
+	query := `SELECT COUNT(DISTINCT message_id) FROM ses_events WHERE ($1 = '' OR provider = $1)`
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, provider).Scan(&count)
 	return count, err
 }
 
 func (r *sesEventRepo) GetEventsByType(ctx context.Context, eventType string) ([]*sesevent.Event, error) {
 	query := `
-		SELECT message_id, email, subject, event_type, status, reason, source, recipients,
-			   event_timestamp, bounce_type, bounce_sub_type, diagnostic_code,
-			   processing_time_millis, smtp_response, remote_mta_ip, reporting_mta, tags
+		SELECT ` + sesEventColumns + `
 		FROM ses_events
 		WHERE event_type = $1
 		ORDER BY event_timestamp DESC
@@ -258,75 +680,204 @@ func (r *sesEventRepo) GetEventsByType(ctx context.Context, eventType string) ([
 	}
 	defer rows.Close()
 
-	var events []*sesevent.Event
+	return scanEvents(rows)
+}
+
+// GetEventsByMessageID returns every event of one sent message in the order
+// they happened
+func (r *sesEventRepo) GetEventsByMessageID(ctx context.Context, messageID string) ([]*sesevent.Event, error) {
+	query := `
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE message_id = $1
+		ORDER BY COALESCE(occurred_at, event_timestamp), id
+	`
+	rows, err := r.db.QueryContext(ctx, query, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// Recipient queries match lower(email) so they use idx_ses_events_recipient
+// whatever case the address was stored in.
+
+// GetEventsByRecipient returns the events of one email address, newest first
+func (r *sesEventRepo) GetEventsByRecipient(ctx context.Context, email string, limit, offset int) ([]*sesevent.Event, error) {
+	query := `
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE lower(email) = lower($1)
+		ORDER BY event_timestamp DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, email, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (r *sesEventRepo) GetRecipientStats(ctx context.Context, email string) (*sesevent.RecipientStats, error) {
+	query := `
+		SELECT COUNT(*),
+		       COUNT(DISTINCT message_id),
+		       COUNT(*) FILTER (WHERE event_type = 'Send'),
+		       COUNT(*) FILTER (WHERE event_type = 'Delivery'),
+		       COUNT(*) FILTER (WHERE event_type = 'Bounce'),
+		       COUNT(*) FILTER (WHERE event_type = 'Bounce' AND bounce_type = 'Permanent'),
+		       COUNT(*) FILTER (WHERE event_type = 'Complaint'),
+		       COUNT(*) FILTER (WHERE event_type = 'Open'),
+		       COUNT(*) FILTER (WHERE event_type = 'Click'),
+		       MIN(COALESCE(occurred_at, event_timestamp)),
+		       MAX(COALESCE(occurred_at, event_timestamp)),
+		       MAX(COALESCE(occurred_at, event_timestamp)) FILTER (WHERE event_type = 'Delivery'),
+		       MAX(COALESCE(occurred_at, event_timestamp)) FILTER (WHERE event_type = 'Bounce'),
+		       MAX(COALESCE(occurred_at, event_timestamp)) FILTER (WHERE event_type = 'Complaint'),
+		       MAX(COALESCE(occurred_at, event_timestamp)) FILTER (WHERE event_type IN ('Open', 'Click')),
+		       COALESCE((ARRAY_AGG(event_type ORDER BY COALESCE(occurred_at, event_timestamp) DESC)
+		                 FILTER (WHERE event_type IN ('Open', 'Click')))[1], '')
+		FROM ses_events
+		WHERE lower(email) = lower($1)
+	`
+	stats := &sesevent.RecipientStats{Email: email}
+	var firstSeen, lastSeen, lastDelivery, lastBounce, lastComplaint, lastEngagement sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&stats.TotalEvents,
+		&stats.Messages,
+		&stats.SendCount,
+		&stats.DeliveryCount,
+		&stats.BounceCount,
+		&stats.HardBounceCount,
+		&stats.ComplaintCount,
+		&stats.OpenCount,
+		&stats.ClickCount,
+		&firstSeen,
+		&lastSeen,
+		&lastDelivery,
+		&lastBounce,
+		&lastComplaint,
+		&lastEngagement,
+		&stats.LastEngagementType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	stats.FirstSeenAt = nullTimePtr(firstSeen)
+	stats.LastSeenAt = nullTimePtr(lastSeen)
+	stats.LastDeliveryAt = nullTimePtr(lastDelivery)
+	stats.LastBounceAt = nullTimePtr(lastBounce)
+	stats.LastComplaintAt = nullTimePtr(lastComplaint)
+	stats.LastEngagementAt = nullTimePtr(lastEngagement)
+	return stats, nil
+}
+
+// GetRecipientSenders returns the sender addresses that mailed a recipient,
+// most recent first
+func (r *sesEventRepo) GetRecipientSenders(ctx context.Context, email string, limit int) ([]*sesevent.RecipientSender, error) {
+	query := `
+		SELECT source, COUNT(DISTINCT message_id), MAX(event_timestamp)
+		FROM ses_events
+		WHERE lower(email) = lower($1)
+		GROUP BY source
+		ORDER BY MAX(event_timestamp) DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var senders []*sesevent.RecipientSender
 	for rows.Next() {
-		e := &sesevent.Event{}
-		err := rows.Scan(
-			&e.MessageID,
-			&e.Email,
-			&e.Subject,
-			&e.EventType,
-			&e.Status,
-			&e.Reason,
-			&e.Source,
-			&e.Recipients,
-			&e.EventTimestamp,
-			&e.BounceType,
-			&e.BounceSubType,
-			&e.DiagnosticCode,
-			&e.ProcessingTimeMillis,
-			&e.SmtpResponse,
-			&e.RemoteMtaIp,
-			&e.ReportingMTA,
-			&e.Tags,
-		)
-		if err != nil {
+		s := &sesevent.RecipientSender{}
+		if err := rows.Scan(&s.Source, &s.Messages, &s.LastSentAt); err != nil {
 			return nil, err
 		}
-		events = append(events, e)
+		senders = append(senders, s)
+	}
+	return senders, rows.Err()
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// GetEventsByIDs returns the stored events with the given IDs, in ID order.
+// IDs that no longer exist are skipped.
+func (r *sesEventRepo) GetEventsByIDs(ctx context.Context, ids []int64) ([]*sesevent.Event, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT ` + sesEventColumns + `
+		FROM ses_events
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
 	}
-	return events, nil
+	defer rows.Close()
+	return scanEvents(rows)
 }
 
-func (r *sesEventRepo) GetBounceRate(ctx context.Context) (float64, error) {
+func (r *sesEventRepo) GetBounceRate(ctx context.Context, provider string) (float64, error) {
 	query := `
 		SELECT 
 			CASE 
-				WHEN (SELECT COUNT(DISTINCT message_id) FROM ses_events) = 0 THEN 0
-				ELSE (SELECT COUNT(DISTINCT message_id) FROM ses_events WHERE event_type = 'Bounce') * 100.0 / (SELECT COUNT(DISTINCT message_id) FROM ses_events)
+				WHEN COUNT(DISTINCT (message_id, email)) = 0 THEN 0
+				ELSE COUNT(DISTINCT CASE WHEN event_type = 'Bounce' THEN (message_id, email) END) * 100.0 / COUNT(DISTINCT (message_id, email))
 			END
+		FROM ses_events
+		WHERE ($1 = '' OR provider = $1)
 	`
 	var rate float64
-	err := r.db.QueryRowContext(ctx, query).Scan(&rate)
+	err := r.db.QueryRowContext(ctx, query, provider).Scan(&rate)
 	return rate, err
 }
 
-func (r *sesEventRepo) GetDeliveryRate(ctx context.Context) (float64, error) {
+func (r *sesEventRepo) GetDeliveryRate(ctx context.Context, provider string) (float64, error) {
 	query := `
 		SELECT 
 			CASE 
-				WHEN (SELECT COUNT(DISTINCT message_id) FROM ses_events) = 0 THEN 0
-				ELSE (SELECT COUNT(DISTINCT message_id) FROM ses_events WHERE event_type = 'Delivery') * 100.0 / (SELECT COUNT(DISTINCT message_id) FROM ses_events)
+				WHEN COUNT(DISTINCT (message_id, email)) = 0 THEN 0
+				ELSE COUNT(DISTINCT CASE WHEN event_type = 'Delivery' THEN (message_id, email) END) * 100.0 / COUNT(DISTINCT (message_id, email))
 			END
+		FROM ses_events
+		WHERE ($1 = '' OR provider = $1)
 	`
 	var rate float64
-	err := r.db.QueryRowContext(ctx, query).Scan(&rate)
+	err := r.db.QueryRowContext(ctx, query, provider).Scan(&rate)
 	return rate, err
 }
 
-func (r *sesEventRepo) GetDailyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.DailyMetrics, error) {
+func (r *sesEventRepo) GetDailyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.DailyMetrics, error) {
 	query := `
 		SELECT 
 			DATE(event_timestamp) as date,
-			COUNT(DISTINCT message_id) as total_events,
-			COUNT(DISTINCT CASE WHEN event_type = 'Send' THEN message_id END) as send_count,
-			COUNT(DISTINCT CASE WHEN event_type = 'Delivery' THEN message_id END) as delivery_count,
-			COUNT(DISTINCT CASE WHEN event_type = 'Bounce' THEN message_id END) as bounce_count,
-			COUNT(DISTINCT CASE WHEN event_type = 'Complaint' THEN message_id END) as complaint_count,
+			COUNT(DISTINCT (message_id, email)) as total_events,
+			COUNT(DISTINCT CASE WHEN event_type = 'Send' THEN (message_id, email) END) as send_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'Delivery' THEN (message_id, email) END) as delivery_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'Bounce' THEN (message_id, email) END) as bounce_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'Complaint' THEN (message_id, email) END) as complaint_count,
 			COUNT(DISTINCT CASE WHEN event_type = 'Open' THEN message_id END) as open_count,
 			COUNT(DISTINCT CASE WHEN event_type = 'Click' THEN message_id END) as click_count,
-			CASE WHEN COUNT(DISTINCT message_id) = 0 THEN 0 ELSE (COUNT(DISTINCT CASE WHEN event_type = 'Bounce' THEN message_id END) * 100.0 / COUNT(DISTINCT message_id)) END as bounce_rate,
-			CASE WHEN COUNT(DISTINCT message_id) = 0 THEN 0 ELSE (COUNT(DISTINCT CASE WHEN event_type = 'Delivery' THEN message_id END) * 100.0 / COUNT(DISTINCT message_id)) END as delivery_rate
+			COUNT(DISTINCT CASE WHEN event_type = 'DeliveryDelay' THEN (message_id, email) END) as delivery_delay_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'Reject' THEN (message_id, email) END) as reject_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'RenderingFailure' THEN (message_id, email) END) as rendering_failure_count,
+			COUNT(DISTINCT CASE WHEN event_type = 'Subscription' THEN (message_id, email) END) as subscription_count,
+			CASE WHEN COUNT(DISTINCT (message_id, email)) = 0 THEN 0 ELSE (COUNT(DISTINCT CASE WHEN event_type = 'Bounce' THEN (message_id, email) END) * 100.0 / COUNT(DISTINCT (message_id, email))) END as bounce_rate,
+			CASE WHEN COUNT(DISTINCT (message_id, email)) = 0 THEN 0 ELSE (COUNT(DISTINCT CASE WHEN event_type = 'Delivery' THEN (message_id, email) END) * 100.0 / COUNT(DISTINCT (message_id, email))) END as delivery_rate
 		FROM ses_events
 	`
 	args := []interface{}{}
@@ -339,6 +890,10 @@ func (r *sesEventRepo) GetDailyMetrics(ctx context.Context, start, end *time.Tim
 		args = append(args, *end)
 		conditions = append(conditions, fmt.Sprintf("event_timestamp < $%d", len(args)))
 	}
+	if provider != "" {
+		args = append(args, provider)
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", len(args)))
+	}
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
@@ -355,7 +910,7 @@ func (r *sesEventRepo) GetDailyMetrics(ctx context.Context, start, end *time.Tim
 	var metrics []*sesevent.DailyMetrics
 	for rows.Next() {
 		m := &sesevent.DailyMetrics{}
-		err := rows.Scan(&m.Date, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.BounceRate, &m.DeliveryRate)
+		err := rows.Scan(&m.Date, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.DeliveryDelayCount, &m.RejectCount, &m.RenderingFailureCount, &m.SubscriptionCount, &m.BounceRate, &m.DeliveryRate)
 		if err != nil {
 			return nil, err
 		}
@@ -364,7 +919,7 @@ func (r *sesEventRepo) GetDailyMetrics(ctx context.Context, start, end *time.Tim
 	return metrics, nil
 }
 
-func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.MonthlyMetrics, error) {
+func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.MonthlyMetrics, error) {
 	query := `
 		SELECT 
 			TO_CHAR(DATE_TRUNC('month', event_timestamp), 'YYYY-MM') as month,
@@ -375,6 +930,10 @@ func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.T
 			SUM(CASE WHEN event_type = 'Complaint' THEN 1 ELSE 0 END) as complaint_count,
 			SUM(CASE WHEN event_type = 'Open' THEN 1 ELSE 0 END) as open_count,
 			SUM(CASE WHEN event_type = 'Click' THEN 1 ELSE 0 END) as click_count,
+			SUM(CASE WHEN event_type = 'DeliveryDelay' THEN 1 ELSE 0 END) as delivery_delay_count,
+			SUM(CASE WHEN event_type = 'Reject' THEN 1 ELSE 0 END) as reject_count,
+			SUM(CASE WHEN event_type = 'RenderingFailure' THEN 1 ELSE 0 END) as rendering_failure_count,
+			SUM(CASE WHEN event_type = 'Subscription' THEN 1 ELSE 0 END) as subscription_count,
 			CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN event_type = 'Bounce' THEN 1 ELSE 0 END) * 100.0 / COUNT(*)) END as bounce_rate,
 			CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN event_type = 'Delivery' THEN 1 ELSE 0 END) * 100.0 / COUNT(*)) END as delivery_rate
 		FROM ses_events
@@ -389,6 +948,10 @@ func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.T
 		args = append(args, *end)
 		conditions = append(conditions, fmt.Sprintf("event_timestamp < $%d", len(args)))
 	}
+	if provider != "" {
+		args = append(args, provider)
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", len(args)))
+	}
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
@@ -405,7 +968,7 @@ func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.T
 	var metrics []*sesevent.MonthlyMetrics
 	for rows.Next() {
 		m := &sesevent.MonthlyMetrics{}
-		err := rows.Scan(&m.Month, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.BounceRate, &m.DeliveryRate)
+		err := rows.Scan(&m.Month, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.DeliveryDelayCount, &m.RejectCount, &m.RenderingFailureCount, &m.SubscriptionCount, &m.BounceRate, &m.DeliveryRate)
 		if err != nil {
 			return nil, err
 		}
@@ -414,7 +977,7 @@ func (r *sesEventRepo) GetMonthlyMetrics(ctx context.Context, start, end *time.T
 	return metrics, nil
 }
 
-func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.HourlyMetrics, error) {
+func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.HourlyMetrics, error) {
 	query := `
 		SELECT 
 			TO_CHAR(DATE_TRUNC('hour', event_timestamp), 'YYYY-MM-DD HH24:00') as hour,
@@ -425,6 +988,10 @@ func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Ti
 			SUM(CASE WHEN event_type = 'Complaint' THEN 1 ELSE 0 END) as complaint_count,
 			SUM(CASE WHEN event_type = 'Open' THEN 1 ELSE 0 END) as open_count,
 			SUM(CASE WHEN event_type = 'Click' THEN 1 ELSE 0 END) as click_count,
+			SUM(CASE WHEN event_type = 'DeliveryDelay' THEN 1 ELSE 0 END) as delivery_delay_count,
+			SUM(CASE WHEN event_type = 'Reject' THEN 1 ELSE 0 END) as reject_count,
+			SUM(CASE WHEN event_type = 'RenderingFailure' THEN 1 ELSE 0 END) as rendering_failure_count,
+			SUM(CASE WHEN event_type = 'Subscription' THEN 1 ELSE 0 END) as subscription_count,
 			CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN event_type = 'Bounce' THEN 1 ELSE 0 END) * 100.0 / COUNT(*)) END as bounce_rate,
 			CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN event_type = 'Delivery' THEN 1 ELSE 0 END) * 100.0 / COUNT(*)) END as delivery_rate
 		FROM ses_events
@@ -439,6 +1006,10 @@ func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Ti
 		args = append(args, *end)
 		conditions = append(conditions, fmt.Sprintf("event_timestamp < $%d", len(args)))
 	}
+	if provider != "" {
+		args = append(args, provider)
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", len(args)))
+	}
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
@@ -455,7 +1026,7 @@ func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Ti
 	var metrics []*sesevent.HourlyMetrics
 	for rows.Next() {
 		m := &sesevent.HourlyMetrics{}
-		err := rows.Scan(&m.Hour, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.BounceRate, &m.DeliveryRate)
+		err := rows.Scan(&m.Hour, &m.TotalEvents, &m.SendCount, &m.DeliveryCount, &m.BounceCount, &m.ComplaintCount, &m.OpenCount, &m.ClickCount, &m.DeliveryDelayCount, &m.RejectCount, &m.RenderingFailureCount, &m.SubscriptionCount, &m.BounceRate, &m.DeliveryRate)
 		if err != nil {
 			return nil, err
 		}
@@ -464,13 +1035,14 @@ func (r *sesEventRepo) GetHourlyMetrics(ctx context.Context, start, end *time.Ti
 	return metrics, nil
 }
 
-func (r *sesEventRepo) GetEventTypeCounts(ctx context.Context) (map[string]int, error) {
+func (r *sesEventRepo) GetEventTypeCounts(ctx context.Context, provider string) (map[string]int, error) {
 	query := `
-		SELECT event_type, COUNT(DISTINCT message_id)
+		SELECT event_type, COUNT(DISTINCT (message_id, email))
 		FROM ses_events
+		WHERE ($1 = '' OR provider = $1)
 		GROUP BY event_type
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, provider)
 	if err != nil {
 		return nil, err
 	}
@@ -489,6 +1061,56 @@ func (r *sesEventRepo) GetEventTypeCounts(ctx context.Context) (map[string]int,
 	return counts, nil
 }
 
+// GetTopClickedLinks ranks clicked links by unique clicks, counting each message once.
+// SES does not report which recipient of a message clicked.
+func (r *sesEventRepo) GetTopClickedLinks(ctx context.Context, start, end *time.Time, provider string, limit int) ([]*sesevent.LinkClickStats, error) {
+	query := `
+		SELECT
+			link,
+			COUNT(DISTINCT message_id) as unique_clicks,
+			COUNT(*) as total_clicks,
+			MAX(occurred_at) as last_clicked_at
+		FROM ses_events
+	`
+	args := []interface{}{}
+	conditions := []string{"event_type = 'Click'", "link <> ''"}
+	if start != nil {
+		args = append(args, *start)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if end != nil {
+		args = append(args, *end)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	if provider != "" {
+		args = append(args, provider)
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", len(args)))
+	}
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	args = append(args, limit)
+	query += fmt.Sprintf(`
+		GROUP BY link
+		ORDER BY unique_clicks DESC, total_clicks DESC
+		LIMIT $%d
+	`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*sesevent.LinkClickStats
+	for rows.Next() {
+		l := &sesevent.LinkClickStats{}
+		if err := rows.Scan(&l.Link, &l.UniqueClicks, &l.TotalClicks, &l.LastClickedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
 // DeleteOldEvents menghapus event logs yang lebih lama dari cutoff date
 func (r *sesEventRepo) DeleteOldEvents(ctx context.Context, cutoffDate time.Time) (int64, error) {
 	query := `DELETE FROM ses_events WHERE event_timestamp < $1`
@@ -498,3 +1120,104 @@ func (r *sesEventRepo) DeleteOldEvents(ctx context.Context, cutoffDate time.Time
 	}
 	return result.RowsAffected()
 }
+
+// duplicateEventIDs selects every stored row that repeats an earlier row for
+// the same message, recipient, event type, event time and link. Opens and
+// clicks stored before occurred_at existed cannot be told apart from genuine
+// repeat engagement, so they are left alone.
+const duplicateEventIDs = `
+	SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (
+			PARTITION BY message_id, email, event_type, COALESCE(occurred_at, event_timestamp), COALESCE(link, '')
+			ORDER BY id
+		) AS rn
+		FROM ses_events
+		WHERE NOT (event_type IN ('Open', 'Click') AND occurred_at IS NULL)
+	) ranked
+	WHERE rn > 1`
+
+func (r *sesEventRepo) CountDuplicateEvents(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+duplicateEventIDs+`) d`).Scan(&count)
+	return count, err
+}
+
+func (r *sesEventRepo) DeleteDuplicateEvents(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ses_events WHERE id IN (`+duplicateEventIDs+`)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanEvents(rows *sql.Rows) ([]*sesevent.Event, error) {
+	var events []*sesevent.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// scanEvent reads the sesEventColumns of the current row
+func scanEvent(rows *sql.Rows) (*sesevent.Event, error) {
+	e := &sesevent.Event{}
+	var complaintArrivalDate, occurredAt, delayExpirationTime sql.NullTime
+	err := rows.Scan(
+		&e.MessageID,
+		&e.Email,
+		&e.Subject,
+		&e.EventType,
+		&e.Status,
+		&e.Reason,
+		&e.Source,
+		&e.Recipients,
+		&e.EventTimestamp,
+		&e.BounceType,
+		&e.BounceSubType,
+		&e.DiagnosticCode,
+		&e.ProcessingTimeMillis,
+		&e.SmtpResponse,
+		&e.RemoteMtaIp,
+		&e.ReportingMTA,
+		&e.Tags,
+		&e.ComplaintFeedbackType,
+		&e.ComplaintSubType,
+		&e.ComplaintUserAgent,
+		&complaintArrivalDate,
+		&e.FeedbackID,
+		&e.Link,
+		&e.LinkTags,
+		&e.IPAddress,
+		&e.UserAgent,
+		&occurredAt,
+		&e.DelayType,
+		&delayExpirationTime,
+		&e.TemplateName,
+		&e.ContactList,
+		&e.TopicPreferences,
+		&e.ConfigurationSet,
+		&e.FromDomain,
+		&e.SNSMessageID,
+		&e.PublishingMechanism,
+		&e.Provider,
+		&e.ID,
+		&e.EventKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if complaintArrivalDate.Valid {
+		e.ComplaintArrivalDate = &complaintArrivalDate.Time
+	}
+	if occurredAt.Valid {
+		e.OccurredAt = &occurredAt.Time
+	}
+	if delayExpirationTime.Valid {
+		e.DelayExpirationTime = &delayExpirationTime.Time
+	}
+	return e, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"ses-monitoring/internal/domain/snssubscription"
+)
+
+type snsSubscriptionRepo struct {
+	db *sql.DB
+}
+
+func NewSNSSubscriptionRepository(db *sql.DB) snssubscription.Repository {
+	return &snsSubscriptionRepo{db: db}
+}
+
+func (r *snsSubscriptionRepo) SetStatus(ctx context.Context, topicArn string, status snssubscription.Status, subscriptionArn, lastError string) error {
+	query := `
+		INSERT INTO sns_subscriptions (topic_arn, subscription_arn, status, last_error, confirmed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, CASE WHEN $3 = 'confirmed' THEN NOW() END, NOW(), NOW())
+		ON CONFLICT (topic_arn)
+		DO UPDATE SET
+			subscription_arn = CASE WHEN $2 = '' THEN sns_subscriptions.subscription_arn ELSE $2 END,
+			status = $3,
+			last_error = $4,
+			confirmed_at = CASE WHEN $3 = 'confirmed' THEN NOW() ELSE sns_subscriptions.confirmed_at END,
+			updated_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, topicArn, subscriptionArn, string(status), lastError)
+	return err
+}
+
+// RecordMessage stores the time of the latest notification for a topic. SNS
+// only delivers notifications to confirmed subscriptions, so a topic that is
+// missing, pending or failed because its confirmation request was lost is
+// marked confirmed. An unsubscribed topic stays unsubscribed: notifications
+// still in flight after the UnsubscribeConfirmation must not revive it.
+func (r *snsSubscriptionRepo) RecordMessage(ctx context.Context, topicArn string) error {
+	query := `
+		INSERT INTO sns_subscriptions (topic_arn, status, last_message_at, confirmed_at, created_at, updated_at)
+		VALUES ($1, 'confirmed', NOW(), NOW(), NOW(), NOW())
+		ON CONFLICT (topic_arn)
+		DO UPDATE SET
+			status = CASE WHEN sns_subscriptions.status IN ('pending', 'failed') THEN 'confirmed' ELSE sns_subscriptions.status END,
+			last_error = CASE WHEN sns_subscriptions.status IN ('pending', 'failed') THEN '' ELSE sns_subscriptions.last_error END,
+			last_message_at = NOW(),
+			confirmed_at = CASE WHEN sns_subscriptions.status IN ('pending', 'failed') THEN COALESCE(sns_subscriptions.confirmed_at, NOW()) ELSE sns_subscriptions.confirmed_at END,
+			updated_at = CASE WHEN sns_subscriptions.status IN ('pending', 'failed') THEN NOW() ELSE sns_subscriptions.updated_at END
+	`
+	_, err := r.db.ExecContext(ctx, query, topicArn)
+	return err
+}
+
+func (r *snsSubscriptionRepo) GetAll(ctx context.Context) ([]*snssubscription.Subscription, error) {
+	query := `
+		SELECT topic_arn, subscription_arn, status, last_error, confirmed_at, last_message_at, created_at, updated_at
+		FROM sns_subscriptions
+		ORDER BY topic_arn
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*snssubscription.Subscription
+	for rows.Next() {
+		s := &snssubscription.Subscription{}
+		var confirmedAt, lastMessageAt sql.NullTime
+		err := rows.Scan(&s.TopicArn, &s.SubscriptionArn, &s.Status, &s.LastError, &confirmedAt, &lastMessageAt, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if confirmedAt.Valid {
+			s.ConfirmedAt = &confirmedAt.Time
+		}
+		if lastMessageAt.Valid {
+			s.LastMessageAt = &lastMessageAt.Time
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, nil
+}
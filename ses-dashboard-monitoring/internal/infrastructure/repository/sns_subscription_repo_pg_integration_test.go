@@ -0,0 +1,101 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"ses-monitoring/internal/domain/snssubscription"
+)
+
+// testDB connects to TEST_DATABASE_URL and creates the given migrations in a
+// schema of their own, which is dropped after the test
+func testDB(t *testing.T, migrations ...string) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The search path is per connection
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := db.Exec("CREATE SCHEMA " + schema + "; SET search_path TO " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		db.Close()
+	})
+
+	for _, name := range migrations {
+		up, err := os.ReadFile("../database/migration/" + name + ".up.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(up)); err != nil {
+			t.Fatalf("migration %s: %v", name, err)
+		}
+	}
+	return db
+}
+
+func TestSNSSubscriptionRecordMessage(t *testing.T) {
+	db := testDB(t, "0012_create_sns_subscriptions")
+	repo := NewSNSSubscriptionRepository(db)
+	ctx := context.Background()
+
+	setStatus := func(topic string, status snssubscription.Status) {
+		t.Helper()
+		if err := repo.SetStatus(ctx, topic, status, "", "confirmation failed"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setStatus("arn:aws:sns:us-east-1:123456789012:pending", snssubscription.StatusPending)
+	setStatus("arn:aws:sns:us-east-1:123456789012:failed", snssubscription.StatusFailed)
+	setStatus("arn:aws:sns:us-east-1:123456789012:unsubscribed", snssubscription.StatusUnsubscribed)
+
+	want := map[string]snssubscription.Status{
+		"arn:aws:sns:us-east-1:123456789012:new":          snssubscription.StatusConfirmed,
+		"arn:aws:sns:us-east-1:123456789012:pending":      snssubscription.StatusConfirmed,
+		"arn:aws:sns:us-east-1:123456789012:failed":       snssubscription.StatusConfirmed,
+		"arn:aws:sns:us-east-1:123456789012:unsubscribed": snssubscription.StatusUnsubscribed,
+	}
+	for topic := range want {
+		if err := repo.RecordMessage(ctx, topic); err != nil {
+			t.Fatalf("RecordMessage(%s) = %v", topic, err)
+		}
+	}
+
+	subscriptions, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != len(want) {
+		t.Fatalf("%d subscriptions, want %d", len(subscriptions), len(want))
+	}
+	for _, s := range subscriptions {
+		if s.Status != want[s.TopicArn] {
+			t.Errorf("%s: status %s, want %s", s.TopicArn, s.Status, want[s.TopicArn])
+		}
+		if s.LastMessageAt == nil {
+			t.Errorf("%s: last message time not recorded", s.TopicArn)
+		}
+		confirmed := s.Status == snssubscription.StatusConfirmed
+		if confirmed && (s.ConfirmedAt == nil || s.LastError != "") {
+			t.Errorf("%s: confirmed at %v with error %q", s.TopicArn, s.ConfirmedAt, s.LastError)
+		}
+		if !confirmed && s.ConfirmedAt != nil {
+			t.Errorf("%s: unsubscribed topic got a confirmation time", s.TopicArn)
+		}
+	}
+}
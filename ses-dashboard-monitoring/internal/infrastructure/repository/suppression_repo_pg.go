@@ -138,6 +138,30 @@ func (r *suppressionRepo) IsSupressed(ctx context.Context, email string) (bool,
 	return exists, err
 }
 
+func (r *suppressionRepo) GetByEmail(ctx context.Context, email string) (*suppression.SuppressionEntry, error) {
+	query := `
+		SELECT s.id, s.email, s.suppression_type, COALESCE(s.reason, ''), s.aws_status, s.is_active,
+		       COALESCE(s.added_by, 0), COALESCE(u.username, 'System') as added_by_name,
+		       s.created_at, s.updated_at
+		FROM suppression_list s
+		LEFT JOIN users u ON s.added_by = u.id
+		WHERE s.email = $1
+	`
+
+	e := &suppression.SuppressionEntry{}
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&e.ID, &e.Email, &e.SuppressionType, &e.Reason, &e.AWSStatus, &e.IsActive,
+		&e.AddedBy, &e.AddedByName, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 func (r *suppressionRepo) UpdateAWSStatus(ctx context.Context, email string, status suppression.AWSStatus) error {
 	query := `UPDATE suppression_list SET aws_status = $1, updated_at = NOW() WHERE email = $2`
 	_, err := r.db.ExecContext(ctx, query, status, email)
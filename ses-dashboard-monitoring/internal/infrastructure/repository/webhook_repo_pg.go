@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/webhook"
+)
+
+const webhookSubscriptionColumns = `id, name, url, secret, event_types, senders, tags, enabled,
+		consecutive_failures, disabled_reason, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, subscription_id, event_key, event_type, payload, status, attempts,
+		next_attempt_at, last_status_code, last_error, created_at, updated_at, delivered_at`
+
+type webhookRepo struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) webhook.Repository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, s *webhook.Subscription) error {
+	eventTypes, senders, tags := marshalWebhookFilters(s)
+	query := `
+		INSERT INTO webhook_subscriptions (name, url, secret, event_types, senders, tags, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, s.Name, s.URL, s.Secret, eventTypes, senders, tags, s.Enabled).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id int64) (*webhook.Subscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+	s, err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*webhook.Subscription
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, s *webhook.Subscription) error {
+	eventTypes, senders, tags := marshalWebhookFilters(s)
+	query := `
+		UPDATE webhook_subscriptions
+		SET name = $2, url = $3, event_types = $4, senders = $5, tags = $6, enabled = $7,
+			consecutive_failures = CASE WHEN $7 AND NOT enabled THEN 0 ELSE consecutive_failures END,
+			disabled_reason = CASE WHEN $7 THEN '' ELSE disabled_reason END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING consecutive_failures, disabled_reason, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, s.ID, s.Name, s.URL, eventTypes, senders, tags, s.Enabled).
+		Scan(&s.ConsecutiveFailures, &s.DisabledReason, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook.ErrNotFound
+	}
+	return err
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireWebhookRow(result, webhook.ErrNotFound)
+}
+
+func (r *webhookRepo) RecordSubscriptionResult(ctx context.Context, id int64, success bool, disableAfter int, reason string) (bool, error) {
+	if success {
+		_, err := r.db.ExecContext(ctx, `UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`, id)
+		return false, err
+	}
+
+	query := `
+		WITH previous AS (
+			SELECT id, enabled FROM webhook_subscriptions WHERE id = $1 FOR UPDATE
+		)
+		UPDATE webhook_subscriptions s
+		SET consecutive_failures = s.consecutive_failures + 1,
+			enabled = s.enabled AND s.consecutive_failures + 1 < $2,
+			disabled_reason = CASE WHEN s.enabled AND s.consecutive_failures + 1 >= $2 THEN $3 ELSE s.disabled_reason END,
+			updated_at = NOW()
+		FROM previous
+		WHERE s.id = previous.id
+		RETURNING previous.enabled AND NOT s.enabled
+	`
+	var disabled bool
+	err := r.db.QueryRowContext(ctx, query, id, disableAfter, reason).Scan(&disabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, webhook.ErrNotFound
+	}
+	return disabled, err
+}
+
+func (r *webhookRepo) EnqueueDeliveries(ctx context.Context, deliveries []*webhook.Delivery) (int, error) {
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	const columns = 4
+	args := make([]interface{}, 0, len(deliveries)*columns)
+	rows := make([]string, 0, len(deliveries))
+	for i, d := range deliveries {
+		rows = append(rows, insertPlaceholders(i*columns+1, columns))
+		args = append(args, d.SubscriptionID, d.EventKey, d.EventType, d.Payload)
+	}
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_key, event_type, payload) VALUES ` +
+		strings.Join(rows, ", ") + ` ON CONFLICT (subscription_id, event_key) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	inserted, err := result.RowsAffected()
+	return int(inserted), err
+}
+
+func (r *webhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.enabled
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebhookDeliveries(rows)
+}
+
+func (r *webhookRepo) RecordDeliveryAttempt(ctx context.Context, id int64, status webhook.DeliveryStatus, statusCode int, lastError string, retryIn time.Duration) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4,
+			next_attempt_at = NOW() + make_interval(secs => $5),
+			delivered_at = CASE WHEN $2 = 'succeeded' THEN NOW() ELSE delivered_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, id, string(status), statusCode, lastError, retryIn.Seconds())
+	if err != nil {
+		return err
+	}
+	return requireWebhookRow(result, webhook.ErrDeliveryNotFound)
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, id int64) (*webhook.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+// ListDeliveries returns deliveries newest first; an empty status lists every delivery
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, status webhook.DeliveryStatus, limit, offset int) ([]*webhook.Delivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, string(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebhookDeliveries(rows)
+}
+
+func (r *webhookRepo) CountDeliveries(ctx context.Context, subscriptionID int64, status webhook.DeliveryStatus) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2)`,
+		subscriptionID, string(status),
+	).Scan(&count)
+	return count, err
+}
+
+func (r *webhookRepo) RetryDelivery(ctx context.Context, id int64) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return requireWebhookRow(result, webhook.ErrDeliveryNotFound)
+}
+
+func (r *webhookRepo) DeleteFinishedDeliveries(ctx context.Context, maxAge time.Duration) (int64, error) {
+	query := `
+		DELETE FROM webhook_deliveries
+		WHERE status IN ('succeeded', 'failed') AND updated_at < NOW() - make_interval(secs => $1)
+	`
+	result, err := r.db.ExecContext(ctx, query, maxAge.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func marshalWebhookFilters(s *webhook.Subscription) (string, string, string) {
+	eventTypes, senders, tags := s.EventTypes, s.Senders, s.Tags
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	if senders == nil {
+		senders = []string{}
+	}
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	eventTypesJSON, _ := json.Marshal(eventTypes)
+	sendersJSON, _ := json.Marshal(senders)
+	tagsJSON, _ := json.Marshal(tags)
+	return string(eventTypesJSON), string(sendersJSON), string(tagsJSON)
+}
+
+func scanWebhookSubscription(row rowScanner) (*webhook.Subscription, error) {
+	s := &webhook.Subscription{}
+	var eventTypes, senders, tags []byte
+	err := row.Scan(&s.ID, &s.Name, &s.URL, &s.Secret, &eventTypes, &senders, &tags, &s.Enabled,
+		&s.ConsecutiveFailures, &s.DisabledReason, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &s.EventTypes); err != nil {
+		return nil, fmt.Errorf("webhook %d event_types: %w", s.ID, err)
+	}
+	if err := json.Unmarshal(senders, &s.Senders); err != nil {
+		return nil, fmt.Errorf("webhook %d senders: %w", s.ID, err)
+	}
+	if err := json.Unmarshal(tags, &s.Tags); err != nil {
+		return nil, fmt.Errorf("webhook %d tags: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]*webhook.Delivery, error) {
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		d := &webhook.Delivery{}
+		var status string
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventKey, &d.EventType, &d.Payload, &status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &deliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		d.Status = webhook.DeliveryStatus(status)
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func requireWebhookRow(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
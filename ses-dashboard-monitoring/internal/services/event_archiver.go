@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"ses-monitoring/internal/infrastructure/blobstore"
+)
+
+const (
+	defaultArchivePrefix        = "ses-events"
+	defaultArchiveFlushInterval = time.Minute
+	defaultArchiveMaxBatch      = 1000
+	// archiveBufferFactor bounds how many batches may pile up while the store is unavailable
+	archiveBufferFactor = 10
+)
+
+// ArchiveRecord is one raw message as received, stored as a line of NDJSON.
+// Source is the SES delivery channel or, for other providers, the provider name.
+type ArchiveRecord struct {
+	ReceivedAt   time.Time `json:"received_at"`
+	Source       string    `json:"source"`
+	SNSMessageID string    `json:"sns_message_id,omitempty"`
+	TopicArn     string    `json:"topic_arn,omitempty"`
+	Message      string    `json:"message"`
+}
+
+type EventArchiverConfig struct {
+	Prefix        string
+	FlushInterval time.Duration
+	MaxBatch      int
+}
+
+// EventArchiver collects raw SES messages and writes them to the object store
+// as gzip compressed NDJSON, partitioned by the UTC date and hour they arrived:
+//
+//	<prefix>/dt=2006-01-02/hour=15/<timestamp>-<random>.ndjson.gz
+type EventArchiver struct {
+	store blobstore.Store
+	cfg   EventArchiverConfig
+
+	mu      sync.Mutex
+	buffer  []ArchiveRecord
+	dropped int64
+
+	flushMu  sync.Mutex
+	flushReq chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewEventArchiver(store blobstore.Store, cfg EventArchiverConfig) *EventArchiver {
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultArchivePrefix
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultArchiveFlushInterval
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = defaultArchiveMaxBatch
+	}
+	return &EventArchiver{
+		store:    store,
+		cfg:      cfg,
+		flushReq: make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Archive buffers a record for the next flush. It never blocks on the store.
+func (a *EventArchiver) Archive(record ArchiveRecord) {
+	if record.ReceivedAt.IsZero() {
+		record.ReceivedAt = time.Now().UTC()
+	}
+
+	a.mu.Lock()
+	a.buffer = append(a.buffer, record)
+	if overflow := len(a.buffer) - a.cfg.MaxBatch*archiveBufferFactor; overflow > 0 {
+		a.buffer = a.buffer[overflow:]
+		a.dropped += int64(overflow)
+		log.Printf("Event archive buffer full, dropped %d oldest records (%d total)", overflow, a.dropped)
+	}
+	full := len(a.buffer) >= a.cfg.MaxBatch
+	a.mu.Unlock()
+
+	if full {
+		select {
+		case a.flushReq <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start flushes periodically, and early whenever a full batch is buffered
+func (a *EventArchiver) Start() {
+	go func() {
+		defer close(a.stopped)
+
+		ticker := time.NewTicker(a.cfg.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-a.stop:
+				return
+			case <-ticker.C:
+			case <-a.flushReq:
+			}
+			if err := a.Flush(context.Background()); err != nil {
+				log.Printf("Event archive flush failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Shutdown stops the flush loop and writes whatever is still buffered
+func (a *EventArchiver) Shutdown(ctx context.Context) error {
+	a.stopOnce.Do(func() { close(a.stop) })
+	select {
+	case <-a.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.Flush(ctx)
+}
+
+// Flush writes the buffered records, one object per partition. Records of a
+// partition that fails to write go back into the buffer for the next flush.
+func (a *EventArchiver) Flush(ctx context.Context) error {
+	a.flushMu.Lock()
+	defer a.flushMu.Unlock()
+
+	a.mu.Lock()
+	records := a.buffer
+	a.buffer = nil
+	a.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	partitions := make(map[string][]ArchiveRecord)
+	var order []string
+	for _, record := range records {
+		partition := ArchivePartitionPrefix(a.cfg.Prefix, record.ReceivedAt) +
+			fmt.Sprintf("hour=%02d/", record.ReceivedAt.UTC().Hour())
+		if _, ok := partitions[partition]; !ok {
+			order = append(order, partition)
+		}
+		partitions[partition] = append(partitions[partition], record)
+	}
+
+	var failed []ArchiveRecord
+	var firstErr error
+	for _, partition := range order {
+		if err := a.writePartition(ctx, partition, partitions[partition]); err != nil {
+			failed = append(failed, partitions[partition]...)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		a.mu.Lock()
+		a.buffer = append(failed, a.buffer...)
+		a.mu.Unlock()
+	}
+	return firstErr
+}
+
+func (a *EventArchiver) writePartition(ctx context.Context, partition string, records []ArchiveRecord) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	key := partition + time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(suffix) + ".ndjson.gz"
+
+	return a.store.Put(ctx, key, bytes.NewReader(buf.Bytes()))
+}
+
+// ArchivePartitionPrefix is the key prefix of all archive objects for the UTC day of t
+func ArchivePartitionPrefix(prefix string, t time.Time) string {
+	return strings.Trim(prefix, "/") + "/dt=" + t.UTC().Format("2006-01-02") + "/"
+}
+
+// ReadArchiveObject streams the records of one archive object to fn
+func ReadArchiveObject(ctx context.Context, store blobstore.Store, key string, fn func(ArchiveRecord) error) error {
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record ArchiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("%s line %d: %w", key, line, err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// EventBroadcaster wakes up live event streams when events were stored. It
+// only signals that something new exists; streams read the events from the
+// database themselves, so a slow client never holds up ingestion and a
+// reconnecting client catches up from the same query.
+type EventBroadcaster struct {
+	mu     sync.Mutex
+	subs   map[chan struct{}]struct{}
+	closed bool
+	done   chan struct{}
+}
+
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{
+		subs: make(map[chan struct{}]struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Notify is registered as a stored events hook. Wake-ups of a subscriber that
+// has not caught up yet are merged, so it never blocks.
+func (b *EventBroadcaster) Notify(ctx context.Context, events []*sesevent.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives a value after events were stored
+// and a function that ends the subscription
+func (b *EventBroadcaster) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	if !b.closed {
+		b.subs[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+	}
+}
+
+// Done is closed when the broadcaster shuts down
+func (b *EventBroadcaster) Done() <-chan struct{} {
+	return b.done
+}
+
+// Close ends every stream, so the HTTP server does not wait for them when
+// it shuts down
+func (b *EventBroadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	b.subs = make(map[chan struct{}]struct{})
+	close(b.done)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+
+	"ses-monitoring/internal/domain/exportjob"
+	"ses-monitoring/internal/infrastructure/blobstore"
+)
+
+const (
+	exportCleanupInterval = time.Hour
+	exportCleanupBatch    = 100
+)
+
+// ExportCleanupService deletes the files of export jobs once they expire.
+// The jobs stay listed as expired.
+type ExportCleanupService struct {
+	repo  exportjob.Repository
+	store blobstore.Store
+}
+
+func NewExportCleanupService(repo exportjob.Repository, store blobstore.Store) *ExportCleanupService {
+	return &ExportCleanupService{repo: repo, store: store}
+}
+
+// StartCleanupScheduler deletes expired export files every hour until ctx is
+// canceled
+func (s *ExportCleanupService) StartCleanupScheduler(ctx context.Context) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	// First cleanup one minute after startup
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(1 * time.Minute):
+			s.RunCleanup(ctx)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			go s.RunCleanup(ctx)
+		}
+	}
+}
+
+// RunCleanup deletes the files of every export that expired by now
+func (s *ExportCleanupService) RunCleanup(ctx context.Context) {
+	deleted := 0
+	for {
+		jobs, err := s.repo.ListExpired(ctx, time.Now(), exportCleanupBatch)
+		if err != nil {
+			log.Printf("Failed to list expired exports: %v", err)
+			return
+		}
+
+		for _, job := range jobs {
+			if err := s.store.Delete(ctx, job.ArtifactKey); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				// Leave the job as it is so the next run tries again
+				log.Printf("Failed to delete export %d file %s: %v", job.ID, job.ArtifactKey, err)
+				return
+			}
+			if err := s.repo.MarkExpired(ctx, job.ID); err != nil {
+				log.Printf("Failed to mark export %d expired: %v", job.ID, err)
+				return
+			}
+			deleted++
+		}
+
+		if len(jobs) < exportCleanupBatch {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		log.Printf("Export cleanup completed: %d expired export files deleted", deleted)
+	}
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ses-monitoring/internal/domain/exportjob"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/blobstore"
+	"ses-monitoring/internal/infrastructure/export"
+)
+
+const (
+	defaultExportWorkers   = 1
+	defaultExportPrefix    = "exports"
+	defaultExportRetention = 24 * time.Hour
+
+	exportPollInterval = 5 * time.Second
+	// exportLease is how long a job stays claimed without progress before
+	// another worker takes it over
+	exportLease = 2 * time.Minute
+	// exportProgressInterval is how often the rows written are recorded and
+	// the lease is extended
+	exportProgressInterval = 5 * time.Second
+)
+
+// errExportCanceled stops a job that was canceled while running
+var errExportCanceled = errors.New("export canceled")
+
+type ExportWorkerConfig struct {
+	Workers int
+	// Prefix is the object store prefix the files are written under
+	Prefix string
+	// Retention is how long a finished file can be downloaded
+	Retention time.Duration
+}
+
+// ExportWorker runs queued export jobs. Each job streams the filtered events
+// from a database cursor through the export writer and gzip straight into the
+// object store, so neither memory nor local disk grow with the export:
+//
+//	<prefix>/<job id>/<attempt>/events-<timestamp>.<format>[.gz]
+//
+// Each attempt writes its own key, so a worker that lost the job to another
+// one only ever deletes its own file. XLSX files are zip archives already and
+// are stored as they are.
+type ExportWorker struct {
+	repo   exportjob.Repository
+	events sesevent.Repository
+	store  blobstore.Store
+	cfg    ExportWorkerConfig
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewExportWorker(repo exportjob.Repository, events sesevent.Repository, store blobstore.Store, cfg ExportWorkerConfig) *ExportWorker {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultExportWorkers
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultExportPrefix
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultExportRetention
+	}
+	return &ExportWorker{
+		repo:   repo,
+		events: events,
+		store:  store,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (w *ExportWorker) Start() {
+	for i := 0; i < w.cfg.Workers; i++ {
+		w.wg.Add(1)
+		go w.run()
+	}
+}
+
+// Shutdown stops the workers. Jobs in flight are abandoned and picked up
+// again by the next worker once their lease runs out.
+func (w *ExportWorker) Shutdown(ctx context.Context) error {
+	close(w.stop)
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *ExportWorker) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// Keep claiming while jobs are queued
+		for w.runNext() {
+			select {
+			case <-w.stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// runNext claims and runs one job; it returns false when none was queued
+func (w *ExportWorker) runNext() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	job, err := w.repo.Claim(ctx, exportLease)
+	cancel()
+	if err != nil {
+		log.Printf("Failed to claim export job: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	log.Printf("Starting export job %d (%s)", job.ID, job.Format)
+	err = w.runJob(job)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	switch {
+	case err == nil:
+		log.Printf("Export job %d finished: %d events, %d bytes", job.ID, job.RowsWritten, job.ArtifactSize)
+	case errors.Is(err, errExportCanceled):
+		log.Printf("Export job %d attempt %d canceled or taken over", job.ID, job.Attempt)
+	case w.stopping():
+		log.Printf("Export job %d interrupted by shutdown, it restarts when its lease runs out", job.ID)
+	default:
+		log.Printf("Export job %d failed: %v", job.ID, err)
+		if err := w.repo.Fail(ctx, job.ID, job.Attempt, err.Error()); err != nil {
+			log.Printf("Failed to record export job %d failure: %v", job.ID, err)
+		}
+	}
+	return true
+}
+
+func (w *ExportWorker) stopping() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *ExportWorker) runJob(job *exportjob.Job) error {
+	// Stops the export when the job is canceled or the worker shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var rows atomic.Int64
+	var canceled atomic.Bool
+	go w.heartbeat(ctx, cancel, job, &rows, &canceled)
+
+	columns, err := export.ParseColumns(job.Columns)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		return err
+	}
+
+	if estimate, err := w.events.EstimateEventCount(ctx, job.Filter); err == nil {
+		if err := w.repo.SetEstimate(ctx, job.ID, job.Attempt, int64(estimate)); err != nil {
+			log.Printf("Failed to record export job %d estimate: %v", job.ID, err)
+		}
+	}
+
+	compress := job.Format != export.FormatXLSX
+	fileName := fmt.Sprintf("events-%s.%s", job.CreatedAt.In(loc).Format("20060102-150405"), job.Format)
+	if compress {
+		fileName += ".gz"
+	}
+	key := fmt.Sprintf("%s/%d/%d/%s", w.cfg.Prefix, job.ID, job.Attempt, fileName)
+
+	// The store reads the file while it is being written
+	reader, writer := io.Pipe()
+	counter := &countingReader{r: reader}
+	stored := make(chan error, 1)
+	go func() {
+		err := w.store.Put(ctx, key, counter)
+		// Unblocks the writer if the store gave up early
+		reader.CloseWithError(err)
+		stored <- err
+	}()
+
+	err = w.writeFile(ctx, job, writer, compress, columns, loc, &rows)
+	writer.CloseWithError(err)
+	storeErr := <-stored
+	if err == nil {
+		err = storeErr
+	}
+	if canceled.Load() {
+		return errExportCanceled
+	}
+	if err != nil {
+		// A failed write also fails the upload, so no partial file is left
+		return err
+	}
+
+	expiresAt := time.Now().Add(w.cfg.Retention)
+	job.RowsWritten = rows.Load()
+	job.ArtifactKey = key
+	job.ArtifactSize = counter.n
+	job.FileName = fileName
+	job.ExpiresAt = &expiresAt
+
+	completeCtx, completeCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer completeCancel()
+	completed, err := w.repo.Complete(completeCtx, job)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		// Canceled or taken over by another worker just before the end; the
+		// key belongs to this attempt only
+		w.store.Delete(completeCtx, key)
+		return errExportCanceled
+	}
+	return nil
+}
+
+// heartbeat records the rows written and extends the lease of a running
+// job until ctx ends. It cancels the export when the job was canceled or
+// claimed by another worker, or the worker shuts down.
+func (w *ExportWorker) heartbeat(ctx context.Context, cancel context.CancelFunc, job *exportjob.Job, rows *atomic.Int64, canceled *atomic.Bool) {
+	ticker := time.NewTicker(exportProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		running, err := w.repo.UpdateProgress(ctx, job.ID, job.Attempt, rows.Load(), exportLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to record export job %d progress: %v", job.ID, err)
+			}
+			continue
+		}
+		if !running {
+			canceled.Store(true)
+			cancel()
+			return
+		}
+	}
+}
+
+// writeFile writes the export into out, counting the events written in rows
+func (w *ExportWorker) writeFile(ctx context.Context, job *exportjob.Job, out io.Writer, compress bool, columns []export.Column, loc *time.Location, rows *atomic.Int64) error {
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+	writer, err := export.NewWriter(job.Format, out, columns, loc)
+	if err != nil {
+		return err
+	}
+
+	err = w.events.ForEachEvent(ctx, job.Filter, func(e *sesevent.Event) error {
+		if err := writer.Write(e); err != nil {
+			return err
+		}
+		rows.Add(1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
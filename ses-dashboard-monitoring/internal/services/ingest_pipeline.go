@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when the queue cannot take the events; callers should ask the sender to retry
+	ErrQueueFull = errors.New("ingest queue is full")
+	// ErrPipelineClosed is returned by Enqueue once shutdown has started
+	ErrPipelineClosed = errors.New("ingest pipeline is shutting down")
+)
+
+const (
+	defaultIngestQueueSize     = 10000
+	defaultIngestWorkers       = 4
+	defaultIngestBatchSize     = 200
+	defaultIngestFlushInterval = 500 * time.Millisecond
+	ingestWriteAttempts        = 3
+	ingestWriteTimeout         = 30 * time.Second
+)
+
+// EventBatchWriter stores a batch of events and returns how many were new
+type EventBatchWriter interface {
+	StoreEvents(ctx context.Context, events []*sesevent.Event) (int, error)
+}
+
+type IngestPipelineConfig struct {
+	QueueSize     int
+	Workers       int
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// IngestStats describes the pipeline for sizing and monitoring
+type IngestStats struct {
+	QueueDepth         int     `json:"queue_depth"`
+	QueueCapacity      int     `json:"queue_capacity"`
+	Workers            int     `json:"workers"`
+	BatchSize          int     `json:"batch_size"`
+	FlushIntervalMs    int64   `json:"flush_interval_ms"`
+	Accepted           int64   `json:"accepted"`
+	Rejected           int64   `json:"rejected"`
+	Written            int64   `json:"written"`
+	Duplicates         int64   `json:"duplicates"`
+	Failed             int64   `json:"failed"`
+	Batches            int64   `json:"batches"`
+	LastBatchSize      int64   `json:"last_batch_size"`
+	LastBatchLatencyMs float64 `json:"last_batch_latency_ms"`
+	AvgBatchLatencyMs  float64 `json:"avg_batch_latency_ms"`
+	MaxBatchLatencyMs  float64 `json:"max_batch_latency_ms"`
+}
+
+// FailedBatchHandler receives a batch the pipeline gave up writing
+type FailedBatchHandler func(events []*sesevent.Event, err error)
+
+// IngestPipeline buffers incoming events in a bounded queue and writes them
+// to the database in batches from a pool of workers
+type IngestPipeline struct {
+	writer    EventBatchWriter
+	cfg       IngestPipelineConfig
+	queue     chan *sesevent.Event
+	onFailure FailedBatchHandler
+
+	mu      sync.Mutex
+	closed  bool
+	started bool
+	wg      sync.WaitGroup
+
+	accepted      atomic.Int64
+	rejected      atomic.Int64
+	written       atomic.Int64
+	duplicates    atomic.Int64
+	failed        atomic.Int64
+	batches       atomic.Int64
+	lastBatchSize atomic.Int64
+	lastLatency   atomic.Int64
+	totalLatency  atomic.Int64
+	maxLatency    atomic.Int64
+}
+
+func NewIngestPipeline(writer EventBatchWriter, cfg IngestPipelineConfig) *IngestPipeline {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultIngestQueueSize
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultIngestWorkers
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultIngestBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultIngestFlushInterval
+	}
+	return &IngestPipeline{
+		writer: writer,
+		cfg:    cfg,
+		queue:  make(chan *sesevent.Event, cfg.QueueSize),
+	}
+}
+
+// SetFailureHandler registers where batches go once every write attempt has
+// failed. It must be called before Start.
+func (p *IngestPipeline) SetFailureHandler(handler FailedBatchHandler) {
+	p.onFailure = handler
+}
+
+// Start launches the workers
+func (p *IngestPipeline) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return
+	}
+	p.started = true
+
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	log.Printf("Ingest pipeline started: %d workers, queue %d, batch %d", p.cfg.Workers, p.cfg.QueueSize, p.cfg.BatchSize)
+}
+
+// Enqueue accepts all events or none of them. Events from one SES message are
+// never split, so a rejected message can be redelivered as a whole.
+func (p *IngestPipeline) Enqueue(events []*sesevent.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPipelineClosed
+	}
+	// Only enqueuers add to the queue and they hold the lock, so free space can only grow here
+	if len(p.queue)+len(events) > cap(p.queue) {
+		p.rejected.Add(int64(len(events)))
+		return ErrQueueFull
+	}
+	for _, event := range events {
+		p.queue <- event
+	}
+	p.accepted.Add(int64(len(events)))
+	return nil
+}
+
+// Shutdown stops accepting events and waits until everything queued is written
+func (p *IngestPipeline) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	started := p.started
+	p.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("Ingest pipeline drained")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *IngestPipeline) Stats() IngestStats {
+	batches := p.batches.Load()
+	stats := IngestStats{
+		QueueDepth:         len(p.queue),
+		QueueCapacity:      cap(p.queue),
+		Workers:            p.cfg.Workers,
+		BatchSize:          p.cfg.BatchSize,
+		FlushIntervalMs:    p.cfg.FlushInterval.Milliseconds(),
+		Accepted:           p.accepted.Load(),
+		Rejected:           p.rejected.Load(),
+		Written:            p.written.Load(),
+		Duplicates:         p.duplicates.Load(),
+		Failed:             p.failed.Load(),
+		Batches:            batches,
+		LastBatchSize:      p.lastBatchSize.Load(),
+		LastBatchLatencyMs: durationMs(p.lastLatency.Load()),
+		MaxBatchLatencyMs:  durationMs(p.maxLatency.Load()),
+	}
+	if batches > 0 {
+		stats.AvgBatchLatencyMs = durationMs(p.totalLatency.Load() / batches)
+	}
+	return stats
+}
+
+func (p *IngestPipeline) worker() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*sesevent.Event, 0, p.cfg.BatchSize)
+	for {
+		select {
+		case event, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= p.cfg.BatchSize {
+				p.flush(batch)
+				batch = make([]*sesevent.Event, 0, p.cfg.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = make([]*sesevent.Event, 0, p.cfg.BatchSize)
+			}
+		}
+	}
+}
+
+func (p *IngestPipeline) flush(batch []*sesevent.Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	started := time.Now()
+	var (
+		stored int
+		err    error
+	)
+	for attempt := 1; attempt <= ingestWriteAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), ingestWriteTimeout)
+		stored, err = p.writer.StoreEvents(ctx, batch)
+		cancel()
+		if err == nil {
+			break
+		}
+		log.Printf("Ingest batch of %d events failed (attempt %d/%d): %v", len(batch), attempt, ingestWriteAttempts, err)
+		if attempt < ingestWriteAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	latency := time.Since(started)
+
+	p.batches.Add(1)
+	p.lastBatchSize.Store(int64(len(batch)))
+	p.lastLatency.Store(int64(latency))
+	p.totalLatency.Add(int64(latency))
+	for {
+		current := p.maxLatency.Load()
+		if int64(latency) <= current || p.maxLatency.CompareAndSwap(current, int64(latency)) {
+			break
+		}
+	}
+
+	if err != nil {
+		p.failed.Add(int64(len(batch)))
+		if p.onFailure != nil {
+			p.onFailure(batch, err)
+			return
+		}
+		log.Printf("Dropping ingest batch of %d events after %d attempts: %v", len(batch), ingestWriteAttempts, err)
+		return
+	}
+	p.written.Add(int64(stored))
+	p.duplicates.Add(int64(len(batch) - stored))
+}
+
+func durationMs(nanos int64) float64 {
+	return float64(nanos) / float64(time.Millisecond)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"ses-monitoring/internal/domain/outbox"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/eventsink"
+)
+
+const (
+	defaultOutboxBatchSize = 500
+	defaultOutboxTimeout   = 30 * time.Second
+
+	outboxPollInterval   = 500 * time.Millisecond
+	outboxInitialBackoff = time.Second
+	outboxMaxBackoff     = time.Minute
+)
+
+type OutboxRelayConfig struct {
+	// BatchSize is how many events are published per broker round trip
+	BatchSize int
+	// Timeout bounds one publish, including the broker acknowledgements
+	Timeout time.Duration
+}
+
+// OutboxRelay publishes the event outbox to the event sinks. Every sink has
+// its own entries and goroutine, so a broker that is down only delays its own
+// events. Entries are removed after the broker acknowledged them and retried
+// until then, which makes delivery at-least-once.
+type OutboxRelay struct {
+	repo   outbox.Repository
+	events sesevent.Repository
+	sinks  []eventsink.Sink
+	topics eventsink.Topics
+	cfg    OutboxRelayConfig
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewOutboxRelay(repo outbox.Repository, events sesevent.Repository, sinks []eventsink.Sink, topics eventsink.Topics, cfg OutboxRelayConfig) *OutboxRelay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultOutboxBatchSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultOutboxTimeout
+	}
+	return &OutboxRelay{
+		repo:   repo,
+		events: events,
+		sinks:  sinks,
+		topics: topics,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (r *OutboxRelay) Start() {
+	for _, sink := range r.sinks {
+		r.wg.Add(1)
+		go r.run(sink)
+	}
+}
+
+// Shutdown stops the relay after the batches in flight and closes the sinks.
+// Entries that were not acknowledged stay in the outbox for the next start.
+func (r *OutboxRelay) Shutdown(ctx context.Context) error {
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for _, sink := range r.sinks {
+		if err := sink.Close(); err != nil {
+			log.Printf("Failed to close %s event sink: %v", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRelay) run(sink eventsink.Sink) {
+	defer r.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-timer.C:
+		}
+
+		wait := outboxPollInterval
+		if r.relayBatch(sink) {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// relayBatch publishes the next due entries of a sink. It reports whether a
+// full batch was published, in which case more entries are likely waiting.
+func (r *OutboxRelay) relayBatch(sink eventsink.Sink) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout+time.Minute)
+	defer cancel()
+
+	// The lease outlasts the publish, so a crashed instance's entries become
+	// due again instead of being lost
+	entries, err := r.repo.Claim(ctx, sink.Name(), r.cfg.BatchSize, r.cfg.Timeout+time.Minute)
+	if err != nil {
+		log.Printf("Failed to claim %s outbox entries: %v", sink.Name(), err)
+		return false
+	}
+	if len(entries) == 0 {
+		return false
+	}
+
+	eventIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		eventIDs = append(eventIDs, entry.EventID)
+	}
+	events, err := r.events.GetEventsByIDs(ctx, eventIDs)
+	if err != nil {
+		log.Printf("Failed to load events for %s outbox: %v", sink.Name(), err)
+		return false
+	}
+	byID := make(map[int64]*sesevent.Event, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+
+	ids := make([]int64, 0, len(entries))
+	messages := make([]eventsink.Message, 0, len(entries))
+	attempts := 0
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+		if entry.Attempts > attempts {
+			attempts = entry.Attempts
+		}
+		e, ok := byID[entry.EventID]
+		if !ok {
+			// Deleted by retention cleanup in the meantime
+			continue
+		}
+		message, err := eventsink.NewMessage(r.topics.For(e), e)
+		if err != nil {
+			log.Printf("Failed to encode event %s for %s: %v", e.EventKey, sink.Name(), err)
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	publishCtx, cancelPublish := context.WithTimeout(ctx, r.cfg.Timeout)
+	err = sink.Publish(publishCtx, messages)
+	cancelPublish()
+	if err != nil {
+		retryIn := outboxBackoff(attempts + 1)
+		log.Printf("Failed to publish %d events to %s, retrying in %s: %v", len(messages), sink.Name(), retryIn, err)
+		if err := r.repo.RecordFailure(ctx, ids, err.Error(), retryIn); err != nil {
+			log.Printf("Failed to record %s outbox failure: %v", sink.Name(), err)
+		}
+		return false
+	}
+
+	if err := r.repo.Delete(ctx, ids); err != nil {
+		// The entries become due again after the lease and are published twice
+		log.Printf("Failed to remove published %s outbox entries: %v", sink.Name(), err)
+		return false
+	}
+	return len(entries) >= r.cfg.BatchSize
+}
+
+// outboxBackoff doubles the wait after every failed attempt. It stays short
+// so events reach a broker soon after it recovers.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxInitialBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
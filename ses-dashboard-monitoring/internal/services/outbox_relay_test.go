@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"ses-monitoring/internal/domain/outbox"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/eventsink"
+)
+
+// fakeOutboxRepo keeps outbox entries in memory. Claimed entries are not due
+// again until they fail or their lease is dropped with expireLeases.
+type fakeOutboxRepo struct {
+	mu       sync.Mutex
+	entries  map[int64]*outbox.Entry
+	claimed  map[int64]bool
+	failures []time.Duration
+}
+
+func newFakeOutboxRepo(sink string, eventIDs ...int64) *fakeOutboxRepo {
+	r := &fakeOutboxRepo{entries: map[int64]*outbox.Entry{}, claimed: map[int64]bool{}}
+	for i, eventID := range eventIDs {
+		id := int64(i + 1)
+		r.entries[id] = &outbox.Entry{ID: id, EventID: eventID, Sink: sink}
+	}
+	return r
+}
+
+func (r *fakeOutboxRepo) Claim(ctx context.Context, sink string, limit int, lease time.Duration) ([]*outbox.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []int64
+	for id, entry := range r.entries {
+		if entry.Sink == sink && !r.claimed[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var claimed []*outbox.Entry
+	for _, id := range ids {
+		if len(claimed) == limit {
+			break
+		}
+		r.claimed[id] = true
+		entry := *r.entries[id]
+		claimed = append(claimed, &entry)
+	}
+	return claimed, nil
+}
+
+func (r *fakeOutboxRepo) Delete(ctx context.Context, ids []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		delete(r.entries, id)
+		delete(r.claimed, id)
+	}
+	return nil
+}
+
+func (r *fakeOutboxRepo) RecordFailure(ctx context.Context, ids []int64, lastError string, retryIn time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, retryIn)
+	for _, id := range ids {
+		r.entries[id].Attempts++
+		r.entries[id].LastError = lastError
+		// Due again; the test does not wait for retryIn
+		delete(r.claimed, id)
+	}
+	return nil
+}
+
+func (r *fakeOutboxRepo) Stats(ctx context.Context) ([]*outbox.SinkStats, error) {
+	return nil, nil
+}
+
+func (r *fakeOutboxRepo) pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// fakeEventStore serves the events the outbox entries point to
+type fakeEventStore struct {
+	sesevent.Repository
+	events map[int64]*sesevent.Event
+}
+
+func (s *fakeEventStore) GetEventsByIDs(ctx context.Context, ids []int64) ([]*sesevent.Event, error) {
+	var events []*sesevent.Event
+	for _, id := range ids {
+		if e, ok := s.events[id]; ok {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// fakeSink fails the first failures publishes and records the rest
+type fakeSink struct {
+	failures int
+
+	mu        sync.Mutex
+	calls     int
+	published []eventsink.Message
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Publish(ctx context.Context, messages []eventsink.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("broker unavailable")
+	}
+	s.published = append(s.published, messages...)
+	return nil
+}
+
+func (s *fakeSink) Close() error { return nil }
+
+func outboxTestEvents(ids ...int64) *fakeEventStore {
+	store := &fakeEventStore{events: map[int64]*sesevent.Event{}}
+	for _, id := range ids {
+		store.events[id] = &sesevent.Event{
+			ID:        id,
+			MessageID: "message-1",
+			EventKey:  "key-" + string(rune('a'+id)),
+			EventType: "DeliveryDelay",
+			Provider:  sesevent.ProviderSES,
+		}
+	}
+	return store
+}
+
+func newTestRelay(repo outbox.Repository, events sesevent.Repository, sink eventsink.Sink, batchSize int) *OutboxRelay {
+	topics, _ := eventsink.ParseTopics("", "")
+	return NewOutboxRelay(repo, events, []eventsink.Sink{sink}, topics, OutboxRelayConfig{BatchSize: batchSize})
+}
+
+func TestOutboxRelayRemovesPublishedEntries(t *testing.T) {
+	repo := newFakeOutboxRepo("fake", 1, 2, 3)
+	sink := &fakeSink{}
+	relay := newTestRelay(repo, outboxTestEvents(1, 2, 3), sink, 2)
+
+	// A full batch reports that more entries are likely waiting
+	if more := relay.relayBatch(sink); !more {
+		t.Error("relayBatch() after a full batch = false, want true")
+	}
+	if more := relay.relayBatch(sink); more {
+		t.Error("relayBatch() after a partial batch = true, want false")
+	}
+	if more := relay.relayBatch(sink); more {
+		t.Error("relayBatch() with an empty outbox = true, want false")
+	}
+
+	if n := repo.pending(); n != 0 {
+		t.Fatalf("%d entries left after publishing, want 0", n)
+	}
+	if len(sink.published) != 3 {
+		t.Fatalf("published %d messages, want 3", len(sink.published))
+	}
+	for i, m := range sink.published {
+		if m.Topic != "ses.events.delivery_delay" {
+			t.Errorf("message %d topic = %q", i, m.Topic)
+		}
+		if want := "key-" + string(rune('a'+i+1)); m.ID != want || m.Headers["event-key"] != want {
+			t.Errorf("message %d ID = %q, header = %q, want %q", i, m.ID, m.Headers["event-key"], want)
+		}
+	}
+}
+
+func TestOutboxRelayRetriesFailedPublishes(t *testing.T) {
+	repo := newFakeOutboxRepo("fake", 1, 2)
+	sink := &fakeSink{failures: 3}
+	relay := newTestRelay(repo, outboxTestEvents(1, 2), sink, 10)
+
+	for i := 0; i < 3; i++ {
+		relay.relayBatch(sink)
+		if n := repo.pending(); n != 2 {
+			t.Fatalf("attempt %d: %d entries left, want both kept for a retry", i+1, n)
+		}
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(repo.failures) != len(want) {
+		t.Fatalf("recorded %d failures, want %d", len(repo.failures), len(want))
+	}
+	for i, retryIn := range want {
+		if repo.failures[i] != retryIn {
+			t.Errorf("failure %d retries in %s, want %s", i+1, repo.failures[i], retryIn)
+		}
+	}
+	for _, entry := range repo.entries {
+		if entry.Attempts != 3 || entry.LastError != "broker unavailable" {
+			t.Errorf("entry %d: attempts %d, last error %q", entry.ID, entry.Attempts, entry.LastError)
+		}
+	}
+
+	relay.relayBatch(sink)
+	if n := repo.pending(); n != 0 {
+		t.Fatalf("%d entries left after the broker recovered, want 0", n)
+	}
+	if len(sink.published) != 2 {
+		t.Fatalf("published %d messages, want 2", len(sink.published))
+	}
+}
+
+func TestOutboxRelaySkipsDeletedEvents(t *testing.T) {
+	// Event 2 was removed by the retention cleanup
+	repo := newFakeOutboxRepo("fake", 1, 2)
+	sink := &fakeSink{}
+	relay := newTestRelay(repo, outboxTestEvents(1), sink, 10)
+
+	relay.relayBatch(sink)
+	if n := repo.pending(); n != 0 {
+		t.Fatalf("%d entries left, want the entry of the deleted event removed too", n)
+	}
+	if len(sink.published) != 1 {
+		t.Fatalf("published %d messages, want 1", len(sink.published))
+	}
+}
+
+func TestOutboxRelayRunsUntilShutdown(t *testing.T) {
+	repo := newFakeOutboxRepo("fake", 1, 2, 3)
+	sink := &fakeSink{failures: 1}
+	relay := newTestRelay(repo, outboxTestEvents(1, 2, 3), sink, 10)
+	relay.Start()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for repo.pending() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := relay.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if n := repo.pending(); n != 0 {
+		t.Fatalf("%d entries left, want all published after one failed attempt", n)
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{50, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/infrastructure/aws"
+	"ses-monitoring/internal/usecase"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	defaultSQSWorkers         = 2
+	defaultSQSMaxMessages     = 10
+	defaultSQSWaitTimeSeconds = 20
+	sqsProcessTimeout         = 30 * time.Second
+	sqsErrorBackoff           = 5 * time.Second
+
+	// SQSMaxMessageAge is the longest an SQS queue retains a message. The
+	// consumer's verifier accepts envelopes this old, since a backlog that
+	// waited out downtime is still genuine.
+	SQSMaxMessageAge = 14 * 24 * time.Hour
+)
+
+// SQSAPI is the part of the SQS client the consumer needs
+type SQSAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error)
+}
+
+type SQSConsumerConfig struct {
+	QueueURL        string
+	AllowedTopicARN string
+	Workers         int
+	MaxMessages     int
+	WaitTimeSeconds int
+	// VisibilityTimeoutSeconds overrides the queue default when set. It is
+	// how long a message stays hidden while it is processed, and how long a
+	// message that failed to save waits before it is received again.
+	VisibilityTimeoutSeconds int
+}
+
+// SQSConsumer long-polls an SQS queue subscribed to the SES topic, as an
+// alternative to exposing the SNS webhook. Messages may carry the SNS
+// envelope or, with raw message delivery, the SES notification itself.
+// A message is deleted only once its events are saved or it has been
+// dead-lettered; anything else is left for SQS to redeliver.
+type SQSConsumer struct {
+	client      SQSAPI
+	uc          *usecase.SESUsecase
+	deadLetters *usecase.DeadLetterUsecase
+	archiver    *EventArchiver
+	verifier    *aws.SNSVerifier
+	cfg         SQSConsumerConfig
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewSQSConsumer creates the consumer. A nil verifier disables signature
+// verification and a nil archiver skips the raw event archive. The verifier
+// should accept messages up to SQSMaxMessageAge old; the webhook's one hour
+// window would reject every message that waited in the queue longer.
+func NewSQSConsumer(
+	client SQSAPI,
+	uc *usecase.SESUsecase,
+	deadLetters *usecase.DeadLetterUsecase,
+	archiver *EventArchiver,
+	verifier *aws.SNSVerifier,
+	cfg SQSConsumerConfig,
+) *SQSConsumer {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultSQSWorkers
+	}
+	if cfg.MaxMessages <= 0 || cfg.MaxMessages > 10 {
+		cfg.MaxMessages = defaultSQSMaxMessages
+	}
+	if cfg.WaitTimeSeconds <= 0 || cfg.WaitTimeSeconds > 20 {
+		cfg.WaitTimeSeconds = defaultSQSWaitTimeSeconds
+	}
+	return &SQSConsumer{
+		client:      client,
+		uc:          uc,
+		deadLetters: deadLetters,
+		archiver:    archiver,
+		verifier:    verifier,
+		cfg:         cfg,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start launches the polling workers
+func (s *SQSConsumer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.poll(ctx)
+	}
+	log.Printf("SQS consumer started: %d workers on %s", s.cfg.Workers, s.cfg.QueueURL)
+}
+
+// Shutdown stops receiving and waits for messages in progress to finish
+func (s *SQSConsumer) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("SQS consumer stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *SQSConsumer) poll(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		input := &sqs.ReceiveMessageInput{
+			QueueUrl:            awssdk.String(s.cfg.QueueURL),
+			MaxNumberOfMessages: int32(s.cfg.MaxMessages),
+			WaitTimeSeconds:     int32(s.cfg.WaitTimeSeconds),
+		}
+		if s.cfg.VisibilityTimeoutSeconds > 0 {
+			input.VisibilityTimeout = int32(s.cfg.VisibilityTimeoutSeconds)
+		}
+
+		out, err := s.client.ReceiveMessage(ctx, input)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("SQS receive failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(sqsErrorBackoff):
+			}
+			continue
+		}
+
+		// Received messages are processed to the end even during shutdown,
+		// so nothing is saved without also being deleted
+		var done []types.DeleteMessageBatchRequestEntry
+		for i, message := range out.Messages {
+			if s.process(message) {
+				done = append(done, types.DeleteMessageBatchRequestEntry{
+					Id:            awssdk.String(fmt.Sprintf("%d", i)),
+					ReceiptHandle: message.ReceiptHandle,
+				})
+			}
+		}
+		s.delete(done)
+	}
+}
+
+// process handles one message and reports whether it can be deleted
+func (s *SQSConsumer) process(message types.Message) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), sqsProcessTimeout)
+	defer cancel()
+
+	sqsMessageID := awssdk.ToString(message.MessageId)
+	body := awssdk.ToString(message.Body)
+
+	// SNS envelope unless the subscription uses raw message delivery
+	var envelope aws.SNSMessage
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil || envelope.Type == "" {
+		envelope = aws.SNSMessage{MessageId: sqsMessageID, Message: body}
+	} else {
+		if envelope.Type != "Notification" {
+			// Cross-account subscriptions must be confirmed by hand via the SubscribeURL
+			log.Printf("Ignoring SNS %s message %s from SQS %s", envelope.Type, envelope.MessageId, envelope.SubscribeURL)
+			return true
+		}
+		if s.cfg.AllowedTopicARN != "" && envelope.TopicArn != s.cfg.AllowedTopicARN {
+			log.Printf("Dropping SQS message %s from unexpected topic %s", sqsMessageID, envelope.TopicArn)
+			return true
+		}
+		if s.verifier != nil {
+			if err := s.verifier.Verify(ctx, &envelope); err != nil {
+				return s.deadLetter(ctx, &envelope, deadletter.StageVerify, err)
+			}
+		}
+	}
+
+	if envelope.Message == "" {
+		log.Printf("Dropping empty SQS message %s", sqsMessageID)
+		return true
+	}
+
+	if s.archiver != nil {
+		s.archiver.Archive(ArchiveRecord{
+			Source:       "sqs",
+			SNSMessageID: envelope.MessageId,
+			TopicArn:     envelope.TopicArn,
+			Message:      envelope.Message,
+		})
+	}
+
+	events, err := usecase.ParseSESMessage([]byte(envelope.Message))
+	if err != nil {
+		return s.deadLetter(ctx, &envelope, deadletter.StageParse, err)
+	}
+
+	for _, event := range events {
+		event.SNSMessageID = envelope.MessageId
+	}
+
+	if _, err := s.uc.HandleEvents(ctx, events); err != nil {
+		log.Printf("Failed to store events of SQS message %s, leaving it for redelivery: %v", sqsMessageID, err)
+		return false
+	}
+	return true
+}
+
+// deadLetter keeps a payload that failed verification or cannot be parsed.
+// The message is deleted only if the payload was saved.
+func (s *SQSConsumer) deadLetter(ctx context.Context, envelope *aws.SNSMessage, stage deadletter.Stage, cause error) bool {
+	entry := &deadletter.Entry{
+		Source:    "sqs",
+		MessageID: envelope.MessageId,
+		TopicArn:  envelope.TopicArn,
+		Payload:   envelope.Message,
+		Stage:     stage,
+		Error:     cause.Error(),
+	}
+	if err := s.deadLetters.Record(ctx, entry); err != nil {
+		log.Printf("Failed to dead-letter SQS message %s: %v", envelope.MessageId, err)
+		return false
+	}
+	log.Printf("Dead-lettered SQS message %s: %v", envelope.MessageId, cause)
+	return true
+}
+
+func (s *SQSConsumer) delete(entries []types.DeleteMessageBatchRequestEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqsProcessTimeout)
+	defer cancel()
+
+	out, err := s.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+		QueueUrl: awssdk.String(s.cfg.QueueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		// Deduplication makes the redelivery harmless
+		log.Printf("Failed to delete %d SQS messages: %v", len(entries), err)
+		return
+	}
+	for _, failed := range out.Failed {
+		log.Printf("Failed to delete SQS message: %s", awssdk.ToString(failed.Message))
+	}
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/infrastructure/aws"
+	"ses-monitoring/internal/usecase"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// operations records what the fakes did, in order
+type operations struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (o *operations) add(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.log = append(o.log, fmt.Sprintf(format, args...))
+}
+
+func (o *operations) index(op string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i, logged := range o.log {
+		if logged == op {
+			return i
+		}
+	}
+	return -1
+}
+
+// fakeSQS hands out one batch of messages, then waits like an empty long poll
+type fakeSQS struct {
+	ops      *operations
+	messages []types.Message
+	deleted  chan []types.DeleteMessageBatchRequestEntry
+
+	mu       sync.Mutex
+	received bool
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	first := !f.received
+	f.received = true
+	f.mu.Unlock()
+	if first {
+		return &sqs.ReceiveMessageOutput{Messages: f.messages}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeSQS) DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	for _, entry := range params.Entries {
+		f.ops.add("delete %s", awssdk.ToString(entry.ReceiptHandle))
+	}
+	f.deleted <- params.Entries
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+// fakeEventRepo stores events in memory; saving fails for failMessageID
+type fakeEventRepo struct {
+	sesevent.Repository
+	ops           *operations
+	failMessageID string
+}
+
+func (r *fakeEventRepo) Save(ctx context.Context, event *sesevent.Event) error {
+	if event.MessageID == r.failMessageID {
+		return errors.New("database unavailable")
+	}
+	r.ops.add("save %s", event.MessageID)
+	return nil
+}
+
+// fakeDeadLetterRepo keeps entries in memory; creating fails for failMessageID
+type fakeDeadLetterRepo struct {
+	deadletter.Repository
+	ops           *operations
+	failMessageID string
+
+	mu      sync.Mutex
+	entries []*deadletter.Entry
+}
+
+func (r *fakeDeadLetterRepo) Create(ctx context.Context, entry *deadletter.Entry) error {
+	if entry.MessageID == r.failMessageID {
+		return errors.New("database unavailable")
+	}
+	r.ops.add("dead-letter %s", entry.MessageID)
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+	return nil
+}
+
+func sesDelivery(messageID string) string {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"eventType": "Delivery",
+		"mail": map[string]interface{}{
+			"timestamp":   "2026-03-01T12:00:00.000Z",
+			"messageId":   messageID,
+			"source":      "noreply@example.com",
+			"destination": []string{"jane@example.com"},
+		},
+		"delivery": map[string]interface{}{
+			"timestamp":  "2026-03-01T12:00:02.000Z",
+			"recipients": []string{"jane@example.com"},
+		},
+	})
+	return string(payload)
+}
+
+func snsEnvelope(snsMessageID, message string) string {
+	envelope, _ := json.Marshal(map[string]string{
+		"Type":      "Notification",
+		"MessageId": snsMessageID,
+		"TopicArn":  "arn:aws:sns:us-east-1:123456789012:ses-events",
+		"Message":   message,
+		"Timestamp": "2026-03-01T12:00:03.000Z",
+	})
+	return string(envelope)
+}
+
+func sqsMessage(id, body string) types.Message {
+	return types.Message{
+		MessageId:     awssdk.String(id),
+		ReceiptHandle: awssdk.String("receipt-" + id),
+		Body:          awssdk.String(body),
+	}
+}
+
+func TestSQSConsumer(t *testing.T) {
+	ops := &operations{}
+	client := &fakeSQS{
+		ops: ops,
+		messages: []types.Message{
+			sqsMessage("raw", sesDelivery("ses-raw")),
+			sqsMessage("envelope", snsEnvelope("sns-1", sesDelivery("ses-envelope"))),
+			sqsMessage("store-fails", sesDelivery("ses-store-fails")),
+			sqsMessage("malformed", "not an SES event"),
+			sqsMessage("dead-letter-fails", snsEnvelope("sns-dead-letter-fails", `{"eventType":"Bounce"}`)),
+		},
+		deleted: make(chan []types.DeleteMessageBatchRequestEntry, 1),
+	}
+	events := &fakeEventRepo{ops: ops, failMessageID: "ses-store-fails"}
+	deadLetters := &fakeDeadLetterRepo{ops: ops, failMessageID: "sns-dead-letter-fails"}
+	sesUC := usecase.NewSESUsecase(events)
+
+	consumer := NewSQSConsumer(client, sesUC, usecase.NewDeadLetterUsecase(deadLetters, sesUC), nil, nil, SQSConsumerConfig{
+		QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/ses-events",
+		Workers:  1,
+	})
+	consumer.Start()
+
+	var deleted []types.DeleteMessageBatchRequestEntry
+	select {
+	case deleted = <-client.deleted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no messages were deleted")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := consumer.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	receipts := map[string]bool{}
+	for _, entry := range deleted {
+		receipts[awssdk.ToString(entry.ReceiptHandle)] = true
+	}
+
+	t.Run("deletes saved messages after saving", func(t *testing.T) {
+		for _, c := range []struct{ receipt, messageID string }{
+			{"receipt-raw", "ses-raw"},
+			{"receipt-envelope", "ses-envelope"},
+		} {
+			if !receipts[c.receipt] {
+				t.Errorf("%s was not deleted", c.receipt)
+			}
+			saved, removed := ops.index("save "+c.messageID), ops.index("delete "+c.receipt)
+			if saved < 0 || saved > removed {
+				t.Errorf("%s: save at %d, delete at %d; want save first", c.receipt, saved, removed)
+			}
+		}
+	})
+
+	t.Run("leaves messages that failed to save", func(t *testing.T) {
+		if receipts["receipt-store-fails"] {
+			t.Error("message whose events failed to save was deleted")
+		}
+	})
+
+	t.Run("dead-letters unparseable messages", func(t *testing.T) {
+		if !receipts["receipt-malformed"] {
+			t.Error("dead-lettered message was not deleted")
+		}
+		if ops.index("dead-letter malformed") > ops.index("delete receipt-malformed") {
+			t.Error("message deleted before it was dead-lettered")
+		}
+		if len(deadLetters.entries) != 1 {
+			t.Fatalf("%d dead-letter entries, want 1", len(deadLetters.entries))
+		}
+		entry := deadLetters.entries[0]
+		if entry.Source != "sqs" || entry.Stage != deadletter.StageParse || entry.Payload != "not an SES event" {
+			t.Errorf("dead-letter entry = %+v", entry)
+		}
+	})
+
+	t.Run("leaves messages that failed to dead-letter", func(t *testing.T) {
+		if receipts["receipt-dead-letter-fails"] {
+			t.Error("message that could not be dead-lettered was deleted")
+		}
+	})
+
+	if len(deleted) != 3 {
+		t.Errorf("%d messages deleted, want 3", len(deleted))
+	}
+}
+
+// staticCertFetcher serves the certificate of a test signing key
+type staticCertFetcher struct {
+	cert *x509.Certificate
+}
+
+func (f *staticCertFetcher) Fetch(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	return f.cert, nil
+}
+
+// signedEnvelope returns an SNS notification signed with key (SignatureVersion 2)
+func signedEnvelope(t *testing.T, key *rsa.PrivateKey, snsMessageID, message string, timestamp time.Time) string {
+	t.Helper()
+	envelope := aws.SNSMessage{
+		Type:             "Notification",
+		MessageId:        snsMessageID,
+		TopicArn:         "arn:aws:sns:us-east-1:123456789012:ses-events",
+		Message:          message,
+		Timestamp:        timestamp.UTC().Format(time.RFC3339),
+		SignatureVersion: "2",
+		SigningCertURL:   "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem",
+	}
+	stringToSign := "Message\n" + envelope.Message + "\nMessageId\n" + envelope.MessageId +
+		"\nTimestamp\n" + envelope.Timestamp + "\nTopicArn\n" + envelope.TopicArn + "\nType\n" + envelope.Type + "\n"
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestSQSConsumerVerifiesQueuedMessages(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := aws.NewSNSVerifier(&staticCertFetcher{cert: cert})
+	verifier.SetMaxMessageAge(SQSMaxMessageAge)
+
+	// A backlog that waited out an outage is older than the webhook's window
+	backlogged := signedEnvelope(t, key, "sns-backlogged", sesDelivery("ses-backlogged"), time.Now().Add(-6*time.Hour))
+	var tampered aws.SNSMessage
+	if err := json.Unmarshal([]byte(signedEnvelope(t, key, "sns-tampered", sesDelivery("ses-tampered"), time.Now())), &tampered); err != nil {
+		t.Fatal(err)
+	}
+	tampered.Message = sesDelivery("ses-forged")
+	tamperedBody, _ := json.Marshal(tampered)
+
+	ops := &operations{}
+	client := &fakeSQS{
+		ops: ops,
+		messages: []types.Message{
+			sqsMessage("backlogged", backlogged),
+			sqsMessage("tampered", string(tamperedBody)),
+		},
+		deleted: make(chan []types.DeleteMessageBatchRequestEntry, 1),
+	}
+	events := &fakeEventRepo{ops: ops}
+	deadLetters := &fakeDeadLetterRepo{ops: ops}
+	sesUC := usecase.NewSESUsecase(events)
+
+	consumer := NewSQSConsumer(client, sesUC, usecase.NewDeadLetterUsecase(deadLetters, sesUC), nil, verifier, SQSConsumerConfig{
+		QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/ses-events",
+		Workers:  1,
+	})
+	consumer.Start()
+
+	var deleted []types.DeleteMessageBatchRequestEntry
+	select {
+	case deleted = <-client.deleted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no messages were deleted")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := consumer.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if len(deleted) != 2 {
+		t.Errorf("%d messages deleted, want 2", len(deleted))
+	}
+	if ops.index("save ses-backlogged") < 0 {
+		t.Error("signed message older than an hour was not saved")
+	}
+	if ops.index("save ses-forged") >= 0 {
+		t.Error("message with an invalid signature was saved")
+	}
+	if ops.index("dead-letter sns-tampered") > ops.index("delete receipt-tampered") {
+		t.Error("message with an invalid signature was deleted before it was dead-lettered")
+	}
+	if len(deadLetters.entries) != 1 {
+		t.Fatalf("%d dead-letter entries, want 1", len(deadLetters.entries))
+	}
+	if entry := deadLetters.entries[0]; entry.Stage != deadletter.StageVerify || entry.MessageID != "sns-tampered" {
+		t.Errorf("dead-letter entry = %+v", entry)
+	}
+}
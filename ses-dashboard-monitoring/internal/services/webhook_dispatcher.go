@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"ses-monitoring/internal/domain/webhook"
+)
+
+const (
+	defaultWebhookWorkers              = 4
+	defaultWebhookMaxAttempts          = 8
+	defaultWebhookTimeout              = 10 * time.Second
+	defaultWebhookDisableAfterFailures = 20
+	defaultWebhookRetentionDays        = 30
+
+	webhookPollInterval    = 2 * time.Second
+	webhookInitialBackoff  = 30 * time.Second
+	webhookMaxBackoff      = 6 * time.Hour
+	webhookCleanupInterval = time.Hour
+	// maxWebhookErrorBody is how much of a failed response is kept in the delivery log
+	maxWebhookErrorBody = 512
+)
+
+// Headers sent with every delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription secret.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookEventHeader     = "X-Webhook-Event"
+)
+
+type WebhookDispatcherConfig struct {
+	Workers     int
+	MaxAttempts int
+	Timeout     time.Duration
+	// DisableAfterFailures is how many failed attempts in a row disable a subscription
+	DisableAfterFailures int
+	// RetentionDays is how long finished deliveries stay in the delivery log
+	RetentionDays int
+}
+
+// WebhookDispatcher posts queued deliveries to subscription URLs and retries
+// failures with exponential backoff. Deliveries live in the database, so
+// they survive restarts and several API instances can share the work.
+type WebhookDispatcher struct {
+	repo   webhook.Repository
+	client *http.Client
+	cfg    WebhookDispatcherConfig
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewWebhookDispatcher(repo webhook.Repository, cfg WebhookDispatcherConfig) *WebhookDispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWebhookWorkers
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	if cfg.DisableAfterFailures <= 0 {
+		cfg.DisableAfterFailures = defaultWebhookDisableAfterFailures
+	}
+	if cfg.RetentionDays <= 0 {
+		cfg.RetentionDays = defaultWebhookRetentionDays
+	}
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	d.wg.Add(1)
+	go d.run()
+}
+
+// Shutdown stops claiming deliveries and waits for attempts in flight
+func (d *WebhookDispatcher) Shutdown(ctx context.Context) error {
+	close(d.stop)
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *WebhookDispatcher) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Time{}
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(lastCleanup) >= webhookCleanupInterval {
+			d.cleanup()
+			lastCleanup = time.Now()
+		}
+
+		// Keep claiming while full batches come back so a backlog drains quickly
+		for {
+			claimed := d.dispatchBatch()
+			if claimed < d.cfg.Workers*2 {
+				break
+			}
+			select {
+			case <-d.stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// dispatchBatch claims due deliveries and attempts them concurrently
+func (d *WebhookDispatcher) dispatchBatch() int {
+	// The lease outlasts every attempt in the batch, so a crashed instance's
+	// deliveries become due again instead of being lost
+	lease := 2*d.cfg.Timeout + time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.cfg.Workers*2, lease)
+	cancel()
+	if err != nil {
+		log.Printf("Failed to claim webhook deliveries: %v", err)
+		return 0
+	}
+	if len(deliveries) == 0 {
+		return 0
+	}
+
+	subscriptions := make(map[int64]*webhook.Subscription)
+	var mu sync.Mutex
+	sem := make(chan struct{}, d.cfg.Workers)
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		delivery := delivery
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			mu.Lock()
+			s, ok := subscriptions[delivery.SubscriptionID]
+			mu.Unlock()
+			if !ok {
+				var err error
+				s, err = d.repo.GetSubscription(context.Background(), delivery.SubscriptionID)
+				if err != nil {
+					log.Printf("Failed to load webhook subscription %d: %v", delivery.SubscriptionID, err)
+					return
+				}
+				mu.Lock()
+				subscriptions[delivery.SubscriptionID] = s
+				mu.Unlock()
+			}
+			d.attempt(s, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+// attempt posts one delivery and records the outcome on the delivery and the subscription
+func (d *WebhookDispatcher) attempt(s *webhook.Subscription, delivery *webhook.Delivery) {
+	statusCode, err := d.post(s, delivery)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err == nil {
+		if err := d.repo.RecordDeliveryAttempt(ctx, delivery.ID, webhook.DeliverySucceeded, statusCode, "", 0); err != nil {
+			log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+		}
+		if _, err := d.repo.RecordSubscriptionResult(ctx, s.ID, true, d.cfg.DisableAfterFailures, ""); err != nil {
+			log.Printf("Failed to update webhook subscription %d: %v", s.ID, err)
+		}
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	status := webhook.DeliveryPending
+	retryIn := webhookBackoff(attempts)
+	if attempts >= d.cfg.MaxAttempts {
+		status = webhook.DeliveryFailed
+		retryIn = 0
+	}
+	if recordErr := d.repo.RecordDeliveryAttempt(ctx, delivery.ID, status, statusCode, err.Error(), retryIn); recordErr != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, recordErr)
+	}
+
+	reason := fmt.Sprintf("disabled after %d consecutive failed deliveries, last error: %v", d.cfg.DisableAfterFailures, err)
+	disabled, recordErr := d.repo.RecordSubscriptionResult(ctx, s.ID, false, d.cfg.DisableAfterFailures, reason)
+	if recordErr != nil {
+		log.Printf("Failed to update webhook subscription %d: %v", s.ID, recordErr)
+	}
+	if disabled {
+		log.Printf("Webhook subscription %d (%s) %s", s.ID, s.Name, reason)
+	}
+}
+
+// post sends the delivery and returns the response status code. Any status
+// outside 2xx is an error.
+func (d *WebhookDispatcher) post(s *webhook.Subscription, delivery *webhook.Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ses-monitoring-webhook/1.0")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(s.Secret, timestamp, body))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookErrorBody))
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	deleted, err := d.repo.DeleteFinishedDeliveries(ctx, time.Duration(d.cfg.RetentionDays)*24*time.Hour)
+	if err != nil {
+		log.Printf("Failed to clean up webhook deliveries: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d webhook deliveries older than %d days", deleted, d.cfg.RetentionDays)
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>"
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff doubles the wait after every failed attempt, with up to 20%
+// jitter so deliveries to a recovering endpoint do not arrive all at once
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/5+1))
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"ses-monitoring/internal/domain/deadletter"
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// DeadLetterReplayResult is the outcome of replaying one entry
+type DeadLetterReplayResult struct {
+	ID     int64             `json:"id"`
+	Status deadletter.Status `json:"status"`
+	Stored int               `json:"stored"`
+	Error  string            `json:"error,omitempty"`
+}
+
+type DeadLetterUsecase struct {
+	repo  deadletter.Repository
+	sesUC *SESUsecase
+}
+
+func NewDeadLetterUsecase(repo deadletter.Repository, sesUC *SESUsecase) *DeadLetterUsecase {
+	return &DeadLetterUsecase{repo: repo, sesUC: sesUC}
+}
+
+// Record saves a payload that failed to process
+func (uc *DeadLetterUsecase) Record(ctx context.Context, entry *deadletter.Entry) error {
+	return uc.repo.Create(ctx, entry)
+}
+
+// RecordFailedEvents dead-letters the messages behind events the ingest
+// pipeline could not store. Events of the same message share one entry.
+func (uc *DeadLetterUsecase) RecordFailedEvents(events []*sesevent.Event, cause error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	seen := make(map[string]bool)
+	for _, event := range events {
+		if event.RawMessage == "" {
+			log.Printf("Dropping event %s for %s: no raw payload to dead-letter", event.MessageID, event.Email)
+			continue
+		}
+		// Other providers keep their own event ID in SNSMessageID, while the
+		// payload is the whole webhook batch, so the payload identifies the entry
+		source, messageID := "sns", event.SNSMessageID
+		if event.Provider != "" && event.Provider != sesevent.ProviderSES {
+			source, messageID = event.Provider, ""
+		}
+		key := source + "\x00" + messageID + "\x00" + event.RawMessage
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		entry := &deadletter.Entry{
+			Source:    source,
+			MessageID: messageID,
+			Payload:   event.RawMessage,
+			Stage:     deadletter.StageStore,
+			Error:     cause.Error(),
+		}
+		if err := uc.repo.Create(ctx, entry); err != nil {
+			log.Printf("Failed to dead-letter message %s: %v", event.MessageID, err)
+		}
+	}
+}
+
+func (uc *DeadLetterUsecase) List(ctx context.Context, status deadletter.Status, limit, offset int) ([]*deadletter.Entry, int, error) {
+	entries, err := uc.repo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := uc.repo.Count(ctx, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (uc *DeadLetterUsecase) Get(ctx context.Context, id int64) (*deadletter.Entry, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+// UpdateAndReplay replaces the stored payload, e.g. to fix a malformed field, and replays it
+func (uc *DeadLetterUsecase) UpdateAndReplay(ctx context.Context, id int64, payload string) (*DeadLetterReplayResult, error) {
+	if err := uc.repo.UpdatePayload(ctx, id, payload); err != nil {
+		return nil, err
+	}
+	return uc.Replay(ctx, id)
+}
+
+// Replay runs the stored payload through parsing and storage again. A replay
+// that fails is recorded on the entry and reported in the result; the error
+// return is reserved for the entry itself being unavailable.
+func (uc *DeadLetterUsecase) Replay(ctx context.Context, id int64) (*DeadLetterReplayResult, error) {
+	entry, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeadLetterReplayResult{ID: id, Status: deadletter.StatusPending}
+
+	events, err := ParseMessage(entry.Source, []byte(entry.Payload))
+	if err != nil {
+		result.Error = err.Error()
+		return result, uc.repo.RecordAttempt(ctx, id, deadletter.StageParse, deadletter.StatusPending, err.Error())
+	}
+
+	// Keep the original SNS MessageId so events stored before the failure are
+	// deduplicated. Events of other providers carry their own event IDs.
+	if entry.MessageID != "" {
+		for _, event := range events {
+			event.SNSMessageID = entry.MessageID
+		}
+	}
+
+	stored, err := uc.sesUC.HandleEvents(ctx, events)
+	result.Stored = stored
+	if err != nil {
+		result.Error = err.Error()
+		return result, uc.repo.RecordAttempt(ctx, id, deadletter.StageStore, deadletter.StatusPending, err.Error())
+	}
+
+	result.Status = deadletter.StatusReplayed
+	return result, uc.repo.RecordAttempt(ctx, id, entry.Stage, deadletter.StatusReplayed, "")
+}
+
+// ReplayMany replays the given entries, or up to limit pending entries when
+// ids is empty. It stops early only if the entries themselves cannot be read.
+func (uc *DeadLetterUsecase) ReplayMany(ctx context.Context, ids []int64, limit int) ([]*DeadLetterReplayResult, error) {
+	if len(ids) == 0 {
+		pending, err := uc.repo.List(ctx, deadletter.StatusPending, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range pending {
+			ids = append(ids, entry.ID)
+		}
+	}
+
+	results := make([]*DeadLetterReplayResult, 0, len(ids))
+	for _, id := range ids {
+		result, err := uc.Replay(ctx, id)
+		if errors.Is(err, deadletter.ErrNotFound) {
+			results = append(results, &DeadLetterReplayResult{ID: id, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Discard deletes an entry that should not be replayed
+func (uc *DeadLetterUsecase) Discard(ctx context.Context, id int64) error {
+	return uc.repo.Delete(ctx, id)
+}
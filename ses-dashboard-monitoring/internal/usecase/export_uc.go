@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"strconv"
+	"time"
+
+	"ses-monitoring/internal/domain/exportjob"
+)
+
+const (
+	defaultExportLinkTTL = 15 * time.Minute
+	maxExportJobsListed  = 100
+)
+
+var (
+	// ErrInvalidDownloadLink is returned for a download link that was not
+	// signed by this server or has expired
+	ErrInvalidDownloadLink = errors.New("invalid or expired download link")
+	// ErrExportNotReady is returned when downloading a job that has no file
+	ErrExportNotReady = errors.New("export is not ready for download")
+)
+
+// ExportArtifactStore reads and deletes the files written by export jobs
+type ExportArtifactStore interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type ExportUsecase struct {
+	repo  exportjob.Repository
+	store ExportArtifactStore
+	// signingKey signs download links, which work without a bearer token
+	signingKey []byte
+	linkTTL    time.Duration
+}
+
+func NewExportUsecase(repo exportjob.Repository, store ExportArtifactStore, signingKey string, linkTTL time.Duration) *ExportUsecase {
+	if linkTTL <= 0 {
+		linkTTL = defaultExportLinkTTL
+	}
+	return &ExportUsecase{repo: repo, store: store, signingKey: []byte(signingKey), linkTTL: linkTTL}
+}
+
+// Submit queues a job for the export workers
+func (uc *ExportUsecase) Submit(ctx context.Context, job *exportjob.Job) error {
+	return uc.repo.Create(ctx, job)
+}
+
+func (uc *ExportUsecase) Get(ctx context.Context, id int64) (*exportjob.Job, error) {
+	return uc.repo.Get(ctx, id)
+}
+
+// List returns the newest jobs of a user, or of every user when userID is 0
+func (uc *ExportUsecase) List(ctx context.Context, userID int64) ([]*exportjob.Job, error) {
+	return uc.repo.List(ctx, userID, maxExportJobsListed)
+}
+
+// Remove cancels a queued or running job, or deletes a finished job together with its file
+func (uc *ExportUsecase) Remove(ctx context.Context, job *exportjob.Job) error {
+	if !job.IsFinished() {
+		canceled, err := uc.repo.Cancel(ctx, job.ID)
+		if err != nil || canceled {
+			return err
+		}
+		// Finished in the meantime; reload to find its file
+		if job, err = uc.repo.Get(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+	if job.Status == exportjob.StatusSucceeded && job.ArtifactKey != "" {
+		if err := uc.deleteArtifact(ctx, job.ArtifactKey); err != nil {
+			return err
+		}
+	}
+	return uc.repo.Delete(ctx, job.ID)
+}
+
+// Open returns the file of a succeeded job
+func (uc *ExportUsecase) Open(ctx context.Context, job *exportjob.Job) (io.ReadCloser, error) {
+	if job.Status != exportjob.StatusSucceeded || job.ArtifactKey == "" {
+		return nil, ErrExportNotReady
+	}
+	return uc.store.Get(ctx, job.ArtifactKey)
+}
+
+// SignDownload returns when a download link for the job created now expires
+// and its signature
+func (uc *ExportUsecase) SignDownload(jobID int64, now time.Time) (time.Time, string) {
+	expires := now.Add(uc.linkTTL).Truncate(time.Second)
+	return expires, uc.downloadSignature(jobID, expires.Unix())
+}
+
+// VerifyDownload checks the expiry and signature of a download link
+func (uc *ExportUsecase) VerifyDownload(jobID int64, expires int64, signature string, now time.Time) error {
+	if now.Unix() > expires {
+		return ErrInvalidDownloadLink
+	}
+	expected := uc.downloadSignature(jobID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidDownloadLink
+	}
+	return nil
+}
+
+// downloadSignature is the hex HMAC-SHA256 of "<job id>.<expiry unix time>"
+func (uc *ExportUsecase) downloadSignature(jobID int64, expires int64) string {
+	mac := hmac.New(sha256.New, uc.signingKey)
+	mac.Write([]byte(strconv.FormatInt(jobID, 10) + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (uc *ExportUsecase) deleteArtifact(ctx context.Context, key string) error {
+	err := uc.store.Delete(ctx, key)
+	// The cleanup may have removed it already; a missing file is what we want
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// MailgunWebhook is the JSON body of a Mailgun webhook. Signature is checked
+// by the webhook handler before the event is parsed.
+type MailgunWebhook struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData struct {
+		ID         string   `json:"id"`
+		Event      string   `json:"event"`
+		Timestamp  float64  `json:"timestamp"`
+		Recipient  string   `json:"recipient"`
+		Severity   string   `json:"severity"`
+		Reason     string   `json:"reason"`
+		URL        string   `json:"url"`
+		IP         string   `json:"ip"`
+		Tags       []string `json:"tags"`
+		ClientInfo struct {
+			UserAgent string `json:"user-agent"`
+		} `json:"client-info"`
+		DeliveryStatus struct {
+			Code        int    `json:"code"`
+			Message     string `json:"message"`
+			Description string `json:"description"`
+			MXHost      string `json:"mx-host"`
+		} `json:"delivery-status"`
+		Message struct {
+			Headers struct {
+				MessageID string `json:"message-id"`
+				From      string `json:"from"`
+				Subject   string `json:"subject"`
+			} `json:"headers"`
+		} `json:"message"`
+	} `json:"event-data"`
+}
+
+// ParseMailgunMessage maps a Mailgun webhook onto SES event types. Events
+// Mailgun has no SES equivalent for yield no events.
+func ParseMailgunMessage(data []byte) ([]*sesevent.Event, error) {
+	var webhook MailgunWebhook
+	if err := json.Unmarshal(data, &webhook); err != nil {
+		return nil, fmt.Errorf("%w: malformed Mailgun JSON", ErrInvalidProviderEvent)
+	}
+	mg := webhook.EventData
+	if mg.Recipient == "" {
+		return nil, fmt.Errorf("%w: Mailgun event without recipient", ErrInvalidProviderEvent)
+	}
+
+	var tags map[string][]string
+	if len(mg.Tags) > 0 {
+		tags = map[string][]string{"tag": mg.Tags}
+	}
+
+	seconds, fraction := math.Modf(mg.Timestamp)
+	occurredAt := time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+
+	newEvent := func(eventType string) *sesevent.Event {
+		event := newProviderEvent(
+			sesevent.ProviderMailgun,
+			strings.Trim(mg.Message.Headers.MessageID, "<>"),
+			mg.Recipient,
+			eventType,
+			occurredAt,
+			tags,
+			string(data),
+		)
+		event.SNSMessageID = mg.ID
+		event.Subject = mg.Message.Headers.Subject
+		event.Source = mg.Message.Headers.From
+		return event
+	}
+
+	diagnostic := mg.DeliveryStatus.Description
+	if diagnostic == "" {
+		diagnostic = mg.DeliveryStatus.Message
+	}
+
+	var event *sesevent.Event
+	switch mg.Event {
+	case "accepted":
+		event = newEvent("Send")
+	case "delivered":
+		event = newEvent("Delivery")
+		event.SmtpResponse = strings.TrimSpace(fmt.Sprintf("%d %s", mg.DeliveryStatus.Code, mg.DeliveryStatus.Message))
+		event.ReportingMTA = mg.DeliveryStatus.MXHost
+	case "failed":
+		if mg.Severity == "temporary" {
+			event = newEvent("DeliveryDelay")
+			event.Status = "DELAYED"
+		} else {
+			event = newEvent("Bounce")
+			event.Status = "FAILED"
+			event.BounceType = "Permanent"
+			event.BounceSubType = mg.Reason
+		}
+		event.Reason = diagnostic
+		event.DiagnosticCode = diagnostic
+		event.ReportingMTA = mg.DeliveryStatus.MXHost
+	case "rejected":
+		event = newEvent("Reject")
+		event.Status = "REJECTED"
+		event.Reason = mg.Reason
+	case "complained":
+		event = newEvent("Complaint")
+		event.Status = "COMPLAINED"
+	case "opened":
+		event = newEvent("Open")
+		event.IPAddress = mg.IP
+		event.UserAgent = mg.ClientInfo.UserAgent
+	case "clicked":
+		event = newEvent("Click")
+		event.Link = mg.URL
+		event.IPAddress = mg.IP
+		event.UserAgent = mg.ClientInfo.UserAgent
+	case "unsubscribed":
+		event = newEvent("Subscription")
+		event.Status = "UNSUBSCRIBED"
+	default:
+		return nil, nil
+	}
+	return []*sesevent.Event{event}, nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// PostmarkEvent is the JSON body of a Postmark webhook. The fields used
+// depend on RecordType.
+type PostmarkEvent struct {
+	RecordType        string            `json:"RecordType"`
+	ID                int64             `json:"ID"`
+	Type              string            `json:"Type"`
+	MessageID         string            `json:"MessageID"`
+	MessageStream     string            `json:"MessageStream"`
+	Recipient         string            `json:"Recipient"`
+	Email             string            `json:"Email"`
+	From              string            `json:"From"`
+	Subject           string            `json:"Subject"`
+	Tag               string            `json:"Tag"`
+	Description       string            `json:"Description"`
+	Details           string            `json:"Details"`
+	DeliveredAt       string            `json:"DeliveredAt"`
+	BouncedAt         string            `json:"BouncedAt"`
+	ReceivedAt        string            `json:"ReceivedAt"`
+	ChangedAt         string            `json:"ChangedAt"`
+	OriginalLink      string            `json:"OriginalLink"`
+	UserAgent         string            `json:"UserAgent"`
+	SuppressSending   bool              `json:"SuppressSending"`
+	SuppressionReason string            `json:"SuppressionReason"`
+	Metadata          map[string]string `json:"Metadata"`
+	Geo               struct {
+		IP string `json:"IP"`
+	} `json:"Geo"`
+}
+
+// postmarkPermanentBounces are the bounce types Postmark deactivates the recipient for
+var postmarkPermanentBounces = map[string]bool{
+	"HardBounce":          true,
+	"BadEmailAddress":     true,
+	"ManuallyDeactivated": true,
+	"DMARCPolicy":         true,
+}
+
+// ParsePostmarkMessage maps a Postmark webhook onto SES event types. Record
+// types Postmark has no SES equivalent for yield no events.
+func ParsePostmarkMessage(data []byte) ([]*sesevent.Event, error) {
+	var pm PostmarkEvent
+	if err := json.Unmarshal(data, &pm); err != nil {
+		return nil, fmt.Errorf("%w: malformed Postmark JSON", ErrInvalidProviderEvent)
+	}
+
+	email := pm.Recipient
+	if email == "" {
+		email = pm.Email
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: Postmark event without recipient", ErrInvalidProviderEvent)
+	}
+
+	tags := map[string][]string{}
+	if pm.Tag != "" {
+		tags["tag"] = []string{pm.Tag}
+	}
+	if pm.MessageStream != "" {
+		tags["message-stream"] = []string{pm.MessageStream}
+	}
+	for key, value := range pm.Metadata {
+		tags[key] = []string{value}
+	}
+
+	newEvent := func(eventType, timestamp string) (*sesevent.Event, error) {
+		occurredAt, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid Postmark %s timestamp", ErrInvalidProviderEvent, pm.RecordType)
+		}
+		event := newProviderEvent(
+			sesevent.ProviderPostmark,
+			pm.MessageID,
+			email,
+			eventType,
+			occurredAt,
+			tags,
+			string(data),
+		)
+		// Only bounce and spam complaint records carry an ID
+		if pm.ID != 0 {
+			event.SNSMessageID = strconv.FormatInt(pm.ID, 10)
+		}
+		event.Subject = pm.Subject
+		event.Source = pm.From
+		return event, nil
+	}
+
+	var (
+		event *sesevent.Event
+		err   error
+	)
+	switch pm.RecordType {
+	case "Delivery":
+		if event, err = newEvent("Delivery", pm.DeliveredAt); err == nil {
+			event.SmtpResponse = pm.Details
+		}
+	case "Bounce":
+		if event, err = newEvent("Bounce", pm.BouncedAt); err == nil {
+			event.Status = "FAILED"
+			event.Reason = pm.Description
+			event.DiagnosticCode = pm.Details
+			event.BounceType = "Transient"
+			if postmarkPermanentBounces[pm.Type] {
+				event.BounceType = "Permanent"
+			}
+			event.BounceSubType = pm.Type
+		}
+	case "SpamComplaint":
+		if event, err = newEvent("Complaint", pm.BouncedAt); err == nil {
+			event.Status = "COMPLAINED"
+			event.Reason = pm.Description
+		}
+	case "Open":
+		if event, err = newEvent("Open", pm.ReceivedAt); err == nil {
+			event.IPAddress = pm.Geo.IP
+			event.UserAgent = pm.UserAgent
+		}
+	case "Click":
+		if event, err = newEvent("Click", pm.ReceivedAt); err == nil {
+			event.Link = pm.OriginalLink
+			event.IPAddress = pm.Geo.IP
+			event.UserAgent = pm.UserAgent
+		}
+	case "SubscriptionChange":
+		if event, err = newEvent("Subscription", pm.ChangedAt); err == nil {
+			if pm.SuppressSending {
+				event.Status = "UNSUBSCRIBED"
+			}
+			event.Reason = pm.SuppressionReason
+		}
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*sesevent.Event{event}, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// ErrInvalidProviderEvent is returned when a webhook payload of another provider cannot be turned into events
+var ErrInvalidProviderEvent = errors.New("invalid provider event")
+
+// ParseMessage converts a payload into events with the parser of the provider
+// that sent it. source is a provider name or, for SES, the channel the payload
+// arrived on (sns, sqs, firehose, ...), as recorded on archive and dead-letter
+// entries.
+func ParseMessage(source string, data []byte) ([]*sesevent.Event, error) {
+	switch source {
+	case sesevent.ProviderSendGrid:
+		return ParseSendGridMessage(data)
+	case sesevent.ProviderMailgun:
+		return ParseMailgunMessage(data)
+	case sesevent.ProviderPostmark:
+		return ParsePostmarkMessage(data)
+	}
+	return ParseSESMessage(data)
+}
+
+// newProviderEvent creates an event reported by a provider other than SES.
+// Providers report when the event happened but not when the mail was sent,
+// so both EventTimestamp and OccurredAt are set to the event time.
+func newProviderEvent(provider, messageID, email, eventType string, occurredAt time.Time, tags map[string][]string, rawMessage string) *sesevent.Event {
+	recipientsJSON, _ := json.Marshal([]string{email})
+	if tags == nil {
+		tags = map[string][]string{}
+	}
+	tagsJSON, _ := json.Marshal(tags)
+	return &sesevent.Event{
+		MessageID:      messageID,
+		Email:          email,
+		EventType:      eventType,
+		Status:         "SUCCESS",
+		Recipients:     string(recipientsJSON),
+		EventTimestamp: occurredAt,
+		OccurredAt:     &occurredAt,
+		Tags:           string(tagsJSON),
+		Provider:       provider,
+		RawMessage:     rawMessage,
+	}
+}
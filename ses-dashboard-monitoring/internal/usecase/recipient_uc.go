@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"ses-monitoring/internal/domain/models"
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/domain/suppression"
+)
+
+// maxRecipientSenders bounds the sender list of a recipient profile
+const maxRecipientSenders = 100
+
+// AWSSuppressionLookup finds an address in the copy of the SES account
+// suppression list (the suppressions table)
+type AWSSuppressionLookup interface {
+	GetByEmail(email string) (*models.Suppression, error)
+}
+
+// RecipientSuppression is the suppression state of an address in both lists
+type RecipientSuppression struct {
+	Suppressed bool `json:"suppressed"`
+	// AWS is the entry synced from the SES account suppression list
+	AWS *models.Suppression `json:"aws"`
+	// Local is the entry of the dashboard's own list, which stays after removal with is_active false
+	Local *suppression.SuppressionEntry `json:"local"`
+}
+
+// RecipientProfile is everything known about one email address
+type RecipientProfile struct {
+	Email       string                      `json:"email"`
+	Stats       *sesevent.RecipientStats    `json:"stats"`
+	Suppression RecipientSuppression        `json:"suppression"`
+	Senders     []*sesevent.RecipientSender `json:"senders"`
+}
+
+type RecipientUsecase struct {
+	events          sesevent.Repository
+	suppressionRepo suppression.Repository
+	awsSuppressions AWSSuppressionLookup
+}
+
+func NewRecipientUsecase(events sesevent.Repository, suppressionRepo suppression.Repository, awsSuppressions AWSSuppressionLookup) *RecipientUsecase {
+	return &RecipientUsecase{
+		events:          events,
+		suppressionRepo: suppressionRepo,
+		awsSuppressions: awsSuppressions,
+	}
+}
+
+// GetProfile returns the profile of an address. An address that was never
+// mailed has an empty profile rather than none.
+func (uc *RecipientUsecase) GetProfile(ctx context.Context, email string) (*RecipientProfile, error) {
+	stats, err := uc.events.GetRecipientStats(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	senders, err := uc.events.GetRecipientSenders(ctx, email, maxRecipientSenders)
+	if err != nil {
+		return nil, err
+	}
+	if senders == nil {
+		senders = []*sesevent.RecipientSender{}
+	}
+
+	profile := &RecipientProfile{Email: email, Stats: stats, Senders: senders}
+
+	// The suppression lists are keyed on the exact address; SES stores them
+	// lower case, manual entries keep what was typed
+	for _, candidate := range emailCandidates(email) {
+		if profile.Suppression.AWS == nil {
+			entry, err := uc.awsSuppressions.GetByEmail(candidate)
+			if err != nil {
+				return nil, err
+			}
+			profile.Suppression.AWS = entry
+		}
+		if profile.Suppression.Local == nil {
+			entry, err := uc.suppressionRepo.GetByEmail(ctx, candidate)
+			if err != nil {
+				return nil, err
+			}
+			profile.Suppression.Local = entry
+		}
+	}
+	profile.Suppression.Suppressed = profile.Suppression.AWS != nil ||
+		(profile.Suppression.Local != nil && profile.Suppression.Local.IsActive)
+
+	return profile, nil
+}
+
+// GetEvents returns a page of the address's events, newest first
+func (uc *RecipientUsecase) GetEvents(ctx context.Context, email string, limit, offset int) ([]*sesevent.Event, error) {
+	return uc.events.GetEventsByRecipient(ctx, email, limit, offset)
+}
+
+func emailCandidates(email string) []string {
+	if lower := strings.ToLower(email); lower != email {
+		return []string{email, lower}
+	}
+	return []string{email}
+}
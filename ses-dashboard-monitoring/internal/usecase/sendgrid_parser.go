@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// SendGridEvent is one entry of the JSON array posted by the SendGrid Event Webhook
+type SendGridEvent struct {
+	Email       string          `json:"email"`
+	Timestamp   int64           `json:"timestamp"`
+	Event       string          `json:"event"`
+	SGEventID   string          `json:"sg_event_id"`
+	SGMessageID string          `json:"sg_message_id"`
+	SMTPID      string          `json:"smtp-id"`
+	Reason      string          `json:"reason"`
+	Status      string          `json:"status"`
+	Response    string          `json:"response"`
+	Type        string          `json:"type"`
+	URL         string          `json:"url"`
+	UserAgent   string          `json:"useragent"`
+	IP          string          `json:"ip"`
+	Category    json.RawMessage `json:"category"` // a string or an array of strings
+}
+
+// ParseSendGridMessage maps a SendGrid Event Webhook batch onto SES event
+// types. Events SendGrid has no SES equivalent for are skipped.
+func ParseSendGridMessage(data []byte) ([]*sesevent.Event, error) {
+	var batch []SendGridEvent
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("%w: malformed SendGrid JSON", ErrInvalidProviderEvent)
+	}
+
+	rawMessage := string(data)
+	var events []*sesevent.Event
+	for _, sg := range batch {
+		if sg.Email == "" {
+			return nil, fmt.Errorf("%w: SendGrid event without email", ErrInvalidProviderEvent)
+		}
+
+		var tags map[string][]string
+		if categories := sendGridCategories(sg.Category); len(categories) > 0 {
+			tags = map[string][]string{"category": categories}
+		}
+
+		newEvent := func(eventType string) *sesevent.Event {
+			event := newProviderEvent(
+				sesevent.ProviderSendGrid,
+				sendGridMessageID(sg),
+				sg.Email,
+				eventType,
+				time.Unix(sg.Timestamp, 0).UTC(),
+				tags,
+				rawMessage,
+			)
+			event.SNSMessageID = sg.SGEventID
+			return event
+		}
+
+		var event *sesevent.Event
+		switch sg.Event {
+		case "processed":
+			event = newEvent("Send")
+		case "delivered":
+			event = newEvent("Delivery")
+			event.SmtpResponse = sg.Response
+		case "deferred":
+			event = newEvent("DeliveryDelay")
+			event.Status = "DELAYED"
+			event.Reason = sg.Response
+			event.DiagnosticCode = sg.Response
+		case "bounce":
+			event = newEvent("Bounce")
+			event.Status = "FAILED"
+			event.Reason = sg.Reason
+			event.DiagnosticCode = sg.Reason
+			// "blocked" bounces are temporary rejections by the receiving server
+			event.BounceType = "Permanent"
+			if sg.Type == "blocked" {
+				event.BounceType = "Transient"
+			}
+			event.BounceSubType = sg.Type
+		case "dropped":
+			event = newEvent("Reject")
+			event.Status = "REJECTED"
+			event.Reason = sg.Reason
+		case "spamreport":
+			event = newEvent("Complaint")
+			event.Status = "COMPLAINED"
+		case "open":
+			event = newEvent("Open")
+			event.IPAddress = sg.IP
+			event.UserAgent = sg.UserAgent
+		case "click":
+			event = newEvent("Click")
+			event.Link = sg.URL
+			event.IPAddress = sg.IP
+			event.UserAgent = sg.UserAgent
+		case "unsubscribe", "group_unsubscribe":
+			event = newEvent("Subscription")
+			event.Status = "UNSUBSCRIBED"
+		case "group_resubscribe":
+			event = newEvent("Subscription")
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// sendGridMessageID returns the X-Message-Id of the mail. sg_message_id adds a
+// per-recipient suffix starting with ".filter".
+func sendGridMessageID(sg SendGridEvent) string {
+	if id, _, ok := strings.Cut(sg.SGMessageID, ".filter"); ok {
+		return id
+	}
+	if sg.SGMessageID != "" {
+		return sg.SGMessageID
+	}
+	return strings.Trim(sg.SMTPID, "<>")
+}
+
+func sendGridCategories(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		if single == "" {
+			return nil
+		}
+		return []string{single}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
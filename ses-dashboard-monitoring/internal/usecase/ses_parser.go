@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+)
+
+// ErrInvalidSESEvent is returned when an SES event payload cannot be turned into events
+var ErrInvalidSESEvent = errors.New("invalid SES event")
+
+// SESEvent is the JSON document SES publishes for every email sending event.
+// Event publishing sets eventType; legacy identity notifications set
+// notificationType instead and otherwise share the same layout.
+type SESEvent struct {
+	EventType        string `json:"eventType"`
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		Timestamp     string              `json:"timestamp"`
+		MessageID     string              `json:"messageId"`
+		Source        string              `json:"source"`
+		Destination   []string            `json:"destination"`
+		Tags          map[string][]string `json:"tags"`
+		CommonHeaders struct {
+			Subject string `json:"subject"`
+		} `json:"commonHeaders"`
+	} `json:"mail"`
+	Bounce struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			Action         string `json:"action"`
+			Status         string `json:"status"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+		Timestamp    string `json:"timestamp"`
+		ReportingMTA string `json:"reportingMTA"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+		Timestamp             string `json:"timestamp"`
+		FeedbackID            string `json:"feedbackId"`
+		UserAgent             string `json:"userAgent"`
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplaintSubType      string `json:"complaintSubType"`
+		ArrivalDate           string `json:"arrivalDate"`
+	} `json:"complaint"`
+	Open struct {
+		Timestamp string `json:"timestamp"`
+		IPAddress string `json:"ipAddress"`
+		UserAgent string `json:"userAgent"`
+	} `json:"open"`
+	Click struct {
+		Timestamp string              `json:"timestamp"`
+		IPAddress string              `json:"ipAddress"`
+		UserAgent string              `json:"userAgent"`
+		Link      string              `json:"link"`
+		LinkTags  map[string][]string `json:"linkTags"`
+	} `json:"click"`
+	DeliveryDelay struct {
+		Timestamp         string `json:"timestamp"`
+		DelayType         string `json:"delayType"`
+		ExpirationTime    string `json:"expirationTime"`
+		ReportingMTA      string `json:"reportingMTA"`
+		DelayedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			Status         string `json:"status"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"delayedRecipients"`
+	} `json:"deliveryDelay"`
+	Reject struct {
+		Reason string `json:"reason"`
+	} `json:"reject"`
+	Failure struct {
+		ErrorMessage string `json:"errorMessage"`
+		TemplateName string `json:"templateName"`
+	} `json:"failure"`
+	Subscription struct {
+		ContactList         string          `json:"contactList"`
+		Timestamp           string          `json:"timestamp"`
+		Source              string          `json:"source"`
+		NewTopicPreferences json.RawMessage `json:"newTopicPreferences"`
+		OldTopicPreferences json.RawMessage `json:"oldTopicPreferences"`
+	} `json:"subscription"`
+	Delivery struct {
+		Timestamp            string   `json:"timestamp"`
+		ProcessingTimeMillis int      `json:"processingTimeMillis"`
+		Recipients           []string `json:"recipients"`
+		SmtpResponse         string   `json:"smtpResponse"`
+		RemoteMtaIp          string   `json:"remoteMtaIp"`
+		ReportingMTA         string   `json:"reportingMTA"`
+	} `json:"delivery"`
+}
+
+// ParseSESMessage converts an SES event payload into one event per affected
+// recipient. Bounces, complaints and deliveries name the recipients they
+// apply to; every other event type applies to all destination addresses.
+// The notification SES sends when identity notifications are first enabled
+// carries no events and yields none.
+func ParseSESMessage(data []byte) ([]*sesevent.Event, error) {
+	var sesEvent SESEvent
+	if err := json.Unmarshal(data, &sesEvent); err != nil {
+		return nil, fmt.Errorf("%w: malformed JSON", ErrInvalidSESEvent)
+	}
+
+	publishingMechanism := sesevent.PublishingMechanismEventPublishing
+	if sesEvent.EventType == "" && sesEvent.NotificationType != "" {
+		if sesEvent.NotificationType == "AmazonSnsSubscriptionSucceeded" {
+			return nil, nil
+		}
+		sesEvent.EventType = sesEvent.NotificationType
+		publishingMechanism = sesevent.PublishingMechanismNotification
+	}
+	if sesEvent.EventType == "" {
+		return nil, fmt.Errorf("%w: missing eventType or notificationType", ErrInvalidSESEvent)
+	}
+
+	// SES publishes rendering failures as "Rendering Failure"
+	if sesEvent.EventType == "Rendering Failure" {
+		sesEvent.EventType = "RenderingFailure"
+	}
+
+	eventTimestamp, err := time.Parse(time.RFC3339, sesEvent.Mail.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid mail timestamp", ErrInvalidSESEvent)
+	}
+
+	if len(sesEvent.Mail.Destination) == 0 {
+		return nil, fmt.Errorf("%w: missing destination recipients", ErrInvalidSESEvent)
+	}
+
+	occurredAt := parseOptionalTimestamp(sesEvent.eventTimestamp())
+
+	recipientsJSON, _ := json.Marshal(sesEvent.Mail.Destination)
+	rawMessage := string(data)
+	tags := sesEvent.Mail.Tags
+	if tags == nil {
+		tags = map[string][]string{}
+	}
+	tagsJSON, _ := json.Marshal(tags)
+	configurationSet := firstTagValue(tags, "ses:configuration-set")
+	fromDomain := firstTagValue(tags, "ses:from-domain")
+
+	newEvent := func(email string) *sesevent.Event {
+		return &sesevent.Event{
+			MessageID:        sesEvent.Mail.MessageID,
+			Email:            email,
+			Subject:          sesEvent.Mail.CommonHeaders.Subject,
+			EventType:        sesEvent.EventType,
+			Status:           "SUCCESS",
+			Source:           sesEvent.Mail.Source,
+			Recipients:       string(recipientsJSON),
+			EventTimestamp:   eventTimestamp,
+			OccurredAt:       occurredAt,
+			Tags:             string(tagsJSON),
+			ConfigurationSet: configurationSet,
+			FromDomain:       fromDomain,
+			RawMessage:       rawMessage,
+
+			PublishingMechanism: publishingMechanism,
+			Provider:            sesevent.ProviderSES,
+		}
+	}
+
+	var events []*sesevent.Event
+
+	switch sesEvent.EventType {
+	case "Bounce":
+		if len(sesEvent.Bounce.BouncedRecipients) == 0 {
+			return nil, fmt.Errorf("%w: missing bounce recipients", ErrInvalidSESEvent)
+		}
+		for _, recipient := range sesEvent.Bounce.BouncedRecipients {
+			event := newEvent(recipient.EmailAddress)
+			event.Status = bounceRecipientStatus(recipient.Action)
+			event.Reason = recipient.DiagnosticCode
+			if event.Reason == "" {
+				event.Reason = recipient.Status
+			}
+			event.BounceType = sesEvent.Bounce.BounceType
+			event.BounceSubType = sesEvent.Bounce.BounceSubType
+			event.DiagnosticCode = recipient.DiagnosticCode
+			event.ReportingMTA = sesEvent.Bounce.ReportingMTA
+			events = append(events, event)
+		}
+	case "Complaint":
+		if len(sesEvent.Complaint.ComplainedRecipients) == 0 {
+			return nil, fmt.Errorf("%w: missing complaint recipients", ErrInvalidSESEvent)
+		}
+		arrivalDate := parseOptionalTimestamp(sesEvent.Complaint.ArrivalDate)
+		for _, recipient := range sesEvent.Complaint.ComplainedRecipients {
+			event := newEvent(recipient.EmailAddress)
+			event.Status = "COMPLAINED"
+			event.Reason = sesEvent.Complaint.ComplaintFeedbackType
+			event.ComplaintFeedbackType = sesEvent.Complaint.ComplaintFeedbackType
+			event.ComplaintSubType = sesEvent.Complaint.ComplaintSubType
+			event.ComplaintUserAgent = sesEvent.Complaint.UserAgent
+			event.ComplaintArrivalDate = arrivalDate
+			event.FeedbackID = sesEvent.Complaint.FeedbackID
+			events = append(events, event)
+		}
+	case "Delivery":
+		recipients := sesEvent.Delivery.Recipients
+		if len(recipients) == 0 {
+			recipients = sesEvent.Mail.Destination
+		}
+		for _, email := range recipients {
+			event := newEvent(email)
+			event.ProcessingTimeMillis = sesEvent.Delivery.ProcessingTimeMillis
+			event.SmtpResponse = sesEvent.Delivery.SmtpResponse
+			event.RemoteMtaIp = sesEvent.Delivery.RemoteMtaIp
+			event.ReportingMTA = sesEvent.Delivery.ReportingMTA
+			events = append(events, event)
+		}
+	case "DeliveryDelay":
+		if len(sesEvent.DeliveryDelay.DelayedRecipients) == 0 {
+			return nil, fmt.Errorf("%w: missing delayed recipients", ErrInvalidSESEvent)
+		}
+		expirationTime := parseOptionalTimestamp(sesEvent.DeliveryDelay.ExpirationTime)
+		for _, recipient := range sesEvent.DeliveryDelay.DelayedRecipients {
+			event := newEvent(recipient.EmailAddress)
+			event.Status = "DELAYED"
+			event.Reason = recipient.DiagnosticCode
+			if event.Reason == "" {
+				event.Reason = recipient.Status
+			}
+			event.DiagnosticCode = recipient.DiagnosticCode
+			event.ReportingMTA = sesEvent.DeliveryDelay.ReportingMTA
+			event.DelayType = sesEvent.DeliveryDelay.DelayType
+			event.DelayExpirationTime = expirationTime
+			events = append(events, event)
+		}
+	case "Reject":
+		for _, email := range sesEvent.Mail.Destination {
+			event := newEvent(email)
+			event.Status = "REJECTED"
+			event.Reason = sesEvent.Reject.Reason
+			events = append(events, event)
+		}
+	case "RenderingFailure":
+		for _, email := range sesEvent.Mail.Destination {
+			event := newEvent(email)
+			event.Status = "FAILED"
+			event.Reason = sesEvent.Failure.ErrorMessage
+			event.TemplateName = sesEvent.Failure.TemplateName
+			events = append(events, event)
+		}
+	case "Subscription":
+		preferences, _ := json.Marshal(map[string]json.RawMessage{
+			"new": nonNullJSON(sesEvent.Subscription.NewTopicPreferences),
+			"old": nonNullJSON(sesEvent.Subscription.OldTopicPreferences),
+		})
+		var newPreferences struct {
+			UnsubscribeAll bool `json:"unsubscribeAll"`
+		}
+		_ = json.Unmarshal(sesEvent.Subscription.NewTopicPreferences, &newPreferences)
+		for _, email := range sesEvent.Mail.Destination {
+			event := newEvent(email)
+			if newPreferences.UnsubscribeAll {
+				event.Status = "UNSUBSCRIBED"
+			}
+			event.ContactList = sesEvent.Subscription.ContactList
+			event.TopicPreferences = string(preferences)
+			events = append(events, event)
+		}
+	case "Open":
+		event := newEvent(engagementRecipient(sesEvent.Mail.Destination))
+		event.IPAddress = sesEvent.Open.IPAddress
+		event.UserAgent = sesEvent.Open.UserAgent
+		events = append(events, event)
+	case "Click":
+		linkTagsJSON := ""
+		if len(sesEvent.Click.LinkTags) > 0 {
+			encoded, _ := json.Marshal(sesEvent.Click.LinkTags)
+			linkTagsJSON = string(encoded)
+		}
+		event := newEvent(engagementRecipient(sesEvent.Mail.Destination))
+		event.Link = sesEvent.Click.Link
+		event.LinkTags = linkTagsJSON
+		event.IPAddress = sesEvent.Click.IPAddress
+		event.UserAgent = sesEvent.Click.UserAgent
+		events = append(events, event)
+	default:
+		for _, email := range sesEvent.Mail.Destination {
+			events = append(events, newEvent(email))
+		}
+	}
+
+	return events, nil
+}
+
+// engagementRecipient returns the address an open or click belongs to. SES
+// does not say which recipient opened or clicked, so the event is only
+// attributed when the message had a single recipient; otherwise it is stored
+// once with an empty email and the recipients list.
+func engagementRecipient(destination []string) string {
+	if len(destination) == 1 {
+		return destination[0]
+	}
+	return ""
+}
+
+// bounceRecipientStatus maps the DSN action of a bounced recipient to an event status
+func bounceRecipientStatus(action string) string {
+	if action == "" || strings.EqualFold(action, "failed") {
+		return "FAILED"
+	}
+	return strings.ToUpper(action)
+}
+
+// eventTimestamp returns the time reported by the event-specific section of the payload
+func (e *SESEvent) eventTimestamp() string {
+	switch e.EventType {
+	case "Bounce":
+		return e.Bounce.Timestamp
+	case "Complaint":
+		return e.Complaint.Timestamp
+	case "Delivery":
+		return e.Delivery.Timestamp
+	case "DeliveryDelay":
+		return e.DeliveryDelay.Timestamp
+	case "Subscription":
+		return e.Subscription.Timestamp
+	case "Open":
+		return e.Open.Timestamp
+	case "Click":
+		return e.Click.Timestamp
+	}
+	return ""
+}
+
+func parseOptionalTimestamp(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+func nonNullJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
+
+func firstTagValue(tags map[string][]string, key string) string {
+	if values := tags[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
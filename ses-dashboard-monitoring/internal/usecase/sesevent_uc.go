@@ -2,13 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"ses-monitoring/internal/domain/sesevent"
 )
 
+// StoredEventsHook is called with events right after they were stored for
+// the first time. It runs on the ingest path, so it should return quickly.
+type StoredEventsHook func(ctx context.Context, events []*sesevent.Event)
+
 type SESUsecase struct {
-	repo sesevent.Repository
+	repo  sesevent.Repository
+	hooks []StoredEventsHook
 }
 
 func NewSESUsecase(repo sesevent.Repository) *SESUsecase {
@@ -22,6 +28,66 @@ func (uc *SESUsecase) HandleEvent(
 	return uc.repo.Save(ctx, event)
 }
 
+// OnEventsStored registers a hook for newly stored events. Hooks must be
+// registered before events are ingested.
+func (uc *SESUsecase) OnEventsStored(hook StoredEventsHook) {
+	uc.hooks = append(uc.hooks, hook)
+}
+
+func (uc *SESUsecase) runHooks(ctx context.Context, events []*sesevent.Event) {
+	if len(events) == 0 {
+		return
+	}
+	for _, hook := range uc.hooks {
+		hook(ctx, events)
+	}
+}
+
+// HandleEvents stores every recipient event produced from a single SES message
+// and returns how many were new. Events that were already stored, e.g. because
+// SNS redelivered the notification, are skipped.
+func (uc *SESUsecase) HandleEvents(ctx context.Context, events []*sesevent.Event) (int, error) {
+	var stored []*sesevent.Event
+	defer func() { uc.runHooks(ctx, stored) }()
+
+	for _, event := range events {
+		if event.EventKey == "" {
+			event.EventKey = event.Fingerprint()
+		}
+		err := uc.repo.Save(ctx, event)
+		if errors.Is(err, sesevent.ErrDuplicateEvent) {
+			continue
+		}
+		if err != nil {
+			return len(stored), err
+		}
+		stored = append(stored, event)
+	}
+	return len(stored), nil
+}
+
+// StoreEvents writes a batch of events in one round trip and returns how many
+// were new. It is used by the ingestion pipeline.
+func (uc *SESUsecase) StoreEvents(ctx context.Context, events []*sesevent.Event) (int, error) {
+	for _, event := range events {
+		if event.EventKey == "" {
+			event.EventKey = event.Fingerprint()
+		}
+	}
+	stored, err := uc.repo.SaveBatch(ctx, events)
+	if err != nil {
+		return 0, err
+	}
+	uc.runHooks(ctx, stored)
+	return len(stored), nil
+}
+
+// UpsertEvents writes a batch of events, overwriting stored events with the
+// same key. It is used to backfill events from the raw archive.
+func (uc *SESUsecase) UpsertEvents(ctx context.Context, events []*sesevent.Event) (int, error) {
+	return uc.repo.UpsertBatch(ctx, events)
+}
+
 func (uc *SESUsecase) GetEvents(ctx context.Context) ([]*sesevent.Event, error) {
 	return uc.repo.GetEvents(ctx)
 }
@@ -30,42 +96,82 @@ func (uc *SESUsecase) GetEventsPaginated(ctx context.Context, limit, offset int)
 	return uc.repo.GetEventsPaginated(ctx, limit, offset)
 }
 
-func (uc *SESUsecase) GetEventsWithFilter(ctx context.Context, limit, offset int, search, startDate, endDate string) ([]*sesevent.Event, error) {
-	return uc.repo.GetEventsWithFilter(ctx, limit, offset, search, startDate, endDate)
+func (uc *SESUsecase) GetEventsWithFilter(ctx context.Context, filter sesevent.Filter, limit, offset int) ([]*sesevent.Event, error) {
+	return uc.repo.GetEventsWithFilter(ctx, filter, limit, offset)
 }
 
-func (uc *SESUsecase) GetFilteredEventCount(ctx context.Context, search, startDate, endDate string) (int, error) {
-	return uc.repo.GetFilteredEventCount(ctx, search, startDate, endDate)
+func (uc *SESUsecase) GetFilteredEventCount(ctx context.Context, filter sesevent.Filter) (int, error) {
+	return uc.repo.GetFilteredEventCount(ctx, filter)
 }
 
-func (uc *SESUsecase) GetEventCount(ctx context.Context) (int, error) {
-	return uc.repo.GetEventCount(ctx)
+// GetEventPage returns the page of filtered events at cursor, which is nil
+// for the first page
+func (uc *SESUsecase) GetEventPage(ctx context.Context, filter sesevent.Filter, cursor *sesevent.Cursor, limit int) (*sesevent.EventPage, error) {
+	// One extra row tells whether another page follows
+	events, err := uc.repo.GetEventsByCursor(ctx, filter, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return sesevent.NewEventPage(events, cursor, limit), nil
+}
+
+// ForEachEvent calls fn with every filtered event, newest first
+func (uc *SESUsecase) ForEachEvent(ctx context.Context, filter sesevent.Filter, fn func(*sesevent.Event) error) error {
+	return uc.repo.ForEachEvent(ctx, filter, fn)
+}
+
+func (uc *SESUsecase) EstimateEventCount(ctx context.Context, filter sesevent.Filter) (int, error) {
+	return uc.repo.EstimateEventCount(ctx, filter)
+}
+
+func (uc *SESUsecase) GetEventsAfterID(ctx context.Context, filter sesevent.Filter, afterID int64, exclude []int64, limit int) ([]*sesevent.Event, error) {
+	return uc.repo.GetEventsAfterID(ctx, filter, afterID, exclude, limit)
+}
+
+func (uc *SESUsecase) GetEventsByMessageID(ctx context.Context, messageID string) ([]*sesevent.Event, error) {
+	return uc.repo.GetEventsByMessageID(ctx, messageID)
+}
+
+func (uc *SESUsecase) GetLatestEventID(ctx context.Context) (int64, error) {
+	return uc.repo.GetLatestEventID(ctx)
+}
+
+func (uc *SESUsecase) GetFirstRecentEventID(ctx context.Context, window time.Duration) (int64, error) {
+	return uc.repo.GetFirstRecentEventID(ctx, window)
+}
+
+func (uc *SESUsecase) GetEventCount(ctx context.Context, provider string) (int, error) {
+	return uc.repo.GetEventCount(ctx, provider)
 }
 
 func (uc *SESUsecase) GetEventsByType(ctx context.Context, eventType string) ([]*sesevent.Event, error) {
 	return uc.repo.GetEventsByType(ctx, eventType)
 }
 
-func (uc *SESUsecase) GetBounceRate(ctx context.Context) (float64, error) {
-	return uc.repo.GetBounceRate(ctx)
+func (uc *SESUsecase) GetBounceRate(ctx context.Context, provider string) (float64, error) {
+	return uc.repo.GetBounceRate(ctx, provider)
+}
+
+func (uc *SESUsecase) GetDeliveryRate(ctx context.Context, provider string) (float64, error) {
+	return uc.repo.GetDeliveryRate(ctx, provider)
 }
 
-func (uc *SESUsecase) GetDeliveryRate(ctx context.Context) (float64, error) {
-	return uc.repo.GetDeliveryRate(ctx)
+func (uc *SESUsecase) GetDailyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.DailyMetrics, error) {
+	return uc.repo.GetDailyMetrics(ctx, start, end, provider)
 }
 
-func (uc *SESUsecase) GetDailyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.DailyMetrics, error) {
-	return uc.repo.GetDailyMetrics(ctx, start, end)
+func (uc *SESUsecase) GetMonthlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.MonthlyMetrics, error) {
+	return uc.repo.GetMonthlyMetrics(ctx, start, end, provider)
 }
 
-func (uc *SESUsecase) GetMonthlyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.MonthlyMetrics, error) {
-	return uc.repo.GetMonthlyMetrics(ctx, start, end)
+func (uc *SESUsecase) GetHourlyMetrics(ctx context.Context, start, end *time.Time, provider string) ([]*sesevent.HourlyMetrics, error) {
+	return uc.repo.GetHourlyMetrics(ctx, start, end, provider)
 }
 
-func (uc *SESUsecase) GetHourlyMetrics(ctx context.Context, start, end *time.Time) ([]*sesevent.HourlyMetrics, error) {
-	return uc.repo.GetHourlyMetrics(ctx, start, end)
+func (uc *SESUsecase) GetEventTypeCounts(ctx context.Context, provider string) (map[string]int, error) {
+	return uc.repo.GetEventTypeCounts(ctx, provider)
 }
 
-func (uc *SESUsecase) GetEventTypeCounts(ctx context.Context) (map[string]int, error) {
-	return uc.repo.GetEventTypeCounts(ctx)
+func (uc *SESUsecase) GetTopClickedLinks(ctx context.Context, start, end *time.Time, provider string, limit int) ([]*sesevent.LinkClickStats, error) {
+	return uc.repo.GetTopClickedLinks(ctx, start, end, provider, limit)
 }
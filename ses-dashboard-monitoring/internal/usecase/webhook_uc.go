@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"ses-monitoring/internal/domain/sesevent"
+	"ses-monitoring/internal/domain/webhook"
+)
+
+// ErrInvalidWebhook is returned when a subscription fails validation
+var ErrInvalidWebhook = errors.New("invalid webhook subscription")
+
+// webhookCacheTTL bounds how long subscription changes made by the dispatcher,
+// such as automatic disabling, take to reach the enqueue path
+const webhookCacheTTL = 30 * time.Second
+
+type WebhookUsecase struct {
+	repo webhook.Repository
+
+	cacheMu       sync.RWMutex
+	cache         []*webhook.Subscription
+	cacheLoadedAt time.Time
+}
+
+func NewWebhookUsecase(repo webhook.Repository) *WebhookUsecase {
+	return &WebhookUsecase{repo: repo}
+}
+
+// Create validates and stores a subscription. A secret is generated when none is given.
+func (uc *WebhookUsecase) Create(ctx context.Context, s *webhook.Subscription) error {
+	if err := validateWebhook(s); err != nil {
+		return err
+	}
+	if s.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		s.Secret = hex.EncodeToString(secret)
+	}
+	if err := uc.repo.CreateSubscription(ctx, s); err != nil {
+		return err
+	}
+	uc.invalidateCache()
+	return nil
+}
+
+func (uc *WebhookUsecase) Get(ctx context.Context, id int64) (*webhook.Subscription, error) {
+	return uc.repo.GetSubscription(ctx, id)
+}
+
+func (uc *WebhookUsecase) List(ctx context.Context) ([]*webhook.Subscription, error) {
+	return uc.repo.ListSubscriptions(ctx)
+}
+
+// Update saves a subscription. Re-enabling a subscription clears its failure count.
+func (uc *WebhookUsecase) Update(ctx context.Context, s *webhook.Subscription) error {
+	if err := validateWebhook(s); err != nil {
+		return err
+	}
+	if err := uc.repo.UpdateSubscription(ctx, s); err != nil {
+		return err
+	}
+	uc.invalidateCache()
+	return nil
+}
+
+func (uc *WebhookUsecase) Delete(ctx context.Context, id int64) error {
+	if err := uc.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	uc.invalidateCache()
+	return nil
+}
+
+func (uc *WebhookUsecase) ListDeliveries(ctx context.Context, subscriptionID int64, status webhook.DeliveryStatus, limit, offset int) ([]*webhook.Delivery, int, error) {
+	if _, err := uc.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, 0, err
+	}
+	deliveries, err := uc.repo.ListDeliveries(ctx, subscriptionID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := uc.repo.CountDeliveries(ctx, subscriptionID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// RetryDelivery schedules a delivery of the subscription for an immediate attempt
+func (uc *WebhookUsecase) RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) error {
+	delivery, err := uc.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return webhook.ErrDeliveryNotFound
+	}
+	return uc.repo.RetryDelivery(ctx, deliveryID)
+}
+
+// EnqueueEvents queues newly stored events for every enabled subscription
+// they match. It is registered as a stored-events hook; failures are logged
+// because the events themselves are already stored.
+func (uc *WebhookUsecase) EnqueueEvents(ctx context.Context, events []*sesevent.Event) {
+	subscriptions, err := uc.enabledSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Failed to load webhook subscriptions: %v", err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	var deliveries []*webhook.Delivery
+	for _, event := range events {
+		var payload []byte
+		for _, s := range subscriptions {
+			if !s.Matches(event) {
+				continue
+			}
+			if payload == nil {
+				payload, err = json.Marshal(webhook.Payload{
+					EventKey:  event.EventKey,
+					EventType: event.EventType,
+					Provider:  event.Provider,
+					Event:     event,
+				})
+				if err != nil {
+					log.Printf("Failed to encode webhook payload for event %s: %v", event.EventKey, err)
+					break
+				}
+			}
+			deliveries = append(deliveries, &webhook.Delivery{
+				SubscriptionID: s.ID,
+				EventKey:       event.EventKey,
+				EventType:      event.EventType,
+				Payload:        string(payload),
+			})
+		}
+	}
+
+	if _, err := uc.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		log.Printf("Failed to queue %d webhook deliveries: %v", len(deliveries), err)
+	}
+}
+
+func (uc *WebhookUsecase) enabledSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	uc.cacheMu.RLock()
+	cached, loadedAt := uc.cache, uc.cacheLoadedAt
+	uc.cacheMu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < webhookCacheTTL {
+		return cached, nil
+	}
+
+	all, err := uc.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make([]*webhook.Subscription, 0, len(all))
+	for _, s := range all {
+		if s.Enabled {
+			enabled = append(enabled, s)
+		}
+	}
+
+	uc.cacheMu.Lock()
+	uc.cache = enabled
+	uc.cacheLoadedAt = time.Now()
+	uc.cacheMu.Unlock()
+	return enabled, nil
+}
+
+func (uc *WebhookUsecase) invalidateCache() {
+	uc.cacheMu.Lock()
+	uc.cacheLoadedAt = time.Time{}
+	uc.cacheMu.Unlock()
+}
+
+func validateWebhook(s *webhook.Subscription) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhook)
+	}
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	return nil
+}